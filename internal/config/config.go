@@ -5,9 +5,12 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // DatabaseConfig holds database connection configuration
@@ -18,12 +21,27 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+
+	// Pool monitor: sample interval and the InUse/MaxOpen ratio that triggers a warning
+	PoolSampleInterval  time.Duration
+	PoolInUseAlertRatio float64
+
+	// Server-side limit on any single statement (default 15s; 0 leaves the server
+	// setting alone) and the longer budget for admin and background jobs (default 5m)
+	StatementTimeout      time.Duration
+	AdminStatementTimeout time.Duration
 }
 
-// ConnectionString generates a PostgreSQL connection string from the database config
+// ConnectionString generates a PostgreSQL connection string from the database config.
+// The statement timeout is sent as a startup parameter so it applies to every pooled
+// connection.
 func (db DatabaseConfig) ConnectionString() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+	conn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		db.Host, db.Port, db.User, db.Password, db.Name, db.SSLMode)
+	if db.StatementTimeout > 0 {
+		conn += fmt.Sprintf(" statement_timeout=%d", db.StatementTimeout.Milliseconds())
+	}
+	return conn
 }
 
 // R2Config holds Cloudflare R2 configuration
@@ -35,12 +53,153 @@ type R2Config struct {
 	PublicURL  string
 }
 
+// UploadConfig holds per-kind upload size limits in bytes and the most images a
+// multi-image post may have. MaxThumbnailSize covers profile images and thumbnails;
+// MaxImageSize covers the other images.
+type UploadConfig struct {
+	MaxImageSize     int64
+	MaxThumbnailSize int64
+	MaxVideoSize     int64
+	MaxImagesPerPost int
+}
+
+// CDNConfig selects the CDN whose cache is purged when media URLs change.
+// Provider is "none" (default) or "cloudflare". ImageWidths are the widths clients may
+// request for CDN image transforms; DefaultImageWidth is used when they don't ask.
+type CDNConfig struct {
+	Provider           string
+	CloudflareZoneID   string
+	CloudflareAPIToken string
+	ImageWidths        []int
+	DefaultImageWidth  int
+}
+
+// ModerationConfig drives the default word-list content moderator. Action is "flag"
+// (store and queue for admin review) or "reject" (refuse the post or comment).
+type ModerationConfig struct {
+	Action       string
+	BlockedTerms []string
+}
+
+// DuplicateUploadConfig decides what happens when a new post's media matches an active
+// video. Action is "flag" (queue for review), "reject" or "off". Scope is "any" (match
+// every user's videos) or "user" (only the uploader's own, so reposts of someone else's
+// media are allowed).
+type DuplicateUploadConfig struct {
+	Action string
+	Scope  string
+}
+
+// SearchSafetyRule maps search queries matching Pattern (a case-insensitive regular
+// expression) to a response policy: "block" (no results), "warn" (results plus a notice)
+// or "redirect" (no results, point the user at ResourceURL instead)
+type SearchSafetyRule struct {
+	Pattern     string `json:"pattern"`
+	Action      string `json:"action"`
+	Message     string `json:"message"`
+	ResourceURL string `json:"resourceUrl"`
+}
+
+// RateLimitRule allows Limit requests per client within Window
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitConfig holds the fallback rule and per-route rules keyed by path pattern.
+// In a pattern "*" matches one path segment, and a trailing "/*" matches the rest of the
+// path, so "/api/v1/videos/*" covers every route under /videos.
+type RateLimitConfig struct {
+	Default RateLimitRule
+	Routes  map[string]RateLimitRule
+}
+
+// LoggingConfig controls the access log. Successful (<400) requests are logged 1-in-
+// SuccessSampleRate; errors are always logged. Query params and headers whose names are
+// in RedactKeys (case-insensitive) are logged as "[REDACTED]".
+type LoggingConfig struct {
+	SuccessSampleRate int
+	Headers           []string
+	RedactKeys        []string
+}
+
+// WalletConfig holds ledger retention settings. Transactions older than
+// ArchiveAfterMonths are moved to wallet_transactions_archive; 0 disables archival.
+type WalletConfig struct {
+	ArchiveAfterMonths int
+	ArchiveInterval    time.Duration
+	ArchiveBatchSize   int
+}
+
+// TrendingConfig controls the precomputed trending ranking. The top Size video IDs are
+// re-ranked every RefreshInterval; 0 disables precomputation and every request runs the
+// live scoring query.
+type TrendingConfig struct {
+	RefreshInterval time.Duration
+	Size            int
+}
+
+// WebhookConfig controls outbound webhook delivery. Due deliveries are sent every
+// DeliveryInterval (0 disables sending; events are still queued) and a delivery is
+// marked failed after MaxAttempts tries with exponential backoff.
+type WebhookConfig struct {
+	DeliveryInterval time.Duration
+	BatchSize        int
+	MaxAttempts      int
+	Timeout          time.Duration
+}
+
+// CacheTTLConfig holds the Cache-Control max-age of each kind of video response. List,
+// detail and comment responses advertise the same value in their "ttl" field.
+// CommentsViewer applies to signed-in comment reads, which carry per-viewer isLiked and
+// are cached privately.
+type CacheTTLConfig struct {
+	VideoStream    time.Duration
+	VideoDetail    time.Duration
+	VideoList      time.Duration
+	Comments       time.Duration
+	CommentsViewer time.Duration
+}
+
+// ActionThrottleConfig caps how many likes, follows and comments a single user can make
+// per Window. A limit of 0 leaves that action unthrottled.
+type ActionThrottleConfig struct {
+	Window   time.Duration
+	Likes    int
+	Follows  int
+	Comments int
+}
+
+// RewardsConfig holds coin amounts and thresholds for engagement milestone rewards.
+// Setting a coin amount to 0 disables that milestone.
+//
+// The daily claim pays DailyCoins plus DailyStreakBonus for every consecutive day after
+// the first, up to DailyStreakCap days. Days are calendar days in DailyTimezone.
+type RewardsConfig struct {
+	FirstPostCoins      int
+	FollowersThreshold  int
+	FollowersCoins      int
+	VideoViewsThreshold int
+	VideoViewsCoins     int
+
+	DailyCoins       int
+	DailyStreakBonus int
+	DailyStreakCap   int
+	DailyTimezone    string
+}
+
 // Config holds all application configuration
 type Config struct {
 	// Server configuration
 	Environment string
 	Port        string
 
+	// How long shutdown waits for in-flight requests to finish
+	ShutdownTimeout time.Duration
+
+	// Region: ISO country assumed for phone numbers without a country code
+	DefaultCountry string
+
 	// Database configuration
 	Database DatabaseConfig
 
@@ -51,11 +210,70 @@ type Config struct {
 	// R2 Storage configuration
 	R2Config R2Config
 
+	// Upload limits
+	Upload UploadConfig
+
+	// CDN cache purging
+	CDN CDNConfig
+
+	// Caption and comment moderation
+	Moderation ModerationConfig
+
+	// Handling of posts whose media was already posted
+	DuplicateUploads DuplicateUploadConfig
+
+	// Sensitive search queries and how to answer them
+	SearchSafetyRules []SearchSafetyRule
+
+	// Per-route request rate limits
+	RateLimits RateLimitConfig
+
+	// Per-user limits on likes, follows and comments
+	ActionThrottles ActionThrottleConfig
+
+	// Maximum simultaneous in-flight requests per signed-in user, or per IP for anonymous
+	// clients; streams and websockets are not counted. 0 disables the limit.
+	MaxConcurrentRequestsPerClient int
+
+	// How long public profile responses are cached in memory; 0 disables the cache
+	ProfileCacheTTL time.Duration
+
+	// Access log sampling and redaction
+	Logging LoggingConfig
+
 	// CORS configuration
 	AllowedOrigins []string
 
 	// Security
 	JWTSecret string
+
+	// Bearer token a metrics scraper must send to GET /metrics; empty disables the endpoint
+	MetricsToken string
+
+	// Engagement rewards
+	Rewards RewardsConfig
+
+	// Wallet ledger retention
+	Wallet WalletConfig
+
+	// Trending feed precomputation
+	Trending TrendingConfig
+
+	// Whether follower/following counts skip deactivated accounts (restored on reactivation)
+	ExcludeInactiveFollows bool
+
+	// HTTP cache lifetimes for video API responses
+	CacheTTLs CacheTTLConfig
+
+	// Fewest characters a search query may have after trimming
+	MinSearchQueryLength int
+
+	// Outbound webhooks for gift and purchase events
+	Webhooks WebhookConfig
+
+	// How often denormalized like/comment/follower/video counts are checked against their
+	// source tables; 0 disables the job
+	CountReconcileInterval time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -63,9 +281,12 @@ func Load() (*Config, error) {
 	config := &Config{
 		Environment:         getEnv("GIN_MODE", "debug"),
 		Port:                getEnv("PORT", "8080"),
+		ShutdownTimeout:     getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		DefaultCountry:      strings.ToUpper(getEnv("DEFAULT_COUNTRY", "KE")),
 		FirebaseProjectID:   getEnv("FIREBASE_PROJECT_ID", ""),
 		FirebaseCredentials: getEnv("FIREBASE_CREDENTIALS", ""),
 		JWTSecret:           getEnv("JWT_SECRET", "your-secret-key"),
+		MetricsToken:        getEnv("METRICS_TOKEN", ""),
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", ""),
 			Port:     getEnv("DB_PORT", "25060"),
@@ -73,6 +294,12 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", ""),
 			Name:     getEnv("DB_NAME", "defaultdb"),
 			SSLMode:  getEnv("DB_SSLMODE", "require"),
+
+			PoolSampleInterval:  getEnvDuration("DB_POOL_SAMPLE_INTERVAL", 30*time.Second),
+			PoolInUseAlertRatio: getEnvFloat("DB_POOL_INUSE_ALERT_RATIO", 0.8),
+
+			StatementTimeout:      getEnvDuration("DB_STATEMENT_TIMEOUT", 15*time.Second),
+			AdminStatementTimeout: getEnvDuration("DB_ADMIN_STATEMENT_TIMEOUT", 5*time.Minute),
 		},
 		R2Config: R2Config{
 			AccountID:  getEnv("R2_ACCOUNT_ID", ""),
@@ -81,8 +308,103 @@ func Load() (*Config, error) {
 			BucketName: getEnv("R2_BUCKET_NAME", "weibaomedia"),
 			PublicURL:  getEnv("R2_PUBLIC_URL", "https://pub-5e8ab62547db4f58851382161d280c19.r2.dev"),
 		},
+		Upload: UploadConfig{
+			MaxImageSize:     int64(getEnvInt("UPLOAD_MAX_IMAGE_MB", 10)) * 1024 * 1024,
+			MaxThumbnailSize: int64(getEnvInt("UPLOAD_MAX_THUMBNAIL_MB", 5)) * 1024 * 1024,
+			MaxVideoSize:     int64(getEnvInt("UPLOAD_MAX_VIDEO_MB", 1024)) * 1024 * 1024,
+			MaxImagesPerPost: getEnvInt("UPLOAD_MAX_IMAGES_PER_POST", 10),
+		},
+		CDN: CDNConfig{
+			Provider:           getEnv("CDN_PROVIDER", "none"),
+			CloudflareZoneID:   getEnv("CLOUDFLARE_ZONE_ID", ""),
+			CloudflareAPIToken: getEnv("CLOUDFLARE_API_TOKEN", ""),
+			ImageWidths:        getEnvIntList("CDN_IMAGE_WIDTHS", []int{160, 320, 640, 1080}),
+			DefaultImageWidth:  getEnvInt("CDN_DEFAULT_IMAGE_WIDTH", 640),
+		},
+		Moderation: ModerationConfig{
+			Action:       getEnv("CONTENT_MODERATION_ACTION", "flag"),
+			BlockedTerms: getEnvList("CONTENT_MODERATION_TERMS", defaultBlockedTerms),
+		},
+		DuplicateUploads: DuplicateUploadConfig{
+			Action: strings.ToLower(getEnv("DUPLICATE_UPLOAD_ACTION", "flag")),
+			Scope:  strings.ToLower(getEnv("DUPLICATE_UPLOAD_SCOPE", "any")),
+		},
+		Logging: LoggingConfig{
+			SuccessSampleRate: getEnvInt("LOG_SUCCESS_SAMPLE_RATE", 10),
+			Headers:           getEnvList("LOG_HEADERS", []string{"User-Agent"}),
+			RedactKeys:        getEnvList("LOG_REDACT_KEYS", defaultLogRedactKeys),
+		},
+		Rewards: RewardsConfig{
+			FirstPostCoins:      getEnvInt("REWARD_FIRST_POST_COINS", 10),
+			FollowersThreshold:  getEnvInt("REWARD_FOLLOWERS_THRESHOLD", 100),
+			FollowersCoins:      getEnvInt("REWARD_FOLLOWERS_COINS", 50),
+			VideoViewsThreshold: getEnvInt("REWARD_VIDEO_VIEWS_THRESHOLD", 1000),
+			VideoViewsCoins:     getEnvInt("REWARD_VIDEO_VIEWS_COINS", 25),
+			DailyCoins:          getEnvInt("REWARD_DAILY_COINS", 5),
+			DailyStreakBonus:    getEnvInt("REWARD_DAILY_STREAK_BONUS", 2),
+			DailyStreakCap:      getEnvInt("REWARD_DAILY_STREAK_CAP", 7),
+			DailyTimezone:       getEnv("REWARD_DAILY_TIMEZONE", "Africa/Nairobi"),
+		},
+		Wallet: WalletConfig{
+			ArchiveAfterMonths: getEnvInt("WALLET_TX_ARCHIVE_AFTER_MONTHS", 12),
+			ArchiveInterval:    getEnvDuration("WALLET_TX_ARCHIVE_INTERVAL", 24*time.Hour),
+			ArchiveBatchSize:   getEnvInt("WALLET_TX_ARCHIVE_BATCH_SIZE", 5000),
+		},
+		Trending: TrendingConfig{
+			RefreshInterval: getEnvDuration("TRENDING_REFRESH_INTERVAL", time.Minute),
+			Size:            getEnvInt("TRENDING_CACHE_SIZE", 500),
+		},
+		CacheTTLs: CacheTTLConfig{
+			VideoStream:    getEnvDuration("CACHE_TTL_VIDEO_STREAM", time.Hour),
+			VideoDetail:    getEnvDuration("CACHE_TTL_VIDEO_DETAIL", 30*time.Minute),
+			VideoList:      getEnvDuration("CACHE_TTL_VIDEO_LIST", 15*time.Minute),
+			Comments:       getEnvDuration("CACHE_TTL_COMMENTS", 5*time.Minute),
+			CommentsViewer: getEnvDuration("CACHE_TTL_COMMENTS_VIEWER", time.Minute),
+		},
+		Webhooks: WebhookConfig{
+			DeliveryInterval: getEnvDuration("WEBHOOK_DELIVERY_INTERVAL", 10*time.Second),
+			BatchSize:        getEnvInt("WEBHOOK_DELIVERY_BATCH_SIZE", 50),
+			MaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			Timeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+		ActionThrottles: ActionThrottleConfig{
+			Window:   getEnvDuration("ACTION_THROTTLE_WINDOW", time.Minute),
+			Likes:    getEnvInt("ACTION_THROTTLE_LIKES", 60),
+			Follows:  getEnvInt("ACTION_THROTTLE_FOLLOWS", 30),
+			Comments: getEnvInt("ACTION_THROTTLE_COMMENTS", 10),
+		},
+		MinSearchQueryLength:           getEnvInt("SEARCH_MIN_QUERY_LENGTH", 2),
+		ExcludeInactiveFollows:         getEnvBool("FOLLOW_COUNTS_EXCLUDE_INACTIVE", true),
+		CountReconcileInterval:         getEnvDuration("COUNT_RECONCILE_INTERVAL", 6*time.Hour),
+		MaxConcurrentRequestsPerClient: getEnvInt("MAX_CONCURRENT_REQUESTS_PER_CLIENT", 20),
+		ProfileCacheTTL:                getEnvDuration("PROFILE_CACHE_TTL", 30*time.Second),
+	}
+
+	// Search safety rules come as a JSON array so trust-and-safety can change them per deploy
+	config.SearchSafetyRules = defaultSearchSafetyRules
+	if rules := os.Getenv("SEARCH_SAFETY_RULES"); rules != "" {
+		if err := json.Unmarshal([]byte(rules), &config.SearchSafetyRules); err != nil {
+			return nil, ConfigError{Message: "SEARCH_SAFETY_RULES must be a JSON array of {pattern, action, message, resourceUrl}: " + err.Error()}
+		}
 	}
 
+	switch config.DuplicateUploads.Action {
+	case "flag", "reject", "off":
+	default:
+		return nil, ConfigError{Message: "DUPLICATE_UPLOAD_ACTION must be flag, reject or off"}
+	}
+	switch config.DuplicateUploads.Scope {
+	case "any", "user":
+	default:
+		return nil, ConfigError{Message: "DUPLICATE_UPLOAD_SCOPE must be any or user"}
+	}
+
+	rateLimits, err := loadRateLimits()
+	if err != nil {
+		return nil, err
+	}
+	config.RateLimits = rateLimits
+
 	// Parse allowed origins
 	originsStr := getEnv("ALLOWED_ORIGINS", "http://localhost:3000,https://yourdomain.com")
 	config.AllowedOrigins = strings.Split(originsStr, ",")
@@ -115,6 +437,164 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt gets an integer environment variable with a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets a float environment variable with a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a trimmed list with a default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvIntList reads a comma-separated list of integers, falling back to the default
+// if any entry isn't a number
+func getEnvIntList(key string, defaultValue []int) []int {
+	var list []int
+	for _, item := range getEnvList(key, nil) {
+		parsed, err := strconv.Atoi(item)
+		if err != nil {
+			return defaultValue
+		}
+		list = append(list, parsed)
+	}
+	if len(list) == 0 {
+		return defaultValue
+	}
+	return list
+}
+
+// getEnvBool gets a boolean environment variable ("true", "false", "1", "0") with a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s") with a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// defaultBlockedTerms seeds the word-list moderator when CONTENT_MODERATION_TERMS is unset
+var defaultBlockedTerms = []string{
+	"fuck", "shit", "bitch", "cunt", "nigger", "faggot",
+	"free coins", "double your money", "send money to", "click the link in bio",
+}
+
+// defaultRouteRateLimits apply unless overridden by RATE_LIMITS
+var defaultRouteRateLimits = map[string]RateLimitRule{
+	// Chat exports are heavy; tracked separately from regular traffic
+	"/api/v1/video-reactions/chats/*/export": {Limit: 10, Window: time.Hour},
+	// Strict bucket to stop phone number enumeration
+	"/api/v1/auth/check-phone": {Limit: 10, Window: 10 * time.Minute},
+	"/api/v1/videos/bulk":      {Limit: 30, Window: time.Minute},
+	"/api/v1/videos/search":    {Limit: 100, Window: time.Minute},
+	"/api/v1/videos":           {Limit: 100, Window: time.Minute},
+	"/api/v1/videos/featured":  {Limit: 100, Window: time.Minute},
+	"/api/v1/videos/trending":  {Limit: 100, Window: time.Minute},
+}
+
+// loadRateLimits builds the rate limit config. RATE_LIMITS is a JSON object of
+// pattern -> {"limit": 100, "window": "1m"} merged over defaultRouteRateLimits.
+func loadRateLimits() (RateLimitConfig, error) {
+	limits := RateLimitConfig{
+		Default: RateLimitRule{
+			Limit:  getEnvInt("RATE_LIMIT_DEFAULT", 200),
+			Window: getEnvDuration("RATE_LIMIT_DEFAULT_WINDOW", time.Minute),
+		},
+		Routes: make(map[string]RateLimitRule, len(defaultRouteRateLimits)),
+	}
+	for pattern, rule := range defaultRouteRateLimits {
+		limits.Routes[pattern] = rule
+	}
+
+	if value := os.Getenv("RATE_LIMITS"); value != "" {
+		var overrides map[string]struct {
+			Limit  int    `json:"limit"`
+			Window string `json:"window"`
+		}
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return limits, ConfigError{Message: "RATE_LIMITS must be a JSON object of pattern -> {limit, window}: " + err.Error()}
+		}
+		for pattern, override := range overrides {
+			window, err := time.ParseDuration(override.Window)
+			if err != nil {
+				return limits, ConfigError{Message: fmt.Sprintf("RATE_LIMITS %q: invalid window %q", pattern, override.Window)}
+			}
+			limits.Routes[pattern] = RateLimitRule{Limit: override.Limit, Window: window}
+		}
+	}
+
+	for pattern, rule := range limits.Routes {
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			return limits, ConfigError{Message: fmt.Sprintf("RATE_LIMITS %q: limit and window must be positive", pattern)}
+		}
+	}
+	if limits.Default.Limit <= 0 || limits.Default.Window <= 0 {
+		return limits, ConfigError{Message: "RATE_LIMIT_DEFAULT and RATE_LIMIT_DEFAULT_WINDOW must be positive"}
+	}
+
+	return limits, nil
+}
+
+// defaultLogRedactKeys are query params and headers never written to the access log:
+// credentials, plus search text and phone numbers which are personal data
+var defaultLogRedactKeys = []string{
+	"Authorization", "Cookie", "X-Api-Key",
+	"token", "access_token", "id_token", "api_key", "key", "password", "secret",
+	"q", "query", "phone", "phoneNumber",
+}
+
+// defaultSearchSafetyRules is used when SEARCH_SAFETY_RULES is unset
+var defaultSearchSafetyRules = []SearchSafetyRule{
+	{
+		Pattern:     `\b(suicide|kill myself|self[ -]?harm|want to die)\b`,
+		Action:      "redirect",
+		Message:     "You're not alone. If you're going through a difficult time, support is available.",
+		ResourceURL: "https://findahelpline.com",
+	},
+	{
+		Pattern: `\b(child porn|cp links|underage nudes?)\b`,
+		Action:  "block",
+		Message: "This search isn't allowed.",
+	},
+}
+
 // Configuration errors
 var (
 	ErrMissingDatabaseConfig = ConfigError{Message: "Database configuration (DB_HOST, DB_USER, DB_PASSWORD, DB_NAME) is required"}
@@ -1448,6 +1448,747 @@ func RunMigrations(db *sqlx.DB) error {
 		-- Add comment to document the change
 		COMMENT ON TRIGGER trigger_check_user_is_active_for_video ON videos IS 
 		'Validates that user account is active before allowing video creation. All active authenticated users can post videos regardless of role.';
+	`,
+		},
+		{
+			Version: "016_idempotency_keys",
+			Query: `
+		-- ===============================
+		-- 🔁 IDEMPOTENCY KEYS FOR RETRY-SAFE WRITES
+		-- ===============================
+
+		-- Stores the response of a processed request per (user, key) so client
+		-- retries replay the original response instead of charging twice
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id VARCHAR(255) NOT NULL,
+			idempotency_key VARCHAR(255) NOT NULL,
+			request_method VARCHAR(10) NOT NULL,
+			request_path TEXT NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response_body TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, idempotency_key)
+		);
+
+		-- Expiry sweeps
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at 
+		ON idempotency_keys(created_at);
+
+		COMMENT ON TABLE idempotency_keys IS 'Processed Idempotency-Key responses, replayed to retries for 24 hours (status_code 0 = in progress)';
+	`,
+		},
+		{
+			Version: "017_blocked_contacts",
+			Query: `
+		-- ===============================
+		-- 🚫 USER BLOCKING
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS blocked_contacts (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			blocker_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			blocked_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(blocker_id, blocked_id),
+			CHECK(blocker_id != blocked_id)
+		);
+
+		-- Block list lookups (newest first) and reverse lookups for enforcement
+		CREATE INDEX IF NOT EXISTS idx_blocked_contacts_blocker 
+		ON blocked_contacts(blocker_id, created_at DESC);
+
+		CREATE INDEX IF NOT EXISTS idx_blocked_contacts_blocked 
+		ON blocked_contacts(blocked_id);
+
+		COMMENT ON TABLE blocked_contacts IS 'User-to-user blocks (blocker_id has blocked blocked_id)';
+	`,
+		},
+		{
+			Version: "019_saved_videos",
+			Query: `
+		-- ===============================
+		-- 🔖 SAVED / BOOKMARKED VIDEOS (private, separate from likes)
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS saved_videos (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(video_id, user_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_saved_videos_user_created 
+		ON saved_videos(user_id, created_at DESC);
+	`,
+		},
+		{
+			Version: "020_reward_grants",
+			Query: `
+		-- ===============================
+		-- 🎁 ENGAGEMENT MILESTONE REWARDS
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS reward_grants (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			milestone_key VARCHAR(100) NOT NULL,
+			reference_id VARCHAR(255),
+			coins INTEGER NOT NULL CHECK (coins > 0),
+			transaction_id VARCHAR(255),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, milestone_key)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_reward_grants_user_created 
+		ON reward_grants(user_id, created_at DESC);
+	`,
+		},
+		{
+			Version: "021_notifications_and_comment_mentions",
+			Query: `
+		-- ===============================
+		-- 🔔 NOTIFICATIONS
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS notifications (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			actor_id VARCHAR(255) REFERENCES users(uid) ON DELETE CASCADE,
+			type VARCHAR(50) NOT NULL,
+			entity_type VARCHAR(50),
+			entity_id VARCHAR(255),
+			message TEXT DEFAULT '',
+			data JSONB DEFAULT '{}',
+			is_read BOOLEAN DEFAULT false,
+			read_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_notifications_user_created 
+		ON notifications(user_id, created_at DESC);
+
+		CREATE INDEX IF NOT EXISTS idx_notifications_user_unread 
+		ON notifications(user_id, type) WHERE is_read = false;
+
+		-- UIDs mentioned in a comment so clients can render @links
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS mentions TEXT[] DEFAULT '{}';
+	`,
+		},
+		{
+			Version: "022_whatsapp_number_international_format",
+			Query: `
+		-- ===============================
+		-- 🌍 MULTI-COUNTRY WHATSAPP NUMBERS
+		-- ===============================
+		-- Country-specific formats are validated in the application; the database only
+		-- requires international digits (E.164 without the leading +)
+
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_whatsapp_number_format_check;
+
+		ALTER TABLE users ADD CONSTRAINT users_whatsapp_number_format_check
+		CHECK (whatsapp_number IS NULL OR whatsapp_number ~ '^[1-9][0-9]{7,14}$');
+	`,
+		},
+		{
+			Version: "023_admin_permissions",
+			Query: `
+		-- ===============================
+		-- 🛡️ ADMIN PERMISSIONS
+		-- ===============================
+		-- Admins only reach the routes whose permission they hold; super_admin covers all
+
+		CREATE TABLE IF NOT EXISTS admin_permissions (
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			permission VARCHAR(50) NOT NULL,
+			granted_by VARCHAR(255) REFERENCES users(uid) ON DELETE SET NULL,
+			granted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (user_id, permission),
+			CONSTRAINT admin_permissions_permission_check CHECK (permission IN (
+				'super_admin', 'moderate_content', 'manage_users', 'manage_wallet', 'view_reports'
+			))
+		);
+
+		-- Existing admins keep full access
+		INSERT INTO admin_permissions (user_id, permission)
+		SELECT uid, 'super_admin' FROM users
+		WHERE user_type = 'admin' OR role = 'admin'
+		ON CONFLICT DO NOTHING;
+	`,
+		},
+		{
+			Version: "024_video_purchases",
+			Query: `
+		-- ===============================
+		-- 💳 VIDEO PURCHASES (paid video entitlements)
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS video_purchases (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			price_paid DECIMAL(10,2) NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(user_id, video_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_video_purchases_video ON video_purchases(video_id);
+	`,
+		},
+		{
+			Version: "025_wallet_transactions_archive",
+			Query: `
+		-- ===============================
+		-- 🗄️ WALLET TRANSACTION ARCHIVE
+		-- ===============================
+		-- Old ledger rows are moved here by the archival job so the hot table stays small.
+		-- Columns mirror wallet_transactions; archived_at records when the row was moved.
+
+		CREATE TABLE IF NOT EXISTS wallet_transactions_archive (
+			LIKE wallet_transactions INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+		);
+
+		ALTER TABLE wallet_transactions_archive
+		ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.table_constraints
+						  WHERE constraint_name = 'wallet_transactions_archive_pkey'
+						  AND table_name = 'wallet_transactions_archive') THEN
+				ALTER TABLE wallet_transactions_archive ADD CONSTRAINT wallet_transactions_archive_pkey
+				PRIMARY KEY (transaction_id);
+			END IF;
+		END $$;
+
+		-- Same access paths on both tables: per-user history by date, type filters,
+		-- and the created_at range scan used by the archival job
+		CREATE INDEX IF NOT EXISTS idx_wallet_transactions_user_created
+		ON wallet_transactions(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_wallet_transactions_created_at
+		ON wallet_transactions(created_at);
+
+		CREATE INDEX IF NOT EXISTS idx_wallet_transactions_archive_user_created
+		ON wallet_transactions_archive(user_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_wallet_transactions_archive_type
+		ON wallet_transactions_archive(type);
+	`,
+		},
+		{
+			Version: "026_video_reports",
+			Query: `
+		-- ===============================
+		-- 🚩 VIDEO REPORTS (moderation queue)
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS video_reports (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			reporter_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			reason VARCHAR(50) NOT NULL,
+			description TEXT DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'open',
+			resolved_by VARCHAR(255) REFERENCES users(uid) ON DELETE SET NULL,
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			CONSTRAINT video_reports_status_check CHECK (status IN ('open', 'resolved', 'dismissed'))
+		);
+
+		-- One open report per user per video
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_video_reports_open_unique
+		ON video_reports(video_id, reporter_id) WHERE status = 'open';
+
+		CREATE INDEX IF NOT EXISTS idx_video_reports_open_video
+		ON video_reports(video_id, created_at DESC) WHERE status = 'open';
+
+		CREATE INDEX IF NOT EXISTS idx_videos_unverified_active
+		ON videos(created_at DESC) WHERE is_active = true AND is_verified = false;
+	`,
+		},
+		{
+			Version: "027_hidden_videos",
+			Query: `
+		-- ===============================
+		-- 🙈 HIDDEN VIDEOS ("not interested" feedback)
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS hidden_videos (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(video_id, user_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_hidden_videos_user_created 
+		ON hidden_videos(user_id, created_at DESC);
+	`,
+		},
+		{
+			Version: "028_video_watch_sessions",
+			Query: `
+		-- ===============================
+		-- ⏱️ WATCH TIME (completion rate feeds trending)
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS video_watch_sessions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) REFERENCES users(uid) ON DELETE SET NULL,
+			watched_ms BIGINT NOT NULL,
+			duration_ms BIGINT NOT NULL,
+			completion_rate DOUBLE PRECISION NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			CONSTRAINT video_watch_sessions_positive CHECK (watched_ms >= 0 AND duration_ms > 0),
+			CONSTRAINT video_watch_sessions_rate CHECK (completion_rate BETWEEN 0 AND 1)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_video_watch_sessions_video_created 
+		ON video_watch_sessions(video_id, created_at DESC);
+
+		-- Running aggregate so the trending query does not scan sessions
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS watch_sessions_count INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS avg_completion_rate DOUBLE PRECISION NOT NULL DEFAULT 0;
+	`,
+		},
+		{
+			Version: "029_content_moderation_flags",
+			Query: `
+		-- ===============================
+		-- 🧹 CONTENT MODERATION FLAGS (captions and comments)
+		-- ===============================
+
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS is_flagged BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS flag_reason TEXT NOT NULL DEFAULT '';
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS is_flagged BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS flag_reason TEXT NOT NULL DEFAULT '';
+
+		CREATE INDEX IF NOT EXISTS idx_videos_flagged 
+		ON videos(created_at DESC) WHERE is_flagged = true;
+
+		CREATE INDEX IF NOT EXISTS idx_comments_flagged 
+		ON comments(created_at DESC) WHERE is_flagged = true;
+	`,
+		},
+		{
+			Version: "030_admin_audit_log",
+			Query: `
+		-- ===============================
+		-- 📜 ADMIN AUDIT LOG
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS admin_audit_log (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			admin_id VARCHAR(255) NOT NULL,
+			action VARCHAR(100) NOT NULL,
+			target_type VARCHAR(50) NOT NULL,
+			target_id VARCHAR(255) NOT NULL DEFAULT '',
+			details JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created 
+		ON admin_audit_log(created_at DESC);
+
+		CREATE INDEX IF NOT EXISTS idx_admin_audit_log_admin_created 
+		ON admin_audit_log(admin_id, created_at DESC);
+
+		CREATE INDEX IF NOT EXISTS idx_admin_audit_log_action_created 
+		ON admin_audit_log(action, created_at DESC);
+	`,
+		},
+		{
+			Version: "031_gift_transactions_video_id",
+			Query: `
+		-- ===============================
+		-- 🎁 GIFTS ON VIDEOS
+		-- ===============================
+		-- gift_transactions is created alongside the gift system, so guard on it existing
+
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'gift_transactions') THEN
+				ALTER TABLE gift_transactions ADD COLUMN IF NOT EXISTS video_id UUID REFERENCES videos(id) ON DELETE SET NULL;
+
+				CREATE INDEX IF NOT EXISTS idx_gift_transactions_recipient_video_created
+				ON gift_transactions(recipient_id, created_at DESC) WHERE video_id IS NOT NULL;
+			END IF;
+		END $$;
+	`,
+		},
+		{
+			Version: "032_users_last_feed_seen_at",
+			Query: `
+		-- ===============================
+		-- 🔔 FOLLOWING FEED NEW-POSTS BADGE
+		-- ===============================
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS last_feed_seen_at TIMESTAMP WITH TIME ZONE;
+	`,
+		},
+		{
+			Version: "033_comment_replies_index",
+			Query: `
+		-- ===============================
+		-- 💬 COMMENT REPLY THREADS
+		-- ===============================
+
+		CREATE INDEX IF NOT EXISTS idx_comments_replied_to_created 
+		ON comments(replied_to_comment_id, created_at) WHERE replied_to_comment_id IS NOT NULL;
+	`,
+		},
+		{
+			Version: "034_user_uid_links",
+			Query: `
+		-- ===============================
+		-- 🔑 ACCOUNT RECOVERY UID LINKS
+		-- ===============================
+		-- A Firebase UID that signed in again with the phone number of an existing account
+		-- is linked to that account instead of creating a duplicate user
+
+		CREATE TABLE IF NOT EXISTS user_uid_links (
+			firebase_uid VARCHAR(255) PRIMARY KEY,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			phone_number VARCHAR(50) NOT NULL,
+			linked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_user_uid_links_user ON user_uid_links(user_id);
+	`,
+		},
+		{
+			Version: "035_users_phone_number_unique",
+			Query: `
+		-- ===============================
+		-- 📞 NORMALIZED PHONE NUMBERS
+		-- ===============================
+		-- Account phone numbers are stored in E.164 form. Legacy rows written as bare
+		-- international digits get their "+"; uniqueness is enforced by migration 048.
+		-- National-format rows ("07...") need the configured default country and are left
+		-- for the application to normalise on next sync.
+
+		UPDATE users
+		SET phone_number = '+' || regexp_replace(phone_number, '[^0-9]', '', 'g')
+		WHERE phone_number !~ '^\+'
+		  AND regexp_replace(phone_number, '[^0-9]', '', 'g') ~ '^[1-9][0-9]{9,14}$';
+	`,
+		},
+		{
+			Version: "036_self_deactivated_accounts",
+			Query: `
+		-- ===============================
+		-- 💤 SELF-DEACTIVATED ACCOUNTS
+		-- ===============================
+		-- self_deactivated_at marks accounts the owner switched off (as opposed to an admin
+		-- suspension) so signing in again can reactivate them. owner_deactivated marks the
+		-- videos hidden with the account so reactivation restores exactly those.
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS self_deactivated_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS owner_deactivated BOOLEAN NOT NULL DEFAULT false;
+
+		CREATE INDEX IF NOT EXISTS idx_videos_owner_deactivated
+			ON videos(user_id) WHERE owner_deactivated = true;
+	`,
+		},
+		{
+			Version: "037_videos_image_urls_count_check",
+			Query: `
+		-- ===============================
+		-- 🖼️ IMAGES PER POST CEILING
+		-- ===============================
+		-- Hard ceiling behind the configurable UPLOAD_MAX_IMAGES_PER_POST limit. NOT VALID
+		-- so existing oversized posts don't block the migration; new writes are checked.
+
+		DO $$
+		BEGIN
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.table_constraints 
+				WHERE constraint_name = 'videos_image_urls_count_check' 
+				AND table_name = 'videos'
+			) THEN
+				ALTER TABLE videos ADD CONSTRAINT videos_image_urls_count_check
+				CHECK (image_urls IS NULL OR cardinality(image_urls) <= 20) NOT VALID;
+			END IF;
+		END $$;
+	`,
+		},
+		{
+			Version: "038_video_daily_engagement",
+			Query: `
+		-- ===============================
+		-- 📅 DAILY VIEW AND SHARE BUCKETS
+		-- ===============================
+
+		-- views_count/shares_count are lifetime counters; likes and comments already carry
+		-- created_at, so only views and shares need per-day buckets for windowed trending
+		CREATE TABLE IF NOT EXISTS video_daily_engagement (
+			video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			views INTEGER NOT NULL DEFAULT 0,
+			shares INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (video_id, day)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_video_daily_engagement_day ON video_daily_engagement(day);
+		CREATE INDEX IF NOT EXISTS idx_video_likes_created_at ON video_likes(created_at);
+		CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments(created_at);
+	`,
+		},
+		{
+			Version: "039_message_reports",
+			Query: `
+		-- ===============================
+		-- 🚩 CHAT MESSAGE REPORTS
+		-- ===============================
+
+		-- No FK on message_id: the content snapshot must outlive the sender editing or
+		-- deleting the message for everyone
+		CREATE TABLE IF NOT EXISTS message_reports (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			message_id UUID NOT NULL,
+			chat_id VARCHAR(255) NOT NULL,
+			sender_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			reporter_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			reason VARCHAR(50) NOT NULL,
+			description TEXT DEFAULT '',
+			content TEXT NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'open',
+			resolved_by VARCHAR(255) REFERENCES users(uid) ON DELETE SET NULL,
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			CONSTRAINT message_reports_status_check CHECK (status IN ('open', 'resolved', 'dismissed'))
+		);
+
+		-- One open report per user per message
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_message_reports_open_unique
+		ON message_reports(message_id, reporter_id) WHERE status = 'open';
+
+		CREATE INDEX IF NOT EXISTS idx_message_reports_open_sender
+		ON message_reports(sender_id, created_at DESC) WHERE status = 'open';
+
+		-- Set when several different users report a sender's messages
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS messaging_flagged_at TIMESTAMP WITH TIME ZONE;
+	`,
+		},
+		{
+			Version: "040_webhooks",
+			Query: `
+		-- ===============================
+		-- 🪝 OUTBOUND WEBHOOKS
+		-- ===============================
+
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			url TEXT NOT NULL,
+			secret VARCHAR(255) NOT NULL,
+			events TEXT[] NOT NULL DEFAULT '{}',
+			description TEXT DEFAULT '',
+			is_active BOOLEAN NOT NULL DEFAULT true,
+			created_by VARCHAR(255) REFERENCES users(uid) ON DELETE SET NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhooks_events ON webhooks USING GIN(events) WHERE is_active = true;
+
+		-- One row per event per subscribed webhook. Rows are written in the same transaction
+		-- as the event, so a committed gift or purchase is never missed by the worker.
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+			event VARCHAR(50) NOT NULL,
+			payload JSONB NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			last_status_code INTEGER,
+			last_error TEXT,
+			delivered_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('pending', 'delivered', 'failed'))
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+		ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook
+		ON webhook_deliveries(webhook_id, created_at DESC);
+	`,
+		},
+		{
+			Version: "041_video_content_hash",
+			Query: `
+		-- ===============================
+		-- 🧬 VIDEO CONTENT HASH
+		-- ===============================
+
+		-- Identifies re-uploads of the same media so duplicate spam can be flagged or rejected
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS video_content_hash VARCHAR(128);
+
+		CREATE INDEX IF NOT EXISTS idx_videos_content_hash_active
+		ON videos(video_content_hash) WHERE is_active = true AND video_content_hash IS NOT NULL;
+	`,
+		},
+		{
+			Version: "042_private_accounts",
+			Query: `
+		-- ===============================
+		-- 🔒 PRIVATE ACCOUNTS & FOLLOW REQUESTS
+		-- ===============================
+
+		-- Following a private account needs the owner's approval, and its videos are
+		-- only listed for approved followers
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT false;
+
+		CREATE INDEX IF NOT EXISTS idx_users_private ON users(uid) WHERE is_private = true;
+
+		CREATE TABLE IF NOT EXISTS follow_requests (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			requester_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			target_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			responded_at TIMESTAMP WITH TIME ZONE,
+			CONSTRAINT follow_requests_status_check CHECK (status IN ('pending', 'approved', 'rejected')),
+			CHECK(requester_id != target_id)
+		);
+
+		-- One open request per pair; rejected requests may be sent again
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_follow_requests_pending_pair
+		ON follow_requests(requester_id, target_id) WHERE status = 'pending';
+
+		CREATE INDEX IF NOT EXISTS idx_follow_requests_target_pending
+		ON follow_requests(target_id, created_at DESC) WHERE status = 'pending';
+	`,
+		},
+		{
+			Version: "043_comment_sorting",
+			Query: `
+		-- ===============================
+		-- 💬 COMMENT SORTING
+		-- ===============================
+
+		-- Serves sortBy=top on a video's comments without sorting every row
+		CREATE INDEX IF NOT EXISTS idx_comments_video_top
+		ON comments(video_id, likes_count DESC, created_at DESC);
+	`,
+		},
+		{
+			Version: "044_pinned_comments",
+			Query: `
+		-- ===============================
+		-- 📌 PINNED COMMENTS
+		-- ===============================
+
+		-- A video owner can pin one top-level comment, shown above the others
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS is_pinned BOOLEAN NOT NULL DEFAULT false;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_comments_one_pin_per_video
+		ON comments(video_id) WHERE is_pinned = true;
+	`,
+		},
+		{
+			Version: "045_daily_claims",
+			Query: `
+		-- ===============================
+		-- 📅 DAILY CLAIM STREAKS
+		-- ===============================
+
+		-- One row per user per calendar day claimed; streak is the run of consecutive
+		-- days ending on claim_date
+		CREATE TABLE IF NOT EXISTS daily_claims (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id VARCHAR(255) NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+			claim_date DATE NOT NULL,
+			streak INTEGER NOT NULL CHECK (streak > 0),
+			coins INTEGER NOT NULL CHECK (coins > 0),
+			transaction_id VARCHAR(255),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, claim_date)
+		);
+	`,
+		},
+		{
+			Version: "046_admin_role_backfill",
+			Query: `
+		-- ===============================
+		-- 🛡️ ROLE IS THE ADMIN FLAG
+		-- ===============================
+		-- Legacy user_type = 'admin' accounts get the admin role so role changes and the
+		-- last-admin guard see them
+
+		UPDATE users SET role = 'admin', updated_at = NOW()
+		WHERE user_type = 'admin' AND role <> 'admin';
+	`,
+		},
+		{
+			Version: "047_video_duration",
+			Query: `
+		-- ===============================
+		-- ⏱️ STORED VIDEO DURATION
+		-- ===============================
+		-- Probed with ffprobe after upload; watch sessions use it instead of the
+		-- duration the client reports
+
+		ALTER TABLE videos ADD COLUMN IF NOT EXISTS duration_ms BIGINT;
+	`,
+		},
+		{
+			Version: "048_users_phone_number_unique_index",
+			Query: `
+		-- ===============================
+		-- 📞 UNIQUE PHONE NUMBERS
+		-- ===============================
+		-- Replaces the check migration 035 used to make, which only warned on duplicates
+		-- and was recorded as applied without the index. Duplicates now fail the migration,
+		-- listing the accounts involved, so it is retried on every start until they are
+		-- merged by hand.
+
+		DO $phone_unique$
+		DECLARE
+			duplicates TEXT;
+		BEGIN
+			SELECT string_agg(phone_number || ' (' || uids || ')', '; ')
+			INTO duplicates
+			FROM (
+				SELECT phone_number, string_agg(uid, ', ' ORDER BY created_at) AS uids
+				FROM users
+				WHERE phone_number <> ''
+				GROUP BY phone_number
+				HAVING COUNT(*) > 1
+				ORDER BY phone_number
+				LIMIT 50
+			) d;
+
+			IF duplicates IS NOT NULL THEN
+				RAISE EXCEPTION 'users.phone_number has duplicates; merge these accounts before idx_users_phone_number_unique can be created: %', duplicates;
+			END IF;
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_unique
+				ON users(phone_number) WHERE phone_number <> '';
+		END $phone_unique$;
+	`,
+		},
+		{
+			Version: "049_videos_tags_lowercase",
+			Query: `
+		-- ===============================
+		-- 🏷️ LOWERCASE VIDEO TAGS
+		-- ===============================
+		-- Tags are lowercased on write so tag pages can match with tags @> ARRAY[tag]
+		-- on the GIN index. Rows written before that are lowercased here, keeping the
+		-- first occurrence when two tags differ only by case.
+
+		UPDATE videos
+		SET tags = ARRAY(
+			SELECT d.tag FROM (
+				SELECT LOWER(u.tag) AS tag, MIN(u.ord) AS first_ord
+				FROM unnest(videos.tags) WITH ORDINALITY AS u(tag, ord)
+				GROUP BY LOWER(u.tag)
+			) d
+			ORDER BY d.first_ord
+		)
+		WHERE EXISTS (SELECT 1 FROM unnest(videos.tags) AS t(tag) WHERE t.tag <> LOWER(t.tag));
 	`,
 		},
 	}
@@ -1475,6 +2216,38 @@ func RunMigrations(db *sqlx.DB) error {
 	log.Println("   • 📨 Real-time messaging with read receipts")
 	log.Println("   • ⌨️  Typing indicators")
 	log.Println("   • 📌 Message pinning (up to 10 per chat)")
+	log.Println("   • 🔁 Idempotency keys for wallet and gift writes")
+	log.Println("   • 🚫 User blocking (blocked_contacts)")
+	log.Println("   • 🎁 Engagement milestone rewards (reward_grants)")
+	log.Println("   • 🔔 Notifications with comment @mentions")
+	log.Println("   • 🌍 WhatsApp numbers in international format (multi-country)")
+	log.Println("   • 🛡️ Granular admin permissions (admin_permissions)")
+	log.Println("   • 💳 Video purchases and range streaming for paid videos")
+	log.Println("   • 🗄️ Wallet transaction archive (wallet_transactions_archive)")
+	log.Println("   • 🚩 Video reports and admin moderation queue")
+	log.Println("   • 🙈 Hidden videos (not interested feedback)")
+	log.Println("   • ⏱️ Video watch sessions and completion rate")
+	log.Println("   • 🧹 Caption and comment moderation flags")
+	log.Println("   • 📜 Admin audit log")
+	log.Println("   • 🎁 Gifts linked to videos")
+	log.Println("   • 🔔 Following feed new-posts tracking")
+	log.Println("   • 💬 Comment reply thread index")
+	log.Println("   • 🔑 Account recovery UID links")
+	log.Println("   • 📞 Phone numbers normalised to E.164")
+	log.Println("   • 💤 Self-service account deactivation and reactivation")
+	log.Println("   • 🖼️ Images per post capped (configurable, hard ceiling of 20)")
+	log.Println("   • 📅 Daily view/share buckets for period trending")
+	log.Println("   • 🚩 Chat message reports")
+	log.Println("   • 🪝 Outbound webhooks")
+	log.Println("   • 🧬 Video content hashes for duplicate detection")
+	log.Println("   • 🔒 Private accounts with follow requests")
+	log.Println("   • 💬 Comment sorting: newest, oldest, top")
+	log.Println("   • 📌 Pinned comments (one per video)")
+	log.Println("   • 📅 Daily coin claims with streak bonus")
+	log.Println("   • 🛡️ Legacy user_type admins backfilled into role")
+	log.Println("   • ⏱️ Stored video duration for watch sessions")
+	log.Println("   • 📞 Unique account phone numbers enforced")
+	log.Println("   • 🏷️ Video tags lowercased for GIN tag lookups")
 	return nil
 }
 
@@ -1505,6 +2278,12 @@ func applyMigration(db *sqlx.DB, migration Migration) error {
 	}
 	defer tx.Rollback()
 
+	// The connection-level statement_timeout is sized for request traffic; index builds
+	// and backfills on a large table legitimately run longer
+	if _, err = tx.Exec("SET LOCAL statement_timeout = 0"); err != nil {
+		return fmt.Errorf("failed to lift statement timeout for migration %s: %w", migration.Version, err)
+	}
+
 	// Execute migration
 	_, err = tx.Exec(migration.Query)
 	if err != nil {
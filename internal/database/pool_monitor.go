@@ -0,0 +1,131 @@
+// ===============================
+// internal/database/pool_monitor.go - Connection pool sampler and metrics
+// ===============================
+
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PoolSample is the most recent connection pool reading
+type PoolSample struct {
+	SampledAt       time.Time     `json:"sampledAt"`
+	MaxOpen         int           `json:"maxOpen"`
+	Open            int           `json:"open"`
+	InUse           int           `json:"inUse"`
+	Idle            int           `json:"idle"`
+	WaitCount       int64         `json:"waitCount"`
+	WaitDuration    time.Duration `json:"waitDuration"`
+	InUseRatio      float64       `json:"inUseRatio"`
+	HighUsageEvents int64         `json:"highUsageEvents"`
+}
+
+var (
+	poolMonitorMu   sync.RWMutex
+	lastPoolSample  PoolSample
+	highUsageEvents int64
+)
+
+// StartPoolMonitor samples the pool every interval and logs the full stats whenever
+// InUse exceeds alertRatio of MaxOpen, which usually means leaked rows or slow queries.
+// The returned function stops the sampler.
+func StartPoolMonitor(interval time.Duration, alertRatio float64) func() {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				samplePool(alertRatio)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	log.Printf("📈 DB pool monitor started (every %s, alert above %.0f%% in use)", interval, alertRatio*100)
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func samplePool(alertRatio float64) {
+	if DB == nil {
+		return
+	}
+
+	stats := DB.Stats()
+
+	ratio := 0.0
+	if stats.MaxOpenConnections > 0 {
+		ratio = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	}
+
+	poolMonitorMu.Lock()
+	if alertRatio > 0 && ratio >= alertRatio {
+		highUsageEvents++
+		log.Printf("⚠️ DB pool pressure: in_use=%d/%d (%.0f%%) open=%d idle=%d wait_count=%d wait_duration=%s lifetime_closed=%d idle_closed=%d",
+			stats.InUse, stats.MaxOpenConnections, ratio*100, stats.OpenConnections, stats.Idle,
+			stats.WaitCount, stats.WaitDuration, stats.MaxLifetimeClosed, stats.MaxIdleTimeClosed)
+	}
+	lastPoolSample = PoolSample{
+		SampledAt:       time.Now(),
+		MaxOpen:         stats.MaxOpenConnections,
+		Open:            stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		WaitCount:       stats.WaitCount,
+		WaitDuration:    stats.WaitDuration,
+		InUseRatio:      ratio,
+		HighUsageEvents: highUsageEvents,
+	}
+	poolMonitorMu.Unlock()
+}
+
+// LastPoolSample returns the most recent sample taken by the pool monitor
+func LastPoolSample() PoolSample {
+	poolMonitorMu.RLock()
+	defer poolMonitorMu.RUnlock()
+	return lastPoolSample
+}
+
+// PoolMetricsText renders live pool stats in the Prometheus text exposition format
+func PoolMetricsText() string {
+	stats := Stats()
+
+	poolMonitorMu.RLock()
+	events := highUsageEvents
+	poolMonitorMu.RUnlock()
+
+	var b strings.Builder
+	writeGauge := func(name, help string, value interface{}) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", name, help, name, name, value)
+	}
+	writeCounter := func(name, help string, value interface{}) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n%s %v\n", name, help, name, name, value)
+	}
+
+	writeGauge("db_pool_max_open_connections", "Maximum number of open connections.", stats.MaxOpenConnections)
+	writeGauge("db_pool_open_connections", "Established connections, in use and idle.", stats.OpenConnections)
+	writeGauge("db_pool_in_use_connections", "Connections currently in use.", stats.InUse)
+	writeGauge("db_pool_idle_connections", "Idle connections.", stats.Idle)
+	writeCounter("db_pool_wait_count_total", "Connections waited for.", stats.WaitCount)
+	writeCounter("db_pool_wait_duration_seconds_total", "Time blocked waiting for a connection.", stats.WaitDuration.Seconds())
+	writeCounter("db_pool_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.", stats.MaxIdleClosed)
+	writeCounter("db_pool_max_idle_time_closed_total", "Connections closed due to SetConnMaxIdleTime.", stats.MaxIdleTimeClosed)
+	writeCounter("db_pool_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.", stats.MaxLifetimeClosed)
+	writeCounter("db_pool_high_usage_events_total", "Samples where in-use connections exceeded the alert ratio.", events)
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+// ===============================
+// internal/database/timeouts.go - Statement Timeouts
+// ===============================
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Every connection carries a server-side statement_timeout (DB_STATEMENT_TIMEOUT, see
+// DatabaseConfig.ConnectionString), so a slow query is cancelled by PostgreSQL even when
+// the caller's context has no deadline and the connection returns to the pool. Jobs that
+// legitimately scan whole tables run through WithAdminStatementTimeout instead.
+
+// DefaultAdminStatementTimeout is the budget for admin and background jobs when
+// DB_ADMIN_STATEMENT_TIMEOUT is not set
+const DefaultAdminStatementTimeout = 5 * time.Minute
+
+var adminStatementTimeout = DefaultAdminStatementTimeout
+
+// SetAdminStatementTimeout changes the budget used by WithAdminStatementTimeout
+func SetAdminStatementTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		adminStatementTimeout = timeout
+	}
+}
+
+// WithAdminStatementTimeout runs fn in a transaction whose statements may run for the
+// admin budget instead of the connection default. The transaction commits if fn succeeds.
+func WithAdminStatementTimeout(ctx context.Context, db *sqlx.DB, fn func(*sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// SET LOCAL only lasts until the transaction ends, so the pooled connection keeps its default
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", adminStatementTimeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,206 @@
+// ===============================
+// internal/handlers/admin.go - Admin Permission Management Handler
+// ===============================
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"weibaobe/internal/models"
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	service      *services.AdminService
+	auditService *services.AuditService
+}
+
+func NewAdminHandler(service *services.AdminService, auditService *services.AuditService) *AdminHandler {
+	return &AdminHandler{service: service, auditService: auditService}
+}
+
+// ListAdmins returns every admin with their permissions
+func (h *AdminHandler) ListAdmins(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	admins, err := h.service.ListAdmins(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to fetch admins", "ADMINS_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"admins":               admins,
+		"total":                len(admins),
+		"availablePermissions": models.AllAdminPermissions,
+	})
+}
+
+// GetPermissions returns the permissions held by one admin
+func (h *AdminHandler) GetPermissions(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+
+	grants, err := h.service.GetPermissions(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch permissions", "PERMISSIONS_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"userId":      userID,
+		"permissions": grants,
+	})
+}
+
+// GrantPermission gives an admin a permission
+func (h *AdminHandler) GrantPermission(c *gin.Context) {
+	adminID := c.GetString("userID")
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	var request struct {
+		Permission string `json:"permission" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	permission := models.AdminPermission(request.Permission)
+	err := h.service.GrantPermission(c.Request.Context(), userID, permission, adminID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid_permission":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Unknown permission",
+				"code":    "INVALID_PERMISSION",
+				"allowed": models.AllAdminPermissions,
+			})
+		case "user_not_admin":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User is not an admin", "code": "USER_NOT_ADMIN"})
+		case "permission_already_granted":
+			c.JSON(http.StatusConflict, gin.H{"error": "Permission already granted", "code": "ALREADY_GRANTED"})
+		default:
+			respondInternalError(c, "Failed to grant permission", "GRANT_ERROR", err)
+		}
+		return
+	}
+
+	h.auditService.Log(c.Request.Context(), adminID, models.AuditPermissionGranted,
+		models.AuditTargetUser, userID, models.MetadataMap{"permission": permission})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Permission granted",
+		"userId":     userID,
+		"permission": permission,
+	})
+}
+
+// RevokePermission removes a permission from an admin
+func (h *AdminHandler) RevokePermission(c *gin.Context) {
+	adminID := c.GetString("userID")
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	permission := models.AdminPermission(c.Param("permission"))
+	err := h.service.RevokePermission(c.Request.Context(), userID, permission, adminID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid_permission":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permission", "code": "INVALID_PERMISSION"})
+		case "cannot_revoke_own_super_admin":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot revoke your own super admin permission", "code": "CANNOT_REVOKE_SELF"})
+		case "permission_not_found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Permission not granted", "code": "PERMISSION_NOT_FOUND"})
+		default:
+			respondInternalError(c, "Failed to revoke permission", "REVOKE_ERROR", err)
+		}
+		return
+	}
+
+	h.auditService.Log(c.Request.Context(), adminID, models.AuditPermissionRevoked,
+		models.AuditTargetUser, userID, models.MetadataMap{"permission": permission})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Permission revoked",
+		"userId":     userID,
+		"permission": permission,
+	})
+}
+
+// GetAuditLog lists recorded admin actions, filterable by adminId, action and a
+// from/to date range (RFC3339 or YYYY-MM-DD; "to" is exclusive)
+func (h *AdminHandler) GetAuditLog(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 200 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	filter := models.AuditLogFilter{
+		AdminID: c.Query("adminId"),
+		Action:  c.Query("action"),
+	}
+
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseAuditTime(value)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid "+param+" date, use RFC3339 or YYYY-MM-DD", "INVALID_DATE")
+			return
+		}
+		*target = &parsed
+	}
+
+	entries, total, err := h.auditService.GetAuditLog(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch audit log", "AUDIT_LOG_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(entries) < total,
+	})
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
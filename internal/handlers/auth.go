@@ -5,40 +5,66 @@
 package handlers
 
 import (
+	"database/sql"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"weibaobe/internal/database"
+	"weibaobe/internal/middleware"
 	"weibaobe/internal/models"
 	"weibaobe/internal/services"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 )
 
 type AuthHandler struct {
 	firebaseService *services.FirebaseService
+	userService     *services.UserService
 }
 
-func NewAuthHandler(firebaseService *services.FirebaseService) *AuthHandler {
+func NewAuthHandler(firebaseService *services.FirebaseService, userService *services.UserService) *AuthHandler {
 	return &AuthHandler{
 		firebaseService: firebaseService,
+		userService:     userService,
 	}
 }
 
+// reactivateOnSignIn switches a self-deactivated account back on when its owner signs in
+// and reports whether it did. Admin suspensions are left alone. Only call it once the
+// caller's Firebase token has been verified.
+func (h *AuthHandler) reactivateOnSignIn(c *gin.Context, user *models.User) bool {
+	if user.IsActive || user.SelfDeactivatedAt == nil {
+		return false
+	}
+
+	reactivated, err := h.userService.ReactivateAccount(c.Request.Context(), user.UID)
+	if err != nil {
+		log.Printf("⚠️ Failed to reactivate account %s on sign-in: %v", user.UID, err)
+		return false
+	}
+	if reactivated {
+		user.IsActive = true
+		user.SelfDeactivatedAt = nil
+	}
+	return reactivated
+}
+
 // Verify Firebase token and return token claims
 func (h *AuthHandler) VerifyToken(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		respondError(c, http.StatusUnauthorized, "Authorization header required", "AUTH_REQUIRED")
 		c.Abort()
 		return
 	}
 
 	// Extract token from "Bearer <token>"
 	if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		respondError(c, http.StatusUnauthorized, "Invalid authorization header format", "INVALID_AUTH_HEADER")
 		c.Abort()
 		return
 	}
@@ -48,7 +74,7 @@ func (h *AuthHandler) VerifyToken(c *gin.Context) {
 	// Verify the token with Firebase using the service
 	token, err := h.firebaseService.VerifyIDToken(c.Request.Context(), idToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		respondError(c, http.StatusUnauthorized, "Invalid token", "INVALID_TOKEN")
 		c.Abort()
 		return
 	}
@@ -63,7 +89,7 @@ func (h *AuthHandler) VerifyToken(c *gin.Context) {
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
@@ -81,7 +107,7 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 
 	err := db.Get(&user, query, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found in database"})
+		respondError(c, http.StatusNotFound, "User not found in database", "USER_NOT_FOUND")
 		return
 	}
 
@@ -110,7 +136,7 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 func (h *AuthHandler) RequireAdmin(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		c.Abort()
 		return
 	}
@@ -121,7 +147,7 @@ func (h *AuthHandler) RequireAdmin(c *gin.Context) {
 	var role models.UserRole
 	err := db.QueryRow("SELECT user_type, role FROM users WHERE uid = $1", userID).Scan(&userType, &role)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User not found"})
+		respondError(c, http.StatusForbidden, "User not found", "USER_NOT_FOUND")
 		c.Abort()
 		return
 	}
@@ -130,6 +156,7 @@ func (h *AuthHandler) RequireAdmin(c *gin.Context) {
 	if userType != "admin" && role != models.UserRoleAdmin {
 		c.JSON(http.StatusForbidden, gin.H{
 			"error":        "Admin access required",
+			"code":         "ADMIN_REQUIRED",
 			"userRole":     role.String(),
 			"allowedRoles": []string{"admin"},
 		})
@@ -144,7 +171,7 @@ func (h *AuthHandler) RequireAdmin(c *gin.Context) {
 func (h *AuthHandler) RequireContentCreator(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		c.Abort()
 		return
 	}
@@ -154,15 +181,13 @@ func (h *AuthHandler) RequireContentCreator(c *gin.Context) {
 	var isActive bool
 	err := db.QueryRow("SELECT is_active FROM users WHERE uid = $1", userID).Scan(&isActive)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User not found"})
+		respondError(c, http.StatusForbidden, "User not found", "USER_NOT_FOUND")
 		c.Abort()
 		return
 	}
 
 	if !isActive {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "User account is inactive",
-		})
+		respondError(c, http.StatusForbidden, "User account is inactive", "ACCOUNT_INACTIVE")
 		c.Abort()
 		return
 	}
@@ -189,27 +214,34 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&requestData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Validate required fields
 	if requestData.UID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "UID is required"})
+		respondError(c, http.StatusBadRequest, "UID is required", "MISSING_UID")
 		return
 	}
 
 	if requestData.PhoneNumber == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Phone number is required"})
+		respondError(c, http.StatusBadRequest, "Phone number is required", "MISSING_PHONE_NUMBER")
 		return
 	}
 
+	// Keep numbers from countries we have no format for as-is, as SyncUserWithToken does,
+	// so existing users from those countries can still sign in
+	phoneNumber := strings.TrimSpace(requestData.PhoneNumber)
+	if normalized, err := models.NormalizePhoneNumber(phoneNumber); err == nil {
+		phoneNumber = normalized
+	}
+
 	// Format WhatsApp number if provided
 	var whatsappNumber *string
 	if requestData.WhatsappNumber != nil && *requestData.WhatsappNumber != "" {
 		formatted, err := models.FormatWhatsAppNumber(*requestData.WhatsappNumber)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid WhatsApp number format", "details": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid WhatsApp number", "code": "INVALID_WHATSAPP_NUMBER", "details": err.Error()})
 			return
 		}
 		whatsappNumber = formatted
@@ -227,11 +259,15 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 	err := db.Get(&existingUser, "SELECT * FROM users WHERE uid = $1", requestData.UID)
 
 	if err != nil {
+		if !ensurePhoneNumberAvailable(c, db, phoneNumber, requestData.UID) {
+			return
+		}
+
 		// ✅ FIXED: User doesn't exist, create new user WITH profile and cover images from request
 		newUser := models.User{
 			UID:            requestData.UID,
 			Name:           getValidName(requestData.Name),
-			PhoneNumber:    requestData.PhoneNumber,
+			PhoneNumber:    phoneNumber,
 			WhatsappNumber: whatsappNumber,
 			ProfileImage:   requestData.ProfileImage, // ✅ FIXED: Use image from request
 			CoverImage:     requestData.CoverImage,   // ✅ FIXED: Use image from request
@@ -258,7 +294,7 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 		// Validate user data
 		if !newUser.IsValidForCreation() {
 			errors := newUser.ValidateForCreation()
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": errors})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "code": "VALIDATION_FAILED", "details": errors})
 			return
 		}
 
@@ -277,10 +313,7 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 
 		_, err = db.NamedExec(query, newUser)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to create user",
-				"details": err.Error(),
-			})
+			respondInternalError(c, "Failed to create user", "CREATE_USER_ERROR", err)
 			return
 		}
 
@@ -316,17 +349,17 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 		return
 	}
 
-	// User exists, update last seen and return existing user
+	// User exists, update last seen and return existing user. This route takes the UID
+	// from the body, so it never reactivates a deactivated account; only the
+	// token-verified profile sync does.
+	normalizeStoredPhone(c, db, &existingUser)
 	existingUser.LastSeen = time.Now()
 	existingUser.UpdatedAt = time.Now()
 
 	_, err = db.Exec("UPDATE users SET last_seen = $1, updated_at = $2 WHERE uid = $3",
 		existingUser.LastSeen, existingUser.UpdatedAt, requestData.UID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update user",
-			"details": err.Error(),
-		})
+		respondInternalError(c, "Failed to update user", "UPDATE_USER_ERROR", err)
 		return
 	}
 
@@ -358,14 +391,23 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 func (h *AuthHandler) SyncUserWithToken(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	// userID is the resolved account UID, which after recovery is the old UID the account
+	// was created under. Firebase only knows the UID in the token.
+	value, _ := c.Get("firebaseToken")
+	token, ok := value.(*auth.Token)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	// Get Firebase user record using the service
-	firebaseUser, err := h.firebaseService.GetUser(c.Request.Context(), userID)
+	firebaseUser, err := h.firebaseService.GetUser(c.Request.Context(), token.UID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Firebase user"})
+		respondInternalError(c, "Failed to get Firebase user", "GET_FIREBASE_USER_ERROR", err)
 		return
 	}
 
@@ -377,18 +419,28 @@ func (h *AuthHandler) SyncUserWithToken(c *gin.Context) {
 		       user_type, role, gender, location, language,
 		       followers_count, following_count, videos_count, likes_count,
 		       is_verified, is_active, is_featured, is_live, tags,
-		       created_at, updated_at, last_seen, last_post_at
+		       created_at, updated_at, last_seen, last_post_at, self_deactivated_at
 		FROM users 
 		WHERE uid = $1`
 
 	err = db.Get(&existingUser, query, userID)
 
 	if err != nil {
+		// Firebase has already verified the number; keep it as-is if it is from a country
+		// we have no format for
+		phoneNumber := firebaseUser.PhoneNumber
+		if normalized, err := models.NormalizePhoneNumber(phoneNumber); err == nil {
+			phoneNumber = normalized
+		}
+		if phoneNumber != "" && !ensurePhoneNumberAvailable(c, db, phoneNumber, userID) {
+			return
+		}
+
 		// User doesn't exist, create new user with Firebase data and role support
 		newUser := models.User{
 			UID:            userID,
 			Name:           getFirebaseDisplayName(firebaseUser),
-			PhoneNumber:    firebaseUser.PhoneNumber,
+			PhoneNumber:    phoneNumber,
 			WhatsappNumber: nil,
 			ProfileImage:   "",
 			CoverImage:     "",
@@ -426,7 +478,7 @@ func (h *AuthHandler) SyncUserWithToken(c *gin.Context) {
 
 		_, err = db.NamedExec(insertQuery, newUser)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+			respondInternalError(c, "Failed to create user", "CREATE_USER_ERROR", err)
 			return
 		}
 
@@ -456,13 +508,15 @@ func (h *AuthHandler) SyncUserWithToken(c *gin.Context) {
 	}
 
 	// User exists, update last seen
+	reactivated := h.reactivateOnSignIn(c, &existingUser)
+	normalizeStoredPhone(c, db, &existingUser)
 	existingUser.LastSeen = time.Now()
 	existingUser.UpdatedAt = time.Now()
 
 	_, err = db.Exec("UPDATE users SET last_seen = $1, updated_at = $2 WHERE uid = $3",
 		existingUser.LastSeen, existingUser.UpdatedAt, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		respondInternalError(c, "Failed to update user", "UPDATE_USER_ERROR", err)
 		return
 	}
 
@@ -485,11 +539,204 @@ func (h *AuthHandler) SyncUserWithToken(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User synced successfully",
-		"user":    response,
+		"message":     "User synced successfully",
+		"user":        response,
+		"reactivated": reactivated,
 	})
 }
 
+// normalizeStoredPhone rewrites an account's legacy phone_number ("0712345678" or digits
+// without the "+") to E.164, so phone lookups and the duplicate check find it. The row is
+// left alone when the number can't be parsed or another account already holds the
+// normalised form; sign-in carries on either way.
+func normalizeStoredPhone(c *gin.Context, db *sqlx.DB, user *models.User) {
+	normalized, err := models.NormalizePhoneNumber(user.PhoneNumber)
+	if err != nil || normalized == user.PhoneNumber {
+		return
+	}
+
+	result, err := db.ExecContext(c.Request.Context(), `
+		UPDATE users SET phone_number = $2
+		WHERE uid = $1
+		  AND NOT EXISTS (SELECT 1 FROM users WHERE phone_number = $2)`,
+		user.UID, normalized)
+	if err != nil {
+		log.Printf("⚠️ Failed to normalise phone number for %s: %v", user.UID, err)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		user.PhoneNumber = normalized
+	}
+}
+
+// ensurePhoneNumberAvailable responds 409 when phone (E.164) already belongs to an account
+// other than uid. Legacy rows may be stored without the "+" or in national form.
+func ensurePhoneNumberAvailable(c *gin.Context, db *sqlx.DB, phone, uid string) bool {
+	var taken bool
+	err := db.QueryRowContext(c.Request.Context(),
+		`SELECT EXISTS(SELECT 1 FROM users WHERE phone_number = ANY($1::text[]) AND uid <> $2)`,
+		models.PhoneNumberStoredForms(phone), uid).Scan(&taken)
+	if err != nil {
+		respondInternalError(c, "Failed to check phone number", "CHECK_FAILED", err)
+		return false
+	}
+	if taken {
+		respondError(c, http.StatusConflict,
+			"This phone number is already registered to another account. Use account recovery to sign in to it.",
+			"PHONE_NUMBER_IN_USE")
+		return false
+	}
+	return true
+}
+
+// CheckPhone reports whether a phone number already belongs to a registered account.
+// Only a boolean is returned so the endpoint cannot be used to read profile data;
+// enumeration is limited by a dedicated rate limit bucket.
+func (h *AuthHandler) CheckPhone(c *gin.Context) {
+	var request struct {
+		PhoneNumber string `json:"phoneNumber" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Phone number is required", "code": "INVALID_REQUEST"})
+		return
+	}
+
+	normalized, err := models.NormalizePhoneNumber(request.PhoneNumber)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid phone number format", "code": "INVALID_PHONE_NUMBER"})
+		return
+	}
+
+	// Numbers are stored in E.164 form, older rows may lack the "+" or be in national form
+	candidates := models.PhoneNumberStoredForms(normalized)
+
+	var exists bool
+	err = database.GetDB().QueryRowContext(c.Request.Context(),
+		`SELECT EXISTS(SELECT 1 FROM users WHERE phone_number = ANY($1::text[]))`,
+		candidates).Scan(&exists)
+	if err != nil {
+		respondInternalError(c, "Failed to check phone number", "CHECK_FAILED", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"exists": exists})
+}
+
+// RecoverAccount links the caller's Firebase UID to the existing account registered with
+// the same verified phone number. After a reinstall or Firebase account reset the UID can
+// change while the phone stays the same; without the link, sync would create a duplicate
+// user and orphan the original account.
+func (h *AuthHandler) RecoverAccount(c *gin.Context) {
+	value, _ := c.Get("firebaseToken")
+	token, ok := value.(*auth.Token)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	ctx := c.Request.Context()
+	db := database.GetDB()
+	query := `
+		SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
+		       user_type, role, gender, location, language,
+		       followers_count, following_count, videos_count, likes_count,
+		       is_verified, is_active, is_featured, is_live, tags,
+		       created_at, updated_at, last_seen, last_post_at, self_deactivated_at
+		FROM users `
+
+	// Already linked, or the UID has its own account: nothing to recover
+	if userID := c.GetString("userID"); userID != "" {
+		var user models.User
+		err := db.GetContext(ctx, &user, query+"WHERE uid = $1", userID)
+		if err == nil {
+			c.JSON(http.StatusOK, gin.H{
+				"recovered": userID != token.UID,
+				"user":      userResponseFor(user),
+			})
+			return
+		}
+		if err != sql.ErrNoRows {
+			respondInternalError(c, "Failed to look up account", "RECOVER_LOOKUP_ERROR", err)
+			return
+		}
+	}
+
+	phone, _ := token.Claims["phone_number"].(string)
+	if phone == "" {
+		respondError(c, http.StatusBadRequest, "Sign in with a verified phone number to recover an account", "PHONE_NOT_VERIFIED")
+		return
+	}
+
+	// Firebase numbers are E.164; older rows may lack the "+" or be in national form
+	candidates := models.PhoneNumberStoredForms(phone)
+
+	var user models.User
+	err := db.GetContext(ctx, &user,
+		query+"WHERE phone_number = ANY($1::text[]) ORDER BY is_active DESC, last_seen DESC LIMIT 1", candidates)
+	if err == sql.ErrNoRows {
+		respondError(c, http.StatusNotFound, "No account is registered with this phone number", "ACCOUNT_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to look up account", "RECOVER_LOOKUP_ERROR", err)
+		return
+	}
+
+	// The verified phone number proves ownership, so an account the owner deactivated is
+	// switched back on; only admin suspensions are refused
+	reactivated := h.reactivateOnSignIn(c, &user)
+	if !user.IsActive {
+		respondError(c, http.StatusForbidden, "This account has been deactivated", "ACCOUNT_INACTIVE")
+		return
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO user_uid_links (firebase_uid, user_id, phone_number)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (firebase_uid) DO NOTHING`,
+		token.UID, user.UID, phone)
+	if err != nil {
+		respondInternalError(c, "Failed to link account", "RECOVER_LINK_ERROR", err)
+		return
+	}
+	middleware.ForgetAccountUID(token.UID)
+
+	user.LastSeen = time.Now()
+	if _, err := db.ExecContext(ctx, "UPDATE users SET last_seen = $1 WHERE uid = $2", user.LastSeen, user.UID); err != nil {
+		log.Printf("⚠️ Failed to update last_seen for recovered account %s: %v", user.UID, err)
+	}
+
+	log.Printf("🔑 Linked Firebase UID %s to existing account %s", token.UID, user.UID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Account recovered successfully",
+		"recovered":   true,
+		"reactivated": reactivated,
+		"user":        userResponseFor(user),
+	})
+}
+
+// userResponseFor wraps a user with the derived display fields the app expects
+func userResponseFor(user models.User) models.UserResponse {
+	return models.UserResponse{
+		User:                    user,
+		RoleDisplayName:         user.Role.DisplayName(),
+		CanPost:                 user.CanPost(),
+		HasWhatsApp:             user.HasWhatsApp(),
+		WhatsAppLink:            user.GetWhatsAppLink(),
+		WhatsAppLinkWithMessage: user.GetWhatsAppLinkWithMessage(),
+		GenderDisplay:           user.GetGenderDisplay(),
+		LocationDisplay:         user.GetLocationDisplay(),
+		LanguageDisplay:         user.GetLanguageDisplay(),
+		HasGender:               user.HasGender(),
+		HasLocation:             user.HasLocation(),
+		HasLanguage:             user.HasLanguage(),
+		HasPostedVideos:         user.HasPostedVideos(),
+		LastPostTimeAgo:         user.GetLastPostTimeAgo(),
+	}
+}
+
 // Helper function to get valid display name
 func getValidName(name string) string {
 	if name != "" && len(name) >= 2 {
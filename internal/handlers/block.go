@@ -0,0 +1,130 @@
+// ===============================
+// internal/handlers/block.go - User Blocking Handler
+// ===============================
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	service *services.BlockService
+}
+
+func NewBlockHandler(service *services.BlockService) *BlockHandler {
+	return &BlockHandler{service: service}
+}
+
+// GetBlockedUsers lists the users the authenticated user has blocked
+func (h *BlockHandler) GetBlockedUsers(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	users, total, err := h.service.GetBlockedUsers(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch blocked users", "BLOCKED_USERS_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":   users,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(users) < total,
+	})
+}
+
+func (h *BlockHandler) BlockUser(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	blockedID := c.Param("userId")
+	if blockedID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	err := h.service.BlockUser(c.Request.Context(), userID, blockedID)
+	if err != nil {
+		switch err.Error() {
+		case "cannot_block_self":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cannot block yourself",
+				"code":  "CANNOT_BLOCK_SELF",
+			})
+		case "user_not_found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+				"code":  "USER_NOT_FOUND",
+			})
+		case "already_blocked":
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "User is already blocked",
+				"code":  "ALREADY_BLOCKED",
+			})
+		default:
+			respondInternalError(c, "Failed to block user", "BLOCK_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+func (h *BlockHandler) UnblockUser(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	blockedID := c.Param("userId")
+	if blockedID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	err := h.service.UnblockUser(c.Request.Context(), userID, blockedID)
+	if err != nil {
+		if err.Error() == "not_blocked" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "User is not blocked",
+				"code":  "NOT_BLOCKED",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to unblock user", "UNBLOCK_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
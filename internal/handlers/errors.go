@@ -0,0 +1,105 @@
+// ===============================
+// internal/handlers/errors.go - Shared error responses
+// ===============================
+
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"weibaobe/internal/logging"
+	"weibaobe/internal/services"
+	"weibaobe/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// respondError writes the standard {error, code} body
+func respondError(c *gin.Context, status int, message, code string) {
+	c.JSON(status, gin.H{"error": message, "code": code})
+}
+
+// respondNotFound writes the standard not-found body, e.g. respondNotFound(c, "Video")
+// returns {"error": "Video not found", "code": "VIDEO_NOT_FOUND"}
+func respondNotFound(c *gin.Context, resource string) {
+	code := strings.ToUpper(strings.ReplaceAll(resource, " ", "_")) + "_NOT_FOUND"
+	respondError(c, http.StatusNotFound, resource+" not found", code)
+}
+
+// respondInternalError logs the underlying error and returns a safe 500 body.
+// Raw errors can carry SQL or storage details, so they never reach the client.
+func respondInternalError(c *gin.Context, message, code string, err error) {
+	logging.FromContext(c.Request.Context()).Error(message,
+		"method", c.Request.Method, "path", c.Request.URL.Path, "code", code, "error", err)
+	respondError(c, http.StatusInternalServerError, message, code)
+}
+
+// respondInsufficientBalance returns a 402 carrying the coins required and the current
+// balance, when the service reported them, so the client can prompt a top-up
+func respondInsufficientBalance(c *gin.Context, err error) {
+	body := gin.H{"error": "Insufficient coin balance", "code": "INSUFFICIENT_BALANCE"}
+
+	var balanceErr *services.InsufficientBalanceError
+	if errors.As(err, &balanceErr) {
+		body["required"] = balanceErr.Required
+		body["current"] = balanceErr.Current
+	}
+
+	c.JSON(http.StatusPaymentRequired, body)
+}
+
+// respondStorageUnavailable answers 503 with a Retry-After when err comes from the open
+// storage circuit breaker, and reports whether it did
+func respondStorageUnavailable(c *gin.Context, err error) bool {
+	if !errors.Is(err, storage.ErrStorageUnavailable) {
+		return false
+	}
+	c.Header("Retry-After", "30")
+	respondError(c, http.StatusServiceUnavailable,
+		"Media storage is temporarily unavailable, please try again shortly", "STORAGE_UNAVAILABLE")
+	return true
+}
+
+// respondRateLimited answers 429 with a Retry-After when err is a per-user action
+// throttle, and reports whether it did
+func respondRateLimited(c *gin.Context, err error) bool {
+	var throttled *services.ThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":      "You're doing that too often, please slow down",
+		"code":       "ACTION_RATE_LIMITED",
+		"action":     throttled.Action,
+		"retryAfter": retryAfter,
+	})
+	return true
+}
+
+// respondBindError returns a 400 for a request body that failed to bind. Only the names
+// of fields that failed validation are reported, not the raw decoder error.
+func respondBindError(c *gin.Context, err error) {
+	body := gin.H{"error": "Invalid request data", "code": "INVALID_REQUEST"}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]string, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, fieldErr.Field()+": "+fieldErr.Tag())
+		}
+		body["fields"] = fields
+	}
+
+	c.JSON(http.StatusBadRequest, body)
+}
@@ -5,8 +5,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"weibaobe/internal/models"
 	"weibaobe/internal/services"
@@ -135,32 +137,32 @@ var giftCatalog = map[string]struct {
 func (h *GiftHandler) SendGift(c *gin.Context) {
 	senderID := c.GetString("userID")
 	if senderID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, "Unauthorized", "AUTH_REQUIRED")
 		return
 	}
 
 	var request models.SendGiftRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Validate sender is not sending gift to themselves
 	if senderID == request.RecipientID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot send gift to yourself"})
+		respondError(c, http.StatusBadRequest, "Cannot send gift to yourself", "CANNOT_GIFT_SELF")
 		return
 	}
 
 	// Get gift details from catalog
 	gift, exists := giftCatalog[request.GiftID]
 	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gift ID"})
+		respondError(c, http.StatusBadRequest, "Invalid gift ID", "INVALID_GIFT_ID")
 		return
 	}
 
 	// Validate gift price
 	if !models.ValidateGiftPrice(gift.Price) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gift price"})
+		respondError(c, http.StatusBadRequest, "Invalid gift price", "INVALID_GIFT_PRICE")
 		return
 	}
 
@@ -175,18 +177,40 @@ func (h *GiftHandler) SendGift(c *gin.Context) {
 		gift.Rarity,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch err.Error() {
+		case "cannot_gift_self":
+			respondError(c, http.StatusBadRequest, "Cannot send gift to yourself", "CANNOT_GIFT_SELF")
+		case "sender_not_found":
+			respondError(c, http.StatusForbidden, "Sender account not found or inactive", "SENDER_NOT_FOUND")
+		case "recipient_not_found":
+			respondNotFound(c, "Recipient")
+		case "video_not_found":
+			respondNotFound(c, "Video")
+		case "insufficient_balance":
+			respondInsufficientBalance(c, err)
+		case "sender_wallet_not_found":
+			respondError(c, http.StatusBadRequest, "Wallet not found", "WALLET_NOT_FOUND")
+		case "recipient_wallet_not_found":
+			respondError(c, http.StatusBadRequest, "Recipient cannot receive gifts yet", "RECIPIENT_WALLET_NOT_FOUND")
+		default:
+			respondInternalError(c, "Failed to send gift", "SEND_GIFT_ERROR", err)
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// GetGiftHistory retrieves gift history for a user
+// GetGiftHistory retrieves the caller's own gift history
 func (h *GiftHandler) GetGiftHistory(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	if userID != c.GetString("userID") {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 		return
 	}
 
@@ -206,7 +230,7 @@ func (h *GiftHandler) GetGiftHistory(c *gin.Context) {
 
 	history, err := h.giftService.GetUserGiftHistory(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch gift history"})
+		respondInternalError(c, "Failed to fetch gift history", "FETCH_GIFT_HISTORY_ERROR", err)
 		return
 	}
 
@@ -216,17 +240,22 @@ func (h *GiftHandler) GetGiftHistory(c *gin.Context) {
 	})
 }
 
-// GetGiftStats retrieves gift statistics for a user
+// GetGiftStats retrieves the caller's own gift statistics
 func (h *GiftHandler) GetGiftStats(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	if userID != c.GetString("userID") {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 		return
 	}
 
 	stats, err := h.giftService.GetUserGiftStats(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondNotFound(c, "User")
 		return
 	}
 
@@ -237,13 +266,91 @@ func (h *GiftHandler) GetGiftStats(c *gin.Context) {
 func (h *GiftHandler) GetPlatformCommissionSummary(c *gin.Context) {
 	summary, err := h.giftService.GetPlatformCommissionSummary(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commission summary"})
+		respondInternalError(c, "Failed to fetch commission summary", "FETCH_COMMISSION_SUMMARY_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
 
+// GetPlatformRevenue returns commission revenue for a date range (admin only).
+// from/to are YYYY-MM-DD dates, both inclusive; the default is the last 30 days.
+func (h *GiftHandler) GetPlatformRevenue(c *gin.Context) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	from := today.AddDate(0, 0, -(models.DefaultRevenueRangeDays - 1))
+
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid 'to' date, expected YYYY-MM-DD", "INVALID_DATE")
+			return
+		}
+		to = parsed
+	}
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid 'from' date, expected YYYY-MM-DD", "INVALID_DATE")
+			return
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		respondError(c, http.StatusBadRequest, "'from' must not be after 'to'", "INVALID_DATE_RANGE")
+		return
+	}
+	if to.Sub(from) >= models.MaxRevenueRangeDays*24*time.Hour {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("Date range cannot exceed %d days", models.MaxRevenueRangeDays), "INVALID_DATE_RANGE")
+		return
+	}
+
+	// Make the end date inclusive
+	report, err := h.giftService.GetPlatformRevenue(c.Request.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		respondInternalError(c, "Failed to fetch platform revenue", "PLATFORM_REVENUE_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, report)
+}
+
+// GetMyTopGiftedVideos ranks the authenticated creator's videos by gift revenue over the
+// last `days` days (default 30, max 365)
+func (h *GiftHandler) GetMyTopGiftedVideos(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "Unauthorized", "AUTH_REQUIRED")
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 && parsed <= 365 {
+			days = parsed
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	report, err := h.giftService.GetTopGiftedVideos(c.Request.Context(), userID, since, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch top gifted videos", "TOP_GIFTED_VIDEOS_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, report)
+}
+
 // GetTopGiftSenders retrieves top gift senders (admin only)
 func (h *GiftHandler) GetTopGiftSenders(c *gin.Context) {
 	limit := 10
@@ -255,7 +362,7 @@ func (h *GiftHandler) GetTopGiftSenders(c *gin.Context) {
 
 	senders, err := h.giftService.GetTopGiftSenders(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top senders"})
+		respondInternalError(c, "Failed to fetch top senders", "FETCH_TOP_SENDERS_ERROR", err)
 		return
 	}
 
@@ -276,7 +383,7 @@ func (h *GiftHandler) GetTopGiftReceivers(c *gin.Context) {
 
 	receivers, err := h.giftService.GetTopGiftReceivers(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top receivers"})
+		respondInternalError(c, "Failed to fetch top receivers", "FETCH_TOP_RECEIVERS_ERROR", err)
 		return
 	}
 
@@ -286,8 +393,19 @@ func (h *GiftHandler) GetTopGiftReceivers(c *gin.Context) {
 	})
 }
 
-// GetGiftCatalog returns available gifts
+// GetGiftCatalog returns available gifts. Signed-in callers also get their balance and a
+// per-gift canAfford hint.
 func (h *GiftHandler) GetGiftCatalog(c *gin.Context) {
+	balance := -1
+	if userID := c.GetString("userID"); userID != "" {
+		var err error
+		balance, err = h.giftService.GetSenderBalance(c.Request.Context(), userID)
+		if err != nil {
+			respondInternalError(c, "Failed to fetch wallet balance", "BALANCE_ERROR", err)
+			return
+		}
+	}
+
 	catalog := make([]gin.H, 0, len(giftCatalog))
 
 	for id, gift := range giftCatalog {
@@ -302,26 +420,45 @@ func (h *GiftHandler) GetGiftCatalog(c *gin.Context) {
 			"recipientAmount":    recipientAmount,
 			"platformCommission": platformCommission,
 		})
+		if balance >= 0 {
+			catalog[len(catalog)-1]["canAfford"] = balance >= gift.Price
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"gifts":          catalog,
 		"total":          len(catalog),
 		"commissionRate": models.DefaultCommissionRate,
-	})
+	}
+	if balance >= 0 {
+		response["userBalance"] = balance
+		c.Header("Cache-Control", "private, no-cache")
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// GetGiftTransaction retrieves a specific gift transaction
+// GetGiftTransaction retrieves a gift transaction the caller sent or received
 func (h *GiftHandler) GetGiftTransaction(c *gin.Context) {
 	transactionID := c.Param("transactionId")
 	if transactionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Transaction ID required"})
+		respondError(c, http.StatusBadRequest, "Transaction ID required", "MISSING_TRANSACTION_ID")
 		return
 	}
 
 	transaction, err := h.giftService.GetGiftTransaction(c.Request.Context(), transactionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		if err.Error() == "transaction_not_found" {
+			respondNotFound(c, "Transaction")
+			return
+		}
+		respondInternalError(c, "Failed to fetch transaction", "TRANSACTION_FETCH_ERROR", err)
+		return
+	}
+
+	userID := c.GetString("userID")
+	if !transaction.IsSender(userID) && !transaction.IsRecipient(userID) {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 		return
 	}
 
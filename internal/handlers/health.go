@@ -0,0 +1,136 @@
+// ===============================
+// internal/handlers/health.go - Dependency Health Probes
+// ===============================
+
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"weibaobe/internal/database"
+	"weibaobe/internal/services"
+	"weibaobe/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthProbeTimeout bounds each dependency probe so a hung dependency can't stall the check
+const healthProbeTimeout = 5 * time.Second
+
+type HealthHandler struct {
+	firebaseService *services.FirebaseService
+	r2Client        *storage.R2Client
+	profileCache    *services.ProfileCache
+}
+
+func NewHealthHandler(firebaseService *services.FirebaseService, r2Client *storage.R2Client, profileCache *services.ProfileCache) *HealthHandler {
+	return &HealthHandler{
+		firebaseService: firebaseService,
+		r2Client:        r2Client,
+		profileCache:    profileCache,
+	}
+}
+
+type dependencyStatus struct {
+	Status    string `json:"status"` // "up" or "down"
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// probeDependencies pings the database, Firebase Auth and R2 concurrently. All three are
+// critical: the app can't authenticate, read or serve media without them.
+func (h *HealthHandler) probeDependencies(ctx context.Context) (map[string]dependencyStatus, bool) {
+	probes := map[string]func(context.Context) error{
+		"database": func(context.Context) error { return database.Health() },
+		"firebase": h.firebaseService.Ping,
+		"storage":  h.r2Client.Ping,
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		statuses = make(map[string]dependencyStatus, len(probes))
+		healthy  = true
+	)
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe func(context.Context) error) {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := probe(probeCtx)
+			status := dependencyStatus{Status: "up", LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				log.Printf("❌ Health probe %s failed: %v", name, err)
+				status.Status = "down"
+			}
+
+			mu.Lock()
+			statuses[name] = status
+			if err != nil {
+				healthy = false
+			}
+			mu.Unlock()
+		}(name, probe)
+	}
+	wg.Wait()
+
+	return statuses, healthy
+}
+
+// GetSystemHealth reports per-dependency status and latency, returning 503 when any
+// critical dependency is down
+func (h *HealthHandler) GetSystemHealth(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	statuses, healthy := h.probeDependencies(c.Request.Context())
+	dbStats := database.Stats()
+
+	status, httpStatus := "healthy", http.StatusOK
+	if !healthy {
+		status, httpStatus = "unhealthy", http.StatusServiceUnavailable
+	}
+
+	c.JSON(httpStatus, gin.H{
+		"status": status,
+		"database": gin.H{
+			"status":           statuses["database"].Status,
+			"latencyMs":        statuses["database"].LatencyMs,
+			"open_connections": dbStats.OpenConnections,
+			"in_use":           dbStats.InUse,
+			"idle":             dbStats.Idle,
+		},
+		"firebase": statuses["firebase"],
+		"storage": gin.H{
+			"status":    statuses["storage"].Status,
+			"latencyMs": statuses["storage"].LatencyMs,
+			"type":      "cloudflare-r2",
+			"breaker":   h.r2Client.BreakerState(),
+		},
+		"profileCache": h.profileCache.Stats(),
+		"search": gin.H{
+			"status":            "enabled",
+			"type":              "fuzzy",
+			"trigram_extension": true,
+			"history_enabled":   true,
+		},
+		"chat": gin.H{
+			"status":         "enabled",
+			"type":           "websocket",
+			"real_time":      true,
+			"tables_created": true,
+		},
+		"app": gin.H{
+			"name":     "video-social-with-reactions",
+			"version":  "2.1.0",
+			"status":   status,
+			"features": []string{"videos", "wallet", "social", "fuzzy-search", "history", "video-reactions", "websocket-chat"},
+		},
+	})
+}
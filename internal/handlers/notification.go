@@ -0,0 +1,107 @@
+// ===============================
+// internal/handlers/notification.go - Notification Handler
+// ===============================
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"weibaobe/internal/models"
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+}
+
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, err := h.service.GetUserNotifications(c.Request.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch notifications", "NOTIFICATIONS_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"total":         len(notifications),
+		"limit":         limit,
+		"offset":        offset,
+		"hasMore":       len(notifications) == limit,
+	})
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+
+	count, err := h.service.GetUnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch unread count", "UNREAD_COUNT_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unreadCount": count})
+}
+
+// MarkNotificationsRead marks notifications read in bulk, optionally only one type and/or
+// only those up to a given notification. An empty body marks everything read.
+func (h *NotificationHandler) MarkNotificationsRead(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	var request models.MarkNotificationsReadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	updated, err := h.service.MarkNotificationsRead(c.Request.Context(), userID, request)
+	if err != nil {
+		respondInternalError(c, "Failed to mark notifications read", "NOTIFICATIONS_READ_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
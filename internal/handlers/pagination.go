@@ -0,0 +1,43 @@
+// ===============================
+// internal/handlers/pagination.go - Shared limit/offset parsing and list metadata
+// ===============================
+
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination reads the limit and offset query params. Limits outside 1..maxLimit
+// fall back to defaultLimit and negative offsets to 0.
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (limit, offset int) {
+	limit = defaultLimit
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= maxLimit {
+			limit = parsed
+		}
+	}
+
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	return limit, offset
+}
+
+// paginatedResponse builds the list body shared by paginated endpoints: the page under
+// key plus the real total, limit, offset, 1-based page and hasMore
+func paginatedResponse(key string, items interface{}, count, total, limit, offset int) gin.H {
+	return gin.H{
+		key:       items,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"page":    offset/limit + 1,
+		"hasMore": offset+count < total,
+	}
+}
@@ -0,0 +1,148 @@
+// ===============================
+// internal/handlers/search.go - Combined Search Handler
+// ===============================
+
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"weibaobe/internal/models"
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultSearchSectionLimit = 10
+	maxSearchSectionLimit     = 50
+)
+
+type SearchHandler struct {
+	videoService *services.VideoService
+	userService  *services.UserService
+}
+
+func NewSearchHandler(videoService *services.VideoService, userService *services.UserService) *SearchHandler {
+	return &SearchHandler{
+		videoService: videoService,
+		userService:  userService,
+	}
+}
+
+// Search runs the video fuzzy search and the user search in parallel for one universal
+// search screen. videoLimit and userLimit size each section independently. There is no
+// drama catalogue in this service, so dramas is always empty.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query, ok := searchQueryParam(c)
+	if !ok {
+		return
+	}
+
+	videoLimit := searchSectionLimit(c, "videoLimit")
+	userLimit := searchSectionLimit(c, "userLimit")
+	viewerID := c.GetString("userID")
+
+	// Priced videos are locked per viewer, so signed-in results must not be shared
+	if viewerID != "" {
+		c.Header("Cache-Control", "private, max-age=300")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+
+	videos := []models.VideoResponse{}
+	users := []models.PublicUser{}
+	response := gin.H{
+		"query":  query,
+		"videos": videos,
+		"users":  users,
+		"dramas": []interface{}{},
+	}
+
+	// Sensitive queries get the configured safe response in place of every section
+	safety := h.videoService.CheckSearchSafety(query)
+	if safety != nil {
+		response["safety"] = safety
+		if safety.WithholdsResults() {
+			c.Header("Cache-Control", "private, no-cache")
+			c.JSON(http.StatusOK, response)
+			return
+		}
+	}
+
+	g, ctx := errgroup.WithContext(c.Request.Context())
+
+	g.Go(func() error {
+		found, _, err := h.videoService.FuzzySearch(ctx, query, viewerID, false, models.VideoSearchFilters{}, videoLimit, 0)
+		if err != nil {
+			return err
+		}
+		if err := h.videoService.LockUnpurchasedVideos(ctx, viewerID, found); err != nil {
+			return err
+		}
+		if err := h.videoService.MarkSavedVideos(ctx, viewerID, found); err != nil {
+			log.Printf("⚠️ Failed to mark saved videos for %s: %v", viewerID, err)
+		}
+		if found != nil {
+			videos = found
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		found, err := h.userService.SearchUsers(ctx, query, viewerID, userLimit)
+		if err != nil {
+			return err
+		}
+		users = found
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		respondInternalError(c, "Search failed", "SEARCH_ERROR", err)
+		return
+	}
+
+	response["videos"] = videos
+	response["users"] = users
+	response["cached_at"] = time.Now().Unix()
+	c.JSON(http.StatusOK, response)
+}
+
+// searchQueryParam reads and trims ?q=, writing a 400 and reporting false when it is
+// blank or shorter than the configured minimum
+func searchQueryParam(c *gin.Context) (string, bool) {
+	query, err := services.NormalizeSearchQuery(c.Query("q"))
+	if err == nil {
+		return query, true
+	}
+
+	if err.Error() == "search_query_too_short" {
+		minLength := services.MinSearchQueryLength()
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     fmt.Sprintf("Search query must be at least %d characters", minLength),
+			"code":      "SEARCH_QUERY_TOO_SHORT",
+			"minLength": minLength,
+		})
+		return "", false
+	}
+
+	respondError(c, http.StatusBadRequest, "Search query required", "MISSING_SEARCH_QUERY")
+	return "", false
+}
+
+// searchSectionLimit reads one section's limit, falling back to the default when it is
+// missing or out of range
+func searchSectionLimit(c *gin.Context, param string) int {
+	if l := c.Query(param); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= maxSearchSectionLimit {
+			return parsed
+		}
+	}
+	return defaultSearchSectionLimit
+}
@@ -2,13 +2,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"weibaobe/internal/services"
+	"weibaobe/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,23 +24,35 @@ func NewUploadHandler(service *services.UploadService) *UploadHandler {
 	return &UploadHandler{service: service}
 }
 
+// multipartOverhead leaves room for form fields and boundaries on top of the file itself
+const multipartOverhead = 1024 * 1024
+
 func (h *UploadHandler) UploadFile(c *gin.Context) {
 	// Add request timeout for large files
 	c.Request = c.Request.WithContext(c.Request.Context())
 
+	// Never read more than the largest allowed file, whatever the client claims
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxSizeFor("video")+multipartOverhead)
+
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "No file uploaded",
-			"details": err.Error(),
-		})
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":       "File too large",
+				"code":        "FILE_TOO_LARGE",
+				"max_size_mb": fmt.Sprintf("%.2f", float64(h.service.MaxSizeFor("video"))/(1024*1024)),
+			})
+			return
+		}
+		respondError(c, http.StatusBadRequest, "No file uploaded", "NO_FILE")
 		return
 	}
 	defer file.Close()
 
 	fileType := c.PostForm("type") // "banner", "thumbnail", "video", "profile"
 	if fileType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File type required"})
+		respondError(c, http.StatusBadRequest, "File type required", "FILE_TYPE_REQUIRED")
 		return
 	}
 
@@ -61,6 +76,7 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 		if !validExt {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":    fmt.Sprintf("Invalid file type for %s", fileType),
+				"code":     "INVALID_FILE_TYPE",
 				"allowed":  allowed,
 				"received": ext,
 			})
@@ -69,30 +85,22 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	} else {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":              "Invalid file type category",
+			"code":               "INVALID_FILE_CATEGORY",
 			"allowed_categories": []string{"banner", "thumbnail", "profile", "video"},
 		})
 		return
 	}
 
-	// Enhanced file size validation with better error messages
-	maxSizes := map[string]int64{
-		"banner":    10 * 1024 * 1024,   // 10MB (increased for better quality)
-		"thumbnail": 5 * 1024 * 1024,    // 5MB
-		"profile":   5 * 1024 * 1024,    // 5MB
-		"video":     1024 * 1024 * 1024, // 1GB (increased for TS files and longer episodes)
-	}
-
-	if maxSize, exists := maxSizes[fileType]; exists {
-		if header.Size > maxSize {
-			fileSizeInMB := float64(header.Size) / (1024 * 1024)
-			maxSizeInMB := float64(maxSize) / (1024 * 1024)
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        "File too large",
-				"file_size_mb": fmt.Sprintf("%.2f", fileSizeInMB),
-				"max_size_mb":  fmt.Sprintf("%.2f", maxSizeInMB),
-			})
+	// Size limit and content sniffing (the extension alone is not trusted)
+	contentType, err := h.service.ValidateFile(file, header.Size, fileType)
+	if err != nil {
+		var uploadErr *services.UploadError
+		if !errors.As(err, &uploadErr) {
+			respondInternalError(c, "Failed to validate file", "VALIDATE_FILE_ERROR", err)
 			return
 		}
+		c.JSON(uploadErrorStatus(uploadErr), h.uploadErrorDetails(uploadErr, fileType))
+		return
 	}
 
 	// Additional validation for video files
@@ -101,19 +109,29 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 		if !isValidVideoFile(header.Filename, ext) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   "Invalid video file format",
+				"code":    "INVALID_VIDEO_FILE",
 				"details": "File may be corrupted or not a valid video file",
 			})
 			return
 		}
 	}
 
-	// Upload with enhanced error handling
-	url, err := h.service.UploadFile(c.Request.Context(), file, header.Filename, fileType)
+	// Upload with enhanced error handling; videos also get a generated thumbnail when possible
+	var url, thumbnailURL string
+	if fileType == "video" {
+		url, thumbnailURL, err = h.service.UploadVideo(c.Request.Context(), file, header.Filename, contentType)
+	} else {
+		url, err = h.service.UploadFile(c.Request.Context(), file, header.Filename, fileType, contentType)
+	}
 	if err != nil {
-		// Enhanced error response
+		// Enhanced error response; storage errors are logged, not returned
+		log.Printf("❌ Upload of %s failed: %v", header.Filename, err)
+		if respondStorageUnavailable(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":     "Failed to upload file",
-			"details":   err.Error(),
+			"code":      "UPLOAD_ERROR",
 			"file_name": header.Filename,
 			"file_size": header.Size,
 			"file_type": fileType,
@@ -123,15 +141,83 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	}
 
 	// Success response with additional metadata
-	c.JSON(http.StatusOK, gin.H{
-		"url":       url,
-		"message":   "File uploaded successfully",
-		"file_name": header.Filename,
-		"file_size": header.Size,
-		"file_type": fileType,
-		"extension": ext,
-		"timestamp": time.Now().Unix(),
-	})
+	response := gin.H{
+		"url":          url,
+		"message":      "File uploaded successfully",
+		"file_name":    header.Filename,
+		"file_size":    header.Size,
+		"file_type":    fileType,
+		"extension":    ext,
+		"content_type": contentType,
+		"timestamp":    time.Now().Unix(),
+	}
+	if thumbnailURL != "" {
+		response["thumbnail_url"] = thumbnailURL
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PresignUpload issues a presigned URL so large files can be uploaded straight to R2
+// instead of being proxied through the server
+func (h *UploadHandler) PresignUpload(c *gin.Context) {
+	var request struct {
+		FileType    string `json:"fileType" binding:"required"`
+		FileName    string `json:"fileName" binding:"required"`
+		ContentType string `json:"contentType" binding:"required"`
+		Size        int64  `json:"size" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	upload, err := h.service.PresignUpload(request.FileType, request.FileName, request.ContentType, request.Size)
+	if err != nil {
+		var uploadErr *services.UploadError
+		if errors.As(err, &uploadErr) {
+			c.JSON(uploadErrorStatus(uploadErr), h.uploadErrorDetails(uploadErr, request.FileType))
+			return
+		}
+		if respondStorageUnavailable(c, err) {
+			return
+		}
+		respondInternalError(c, "Failed to create upload URL", "PRESIGN_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// uploadErrorStatus maps a rejected upload to its HTTP status
+func uploadErrorStatus(err *services.UploadError) int {
+	switch err.Code {
+	case "FILE_TOO_LARGE":
+		return http.StatusRequestEntityTooLarge
+	case "UNSUPPORTED_CONTENT_TYPE":
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// uploadErrorDetails builds the structured error body for a rejected upload
+func (h *UploadHandler) uploadErrorDetails(err *services.UploadError, fileType string) gin.H {
+	details := gin.H{
+		"error": err.Message,
+		"code":  err.Code,
+	}
+
+	switch err.Code {
+	case "FILE_TOO_LARGE":
+		details["file_size_mb"] = fmt.Sprintf("%.2f", float64(err.Size)/(1024*1024))
+		details["max_size_mb"] = fmt.Sprintf("%.2f", float64(err.MaxSize)/(1024*1024))
+	case "UNSUPPORTED_CONTENT_TYPE":
+		details["detected_type"] = err.DetectedType
+		details["allowed_types"] = h.service.AllowedContentTypes(fileType)
+	}
+
+	return details
 }
 
 // Enhanced video file validation
@@ -162,11 +248,11 @@ func (h *UploadHandler) HealthCheck(c *gin.Context) {
 			"images": {".jpg", ".jpeg", ".png", ".webp", ".gif"},
 			"videos": {".mp4", ".mov", ".avi", ".webm", ".ts", ".m3u8", ".mkv"},
 		},
-		"max_sizes_mb": map[string]int{
-			"banner":    10,
-			"thumbnail": 5,
-			"profile":   5,
-			"video":     1024,
+		"max_sizes_mb": map[string]int64{
+			"banner":    h.service.MaxSizeFor("banner") / (1024 * 1024),
+			"thumbnail": h.service.MaxSizeFor("thumbnail") / (1024 * 1024),
+			"profile":   h.service.MaxSizeFor("profile") / (1024 * 1024),
+			"video":     h.service.MaxSizeFor("video") / (1024 * 1024),
 		},
 	})
 }
@@ -175,22 +261,19 @@ func (h *UploadHandler) HealthCheck(c *gin.Context) {
 func (h *UploadHandler) BatchUploadFiles(c *gin.Context) {
 	form, err := c.MultipartForm()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to parse multipart form",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, "Failed to parse multipart form", "INVALID_MULTIPART_FORM")
 		return
 	}
 
 	files := form.File["files"]
 	if len(files) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No files uploaded"})
+		respondError(c, http.StatusBadRequest, "No files uploaded", "NO_FILES_UPLOADED")
 		return
 	}
 
 	fileType := c.PostForm("type")
 	if fileType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File type required"})
+		respondError(c, http.StatusBadRequest, "File type required", "FILE_TYPE_REQUIRED")
 		return
 	}
 
@@ -199,6 +282,7 @@ func (h *UploadHandler) BatchUploadFiles(c *gin.Context) {
 	if len(files) > maxBatchSize {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":    fmt.Sprintf("Too many files. Maximum %d files allowed per batch", maxBatchSize),
+			"code":     "TOO_MANY_FILES",
 			"received": len(files),
 		})
 		return
@@ -214,7 +298,8 @@ func (h *UploadHandler) BatchUploadFiles(c *gin.Context) {
 				"index":    i,
 				"filename": fileHeader.Filename,
 				"status":   "error",
-				"error":    "Failed to open file: " + err.Error(),
+				"error":    "Failed to open file",
+				"code":     "OPEN_FILE_ERROR",
 			})
 			continue
 		}
@@ -228,28 +313,64 @@ func (h *UploadHandler) BatchUploadFiles(c *gin.Context) {
 				"filename": fileHeader.Filename,
 				"status":   "error",
 				"error":    "Invalid file type",
+				"code":     "INVALID_FILE_TYPE",
 			})
 			continue
 		}
 
+		contentType, err := h.service.ValidateFile(file, fileHeader.Size, fileType)
+		if err != nil {
+			file.Close()
+			result := map[string]interface{}{
+				"index":    i,
+				"filename": fileHeader.Filename,
+				"status":   "error",
+				"error":    "Failed to validate file",
+				"code":     "VALIDATE_FILE_ERROR",
+			}
+			var uploadErr *services.UploadError
+			if errors.As(err, &uploadErr) {
+				for key, value := range h.uploadErrorDetails(uploadErr, fileType) {
+					result[key] = value
+				}
+			}
+			results = append(results, result)
+			continue
+		}
+
 		// Upload file
-		url, err := h.service.UploadFile(c.Request.Context(), file, fileHeader.Filename, fileType)
+		var url, thumbnailURL string
+		if fileType == "video" {
+			url, thumbnailURL, err = h.service.UploadVideo(c.Request.Context(), file, fileHeader.Filename, contentType)
+		} else {
+			url, err = h.service.UploadFile(c.Request.Context(), file, fileHeader.Filename, fileType, contentType)
+		}
 		file.Close()
 
 		if err != nil {
+			log.Printf("❌ Batch upload of %s failed: %v", fileHeader.Filename, err)
+			message, code := "Upload failed", "UPLOAD_ERROR"
+			if errors.Is(err, storage.ErrStorageUnavailable) {
+				message, code = "Media storage is temporarily unavailable", "STORAGE_UNAVAILABLE"
+			}
 			results = append(results, map[string]interface{}{
 				"index":    i,
 				"filename": fileHeader.Filename,
 				"status":   "error",
-				"error":    "Upload failed: " + err.Error(),
+				"error":    message,
+				"code":     code,
 			})
 		} else {
-			results = append(results, map[string]interface{}{
+			result := map[string]interface{}{
 				"index":    i,
 				"filename": fileHeader.Filename,
 				"status":   "success",
 				"url":      url,
-			})
+			}
+			if thumbnailURL != "" {
+				result["thumbnail_url"] = thumbnailURL
+			}
+			results = append(results, result)
 			successCount++
 		}
 	}
@@ -5,30 +5,35 @@
 package handlers
 
 import (
+	"database/sql"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"weibaobe/internal/models"
+	"weibaobe/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
 type UserHandler struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	userService  *services.UserService
+	auditService *services.AuditService
 }
 
-func NewUserHandler(db *sqlx.DB) *UserHandler {
-	return &UserHandler{db: db}
+func NewUserHandler(db *sqlx.DB, userService *services.UserService, auditService *services.AuditService) *UserHandler {
+	return &UserHandler{db: db, userService: userService, auditService: auditService}
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -37,12 +42,18 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	if req.WhatsappNumber != nil && *req.WhatsappNumber != "" {
 		formatted, err := models.FormatWhatsAppNumber(*req.WhatsappNumber)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid WhatsApp number: %v", err)})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid WhatsApp number", "code": "INVALID_WHATSAPP_NUMBER", "details": err.Error()})
 			return
 		}
 		whatsappNumber = formatted
 	}
 
+	phoneNumber, err := models.NormalizePhoneNumber(req.PhoneNumber)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid phone number", "code": "INVALID_PHONE_NUMBER", "details": err.Error()})
+		return
+	}
+
 	// Set default role if not provided
 	role := models.UserRoleGuest
 	if req.Role != nil {
@@ -50,9 +61,9 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 
 	user := models.User{
-		UID:            req.Name + "_" + req.PhoneNumber, // You might want to generate a proper UID
+		UID:            req.Name + "_" + phoneNumber, // You might want to generate a proper UID
 		Name:           req.Name,
-		PhoneNumber:    req.PhoneNumber,
+		PhoneNumber:    phoneNumber,
 		WhatsappNumber: whatsappNumber,
 		ProfileImage:   req.ProfileImage,
 		Bio:            req.Bio,
@@ -68,7 +79,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	// Validate user
 	if !user.IsValidForCreation() {
 		errors := user.ValidateForCreation()
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": errors})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "code": "VALIDATION_FAILED", "details": errors})
 		return
 	}
 
@@ -92,9 +103,13 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		updated_at = EXCLUDED.updated_at,
 		last_seen = EXCLUDED.last_seen`
 
-	_, err := h.db.NamedExec(query, user)
+	if !ensurePhoneNumberAvailable(c, h.db, user.PhoneNumber, user.UID) {
+		return
+	}
+
+	_, err = h.db.NamedExec(query, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user", "details": err.Error()})
+		respondInternalError(c, "Failed to create user", "CREATE_USER_ERROR", err)
 		return
 	}
 
@@ -120,20 +135,41 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 func (h *UserHandler) GetUser(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
+	response, ok := h.loadProfile(c, userID)
+	if !ok {
+		return
+	}
+
+	if notModified(c, contentETag(response)) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// loadProfile returns the public profile response for userID, from the profile cache
+// when possible. It writes the 404 itself and reports false when the user doesn't exist.
+func (h *UserHandler) loadProfile(c *gin.Context, userID string) (models.UserResponse, bool) {
+	cache := h.userService.ProfileCache()
+	if response, ok := cache.Get(userID); ok {
+		// Relative to now, so never served from the cache
+		response.LastPostTimeAgo = response.User.GetLastPostTimeAgo()
+		return response, true
+	}
+
 	var user models.User
 	query := `SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
 	                 user_type, role, followers_count, following_count, videos_count, likes_count,
-	                 is_verified, is_active, is_featured, tags,
+	                 is_verified, is_active, is_featured, is_private, tags,
 	                 created_at, updated_at, last_seen, last_post_at
 	          FROM users WHERE uid = $1 AND is_active = true`
 	err := h.db.Get(&user, query, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		return
+		respondNotFound(c, "User")
+		return models.UserResponse{}, false
 	}
 
 	// Create enhanced response
@@ -148,13 +184,14 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		LastPostTimeAgo:         user.GetLastPostTimeAgo(),
 	}
 
-	c.JSON(http.StatusOK, response)
+	cache.Set(userID, response)
+	return response, true
 }
 
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
@@ -165,14 +202,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		var requestingUser models.User
 		err := h.db.Get(&requestingUser, "SELECT user_type, role FROM users WHERE uid = $1", requestingUserID)
 		if err != nil || !requestingUser.IsAdmin() {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 			return
 		}
 	}
 
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -185,7 +222,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		} else {
 			formatted, err := models.FormatWhatsAppNumber(*req.WhatsappNumber)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid WhatsApp number: %v", err)})
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid WhatsApp number", "code": "INVALID_WHATSAPP_NUMBER", "details": err.Error()})
 				return
 			}
 			whatsappNumber = formatted
@@ -234,8 +271,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		argIndex++
 	}
 
+	if req.IsPrivate != nil {
+		setParts = append(setParts, fmt.Sprintf("is_private = $%d", argIndex))
+		args = append(args, *req.IsPrivate)
+		argIndex++
+	}
+
 	if len(setParts) == 2 { // Only time fields
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		respondError(c, http.StatusBadRequest, "No fields to update", "NO_FIELDS_TO_UPDATE")
 		return
 	}
 
@@ -245,9 +288,17 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	_, err := h.db.Exec(query, args...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		respondInternalError(c, "Failed to update user", "UPDATE_USER_ERROR", err)
 		return
 	}
+	h.userService.InvalidateProfile(userID)
+
+	// Going public lets everyone waiting in the request queue in
+	if req.IsPrivate != nil && !*req.IsPrivate {
+		if _, err := h.userService.ApprovePendingFollowRequests(c.Request.Context(), userID); err != nil {
+			log.Printf("Failed to approve pending follow requests for %s: %v", userID, err)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
 }
@@ -255,7 +306,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
@@ -266,7 +317,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		var requestingUser models.User
 		err := h.db.Get(&requestingUser, "SELECT user_type, role FROM users WHERE uid = $1", requestingUserID)
 		if err != nil || !requestingUser.IsAdmin() {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 			return
 		}
 	}
@@ -274,7 +325,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	// Use transaction to delete user and related data
 	tx, err := h.db.Beginx()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		respondInternalError(c, "Failed to start transaction", "START_TRANSACTION_ERROR", err)
 		return
 	}
 	defer tx.Rollback()
@@ -282,7 +333,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	// Delete user follows
 	_, err = tx.Exec("DELETE FROM user_follows WHERE follower_id = $1 OR following_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user follows"})
+		respondInternalError(c, "Failed to delete user follows", "DELETE_USER_FOLLOWS_ERROR", err)
 		return
 	}
 
@@ -293,67 +344,81 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 			SELECT id FROM comments WHERE author_id = $1
 		)`, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment likes"})
+		respondInternalError(c, "Failed to delete comment likes", "DELETE_COMMENT_LIKES_ERROR", err)
 		return
 	}
 
 	// Delete video likes
 	_, err = tx.Exec("DELETE FROM video_likes WHERE user_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete video likes"})
+		respondInternalError(c, "Failed to delete video likes", "DELETE_VIDEO_LIKES_ERROR", err)
 		return
 	}
 
 	// Delete comments
 	_, err = tx.Exec("DELETE FROM comments WHERE author_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comments"})
+		respondInternalError(c, "Failed to delete comments", "DELETE_COMMENTS_ERROR", err)
 		return
 	}
 
 	// Delete videos
 	_, err = tx.Exec("DELETE FROM videos WHERE user_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete videos"})
+		respondInternalError(c, "Failed to delete videos", "DELETE_VIDEOS_ERROR", err)
 		return
 	}
 
 	// Delete wallet transactions
 	_, err = tx.Exec("DELETE FROM wallet_transactions WHERE user_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete wallet transactions"})
+		respondInternalError(c, "Failed to delete wallet transactions", "DELETE_WALLET_TRANSACTIONS_ERROR", err)
+		return
+	}
+
+	// Delete archived wallet transactions
+	_, err = tx.Exec("DELETE FROM wallet_transactions_archive WHERE user_id = $1", userID)
+	if err != nil {
+		respondInternalError(c, "Failed to delete archived wallet transactions", "DELETE_WALLET_TRANSACTIONS_ARCHIVE_ERROR", err)
 		return
 	}
 
 	// Delete wallet
 	_, err = tx.Exec("DELETE FROM wallets WHERE user_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete wallet"})
+		respondInternalError(c, "Failed to delete wallet", "DELETE_WALLET_ERROR", err)
 		return
 	}
 
 	// Delete purchase requests
 	_, err = tx.Exec("DELETE FROM coin_purchase_requests WHERE user_id = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete purchase requests"})
+		respondInternalError(c, "Failed to delete purchase requests", "DELETE_PURCHASE_REQUESTS_ERROR", err)
 		return
 	}
 
 	// Delete user
 	_, err = tx.Exec("DELETE FROM users WHERE uid = $1", userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		respondInternalError(c, "Failed to delete user", "DELETE_USER_ERROR", err)
 		return
 	}
 
 	if err = tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		respondInternalError(c, "Failed to commit transaction", "COMMIT_TRANSACTION_ERROR", err)
 		return
 	}
+	h.userService.InvalidateProfile(userID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// userWithTotal carries the window-function total alongside each user row
+type userWithTotal struct {
+	models.User
+	TotalCount int `db:"total_count"`
+}
+
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	limit := 50
 	if l := c.Query("limit"); l != "" {
@@ -407,6 +472,14 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 		whereClause += " AND whatsapp_number IS NOT NULL AND whatsapp_number != ''"
 	}
 
+	demographics, demographicArgs, ok := demographicFilters(c, argIndex)
+	if !ok {
+		return
+	}
+	whereClause += demographics
+	args = append(args, demographicArgs...)
+	argIndex += len(demographicArgs)
+
 	if query := c.Query("q"); query != "" {
 		whereClause += fmt.Sprintf(" AND (name ILIKE $%d OR phone_number ILIKE $%d)", argIndex, argIndex)
 		searchPattern := "%" + query + "%"
@@ -437,21 +510,25 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	limitOffset := fmt.Sprintf(" %s LIMIT $%d OFFSET $%d", orderBy, argIndex, argIndex+1)
 	args = append(args, limit, offset)
 
-	var users []models.User
+	var rows []userWithTotal
 	query := `SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
 	                 user_type, role, followers_count, following_count, videos_count, likes_count,
 	                 is_verified, is_active, is_featured, tags,
-	                 created_at, updated_at, last_seen, last_post_at
+	                 created_at, updated_at, last_seen, last_post_at,
+	                 COUNT(*) OVER() AS total_count
 	          FROM users ` + whereClause + limitOffset
-	err := h.db.Select(&users, query, args...)
+	err := h.db.Select(&rows, query, args...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		respondInternalError(c, "Failed to fetch users", "FETCH_USERS_ERROR", err)
 		return
 	}
 
 	// Convert to enhanced response format
-	userResponses := make([]models.UserResponse, len(users))
-	for i, user := range users {
+	total := 0
+	userResponses := make([]models.UserResponse, len(rows))
+	for i, row := range rows {
+		user := row.User
+		total = row.TotalCount
 		userResponses[i] = models.UserResponse{
 			User:                    user,
 			RoleDisplayName:         user.Role.DisplayName(),
@@ -465,15 +542,17 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"users": userResponses,
-		"total": len(userResponses),
+		"users":   userResponses,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(userResponses) < total,
 	})
 }
 
 func (h *UserHandler) SearchUsers(c *gin.Context) {
-	query := c.Query("q")
-	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query required"})
+	query, ok := searchQueryParam(c)
+	if !ok {
 		return
 	}
 
@@ -484,35 +563,52 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 		}
 	}
 
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
 	searchPattern := "%" + query + "%"
 
-	var users []models.User
+	demographics, demographicArgs, ok := demographicFilters(c, 4)
+	if !ok {
+		return
+	}
+
+	var rows []userWithTotal
 	searchQuery := `
 		SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
 		       user_type, role, followers_count, following_count, videos_count, likes_count,
 		       is_verified, is_active, is_featured, tags,
-		       created_at, updated_at, last_seen, last_post_at
+		       created_at, updated_at, last_seen, last_post_at,
+		       COUNT(*) OVER() AS total_count
 		FROM users 
 		WHERE is_active = true AND (
 			name ILIKE $1 OR 
 			phone_number ILIKE $1 OR
 			bio ILIKE $1
-		)
+		)` + demographics + `
 		ORDER BY 
 			CASE WHEN name ILIKE $1 THEN 1 ELSE 2 END,
 			followers_count DESC,
 			created_at DESC 
-		LIMIT $2`
+		LIMIT $2 OFFSET $3`
 
-	err := h.db.Select(&users, searchQuery, searchPattern, limit)
+	args := append([]interface{}{searchPattern, limit, offset}, demographicArgs...)
+	err := h.db.Select(&rows, searchQuery, args...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search users"})
+		respondInternalError(c, "Failed to search users", "SEARCH_USERS_ERROR", err)
 		return
 	}
 
 	// Convert to enhanced response format
-	userResponses := make([]models.UserResponse, len(users))
-	for i, user := range users {
+	total := 0
+	userResponses := make([]models.UserResponse, len(rows))
+	for i, row := range rows {
+		user := row.User
+		total = row.TotalCount
 		userResponses[i] = models.UserResponse{
 			User:                    user,
 			RoleDisplayName:         user.Role.DisplayName(),
@@ -526,35 +622,32 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"users": userResponses,
-		"total": len(userResponses),
-		"query": query,
+		"users":   userResponses,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(userResponses) < total,
+		"query":   query,
 	})
 }
 
 func (h *UserHandler) GetUserStats(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
 	// Get user with basic stats
-	var user models.User
-	err := h.db.Get(&user, `
-		SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
-		       user_type, role, followers_count, following_count, videos_count, likes_count,
-		       is_verified, is_active, is_featured, tags,
-		       created_at, updated_at, last_seen, last_post_at
-		FROM users WHERE uid = $1 AND is_active = true`, userID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	profile, ok := h.loadProfile(c, userID)
+	if !ok {
 		return
 	}
+	user := profile.User
 
 	// Get additional video stats
 	var totalViews, totalLikes int
-	err = h.db.QueryRow(`
+	err := h.db.QueryRow(`
 		SELECT 
 			COALESCE(SUM(views_count), 0) as total_views,
 			COALESCE(SUM(likes_count), 0) as total_likes
@@ -589,23 +682,258 @@ func (h *UserHandler) GetUserStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetDashboard returns everything the home screen needs in one round trip: wallet
+// balance, unread notifications and chats, follower count and recent earnings
+func (h *UserHandler) GetDashboard(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-cache")
+
+	since := time.Now().Add(-models.DashboardEarningsWindow)
+
+	// Each figure is a cheap indexed lookup, so one round trip replaces several API calls
+	var dashboard models.UserDashboard
+	err := h.db.GetContext(c.Request.Context(), &dashboard, `
+		SELECT u.uid, u.followers_count,
+		       COALESCE((SELECT w.coins_balance FROM wallets w WHERE w.user_id = u.uid), 0) AS coins_balance,
+		       (SELECT COUNT(*) FROM notifications n
+		        WHERE n.user_id = u.uid AND n.is_read = false) AS unread_notifications,
+		       (SELECT COUNT(*) FROM video_reaction_chats ch
+		        WHERE u.uid = ANY(ch.participants)
+		          AND COALESCE((ch.unread_counts->>u.uid)::int, 0) > 0) AS unread_chats,
+		       COALESCE((SELECT SUM(t.coin_amount) FROM wallet_transactions t
+		                 WHERE t.user_id = u.uid
+		                   AND t.type = ANY($2::text[])
+		                   AND t.created_at >= $3), 0) AS recent_earnings
+		FROM users u
+		WHERE u.uid = $1`,
+		userID, models.StringSlice(models.EarningTransactionTypes), since)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found", "code": "USER_NOT_FOUND"})
+			return
+		}
+		respondInternalError(c, "Failed to load dashboard", "DASHBOARD_ERROR", err)
+		return
+	}
+	dashboard.EarningsSince = since
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// demographicFilters turns the gender, location and language query params into
+// " AND ..." conditions with placeholders starting at argIndex. Matches are exact so the
+// partial indexes on those columns stay usable; location is the full
+// "Ward, Constituency, County" string. An invalid gender writes a 400 and returns !ok.
+func demographicFilters(c *gin.Context, argIndex int) (string, []interface{}, bool) {
+	var clause string
+	var args []interface{}
+
+	if gender := strings.TrimSpace(c.Query("gender")); gender != "" {
+		parsed := models.ParseUserGender(gender)
+		if parsed == nil {
+			respondError(c, http.StatusBadRequest, "gender must be male or female", "INVALID_GENDER")
+			return "", nil, false
+		}
+		clause += fmt.Sprintf(" AND gender = $%d", argIndex)
+		args = append(args, parsed.String())
+		argIndex++
+	}
+
+	if location := strings.TrimSpace(c.Query("location")); location != "" {
+		clause += fmt.Sprintf(" AND location = $%d", argIndex)
+		args = append(args, location)
+		argIndex++
+	}
+
+	if language := strings.TrimSpace(c.Query("language")); language != "" {
+		clause += fmt.Sprintf(" AND language = $%d", argIndex)
+		args = append(args, language)
+	}
+
+	return clause, args, true
+}
+
+// GetDemographicsSummary returns the platform-wide gender split and top locations and
+// languages of active users
+func (h *UserHandler) GetDemographicsSummary(c *gin.Context) {
+	summary, err := h.userService.GetDemographicsSummary(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to fetch demographics summary", "DEMOGRAPHICS_SUMMARY_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetMyAudience returns the aggregated demographics of the caller's followers. Small
+// buckets are suppressed so individual followers can't be identified.
+func (h *UserHandler) GetMyAudience(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+
+	audience, err := h.userService.GetAudienceDemographics(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch audience demographics", "AUDIENCE_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audience)
+}
+
+// GetMyBestTimes returns the caller's average engagement by posting weekday and hour,
+// bucketed in ?tz= (default Africa/Nairobi) over the last ?days= days (default 90)
+func (h *UserHandler) GetMyBestTimes(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+
+	days := 90
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 && parsed <= 365 {
+			days = parsed
+		}
+	}
+
+	timezone := models.DefaultAnalyticsTimezone
+	if tz := strings.TrimSpace(c.Query("tz")); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			respondError(c, http.StatusBadRequest, "tz must be an IANA timezone such as Africa/Nairobi", "INVALID_TIMEZONE")
+			return
+		}
+		timezone = tz
+	}
+
+	bestTimes, err := h.userService.GetBestPostingTimes(c.Request.Context(), userID, timezone, days)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch best posting times", "BEST_TIMES_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bestTimes)
+}
+
+// GetCreatorLeaderboard ranks creators by likes, comments and watch sessions their videos
+// received in the last `days` days, optionally narrowed to a location (substring match,
+// e.g. a county) and/or language
+func (h *UserHandler) GetCreatorLeaderboard(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	days := 7
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 && parsed <= 90 {
+			days = parsed
+		}
+	}
+
+	location := strings.TrimSpace(c.Query("location"))
+	language := strings.TrimSpace(c.Query("language"))
+	since := time.Now().AddDate(0, 0, -days)
+
+	query := `
+		WITH recent_likes AS (
+			SELECT v.user_id, COUNT(*) AS cnt
+			FROM video_likes vl
+			JOIN videos v ON v.id = vl.video_id
+			WHERE vl.created_at >= $1 AND v.is_active = true
+			GROUP BY v.user_id
+		),
+		recent_comments AS (
+			SELECT v.user_id, COUNT(*) AS cnt
+			FROM comments cm
+			JOIN videos v ON v.id = cm.video_id
+			WHERE cm.created_at >= $1 AND v.is_active = true AND cm.author_id != v.user_id
+			GROUP BY v.user_id
+		),
+		recent_watches AS (
+			SELECT v.user_id, COUNT(*) AS cnt
+			FROM video_watch_sessions ws
+			JOIN videos v ON v.id = ws.video_id
+			WHERE ws.created_at >= $1 AND v.is_active = true
+			GROUP BY v.user_id
+		)
+		SELECT u.uid, u.name, u.profile_image, u.is_verified, u.location, u.language, u.followers_count,
+		       COALESCE(rl.cnt, 0) AS recent_likes,
+		       COALESCE(rc.cnt, 0) AS recent_comments,
+		       COALESCE(rw.cnt, 0) AS recent_watches,
+		       COALESCE(rl.cnt, 0) * 1.0 + COALESCE(rc.cnt, 0) * 2.0 + COALESCE(rw.cnt, 0) * 0.5 AS engagement_score
+		FROM users u
+		LEFT JOIN recent_likes rl ON rl.user_id = u.uid
+		LEFT JOIN recent_comments rc ON rc.user_id = u.uid
+		LEFT JOIN recent_watches rw ON rw.user_id = u.uid
+		WHERE u.is_active = true
+		  AND (rl.user_id IS NOT NULL OR rc.user_id IS NOT NULL OR rw.user_id IS NOT NULL)`
+
+	args := []interface{}{since}
+	argIndex := 2
+
+	if location != "" {
+		query += fmt.Sprintf(" AND u.location ILIKE $%d", argIndex)
+		args = append(args, "%"+location+"%")
+		argIndex++
+	}
+
+	if language != "" {
+		query += fmt.Sprintf(" AND LOWER(u.language) = LOWER($%d)", argIndex)
+		args = append(args, language)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY engagement_score DESC, u.followers_count DESC LIMIT $%d", argIndex)
+	args = append(args, limit)
+
+	creators := []models.CreatorLeaderboardEntry{}
+	if err := h.db.SelectContext(c.Request.Context(), &creators, query, args...); err != nil {
+		respondInternalError(c, "Failed to fetch creator leaderboard", "CREATOR_LEADERBOARD_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"creators": creators,
+		"total":    len(creators),
+		"location": location,
+		"language": language,
+		"days":     days,
+		"since":    since,
+	})
+}
+
 func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
 	var request struct {
-		IsActive   *bool   `json:"isActive"`
-		IsVerified *bool   `json:"isVerified"`
-		IsFeatured *bool   `json:"isFeatured"`
-		UserType   string  `json:"userType"`
-		Role       *string `json:"role"` // NEW: Role update
+		IsActive   *bool  `json:"isActive"`
+		IsVerified *bool  `json:"isVerified"`
+		IsFeatured *bool  `json:"isFeatured"`
+		UserType   string `json:"userType"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -614,7 +942,10 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 	args := []interface{}{time.Now()}
 	argIndex := 2
 
+	// An admin decision replaces any self-deactivation, so a suspended user can't
+	// reactivate themselves by signing in
 	if request.IsActive != nil {
+		setParts = append(setParts, "self_deactivated_at = NULL")
 		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argIndex))
 		args = append(args, *request.IsActive)
 		argIndex++
@@ -632,26 +963,20 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 		argIndex++
 	}
 
+	// Admin access follows role, which only ChangeRole may set
+	if request.UserType == models.UserTypeAdmin {
+		respondError(c, http.StatusBadRequest, "Use role to grant admin access", "USE_ROLE_FOR_ADMIN")
+		return
+	}
+
 	if request.UserType != "" {
 		setParts = append(setParts, fmt.Sprintf("user_type = $%d", argIndex))
 		args = append(args, request.UserType)
 		argIndex++
 	}
 
-	// NEW: Handle role update
-	if request.Role != nil {
-		role := models.ParseUserRole(*request.Role)
-		if !role.IsValid() {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role value"})
-			return
-		}
-		setParts = append(setParts, fmt.Sprintf("role = $%d", argIndex))
-		args = append(args, role)
-		argIndex++
-	}
-
 	if len(setParts) == 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		respondError(c, http.StatusBadRequest, "No fields to update", "NO_FIELDS_TO_UPDATE")
 		return
 	}
 
@@ -659,37 +984,178 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 		strings.Join(setParts, ", "), argIndex)
 	args = append(args, userID)
 
-	result, err := h.db.Exec(query, args...)
+	// Follow counts change with is_active, so they are synced in the same transaction
+	tx, err := h.db.BeginTxx(c.Request.Context(), nil)
+	if err != nil {
+		respondInternalError(c, "Failed to start transaction", "START_TRANSACTION_ERROR", err)
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+		respondInternalError(c, "Failed to update user status", "UPDATE_USER_STATUS_ERROR", err)
 		return
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check update result"})
+		respondInternalError(c, "Failed to check update result", "CHECK_UPDATE_RESULT_ERROR", err)
 		return
 	}
 
 	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondNotFound(c, "User")
 		return
 	}
 
+	var neighbors []string
+	if request.IsActive != nil {
+		neighbors, err = h.userService.SyncFollowCounts(c.Request.Context(), tx, userID)
+		if err != nil {
+			respondInternalError(c, "Failed to sync follow counts", "SYNC_FOLLOW_COUNTS_ERROR", err)
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		respondInternalError(c, "Failed to commit transaction", "COMMIT_TRANSACTION_ERROR", err)
+		return
+	}
+	h.userService.InvalidateProfile(append(neighbors, userID)...)
+
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditUserStatusUpdated,
+		models.AuditTargetUser, userID, models.MetadataMap{
+			"isActive":   request.IsActive,
+			"isVerified": request.IsVerified,
+			"isFeatured": request.IsFeatured,
+			"userType":   request.UserType,
+		})
+
 	c.JSON(http.StatusOK, gin.H{"message": "User status updated successfully"})
 }
 
+// DeactivateAccount switches the caller's account off. Unlike DeleteUser nothing is removed:
+// the profile and videos are hidden until the user reactivates or signs in again.
+func (h *UserHandler) DeactivateAccount(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	if err := h.userService.DeactivateAccount(c.Request.Context(), userID); err != nil {
+		if err.Error() == "user_not_found" {
+			respondError(c, http.StatusConflict, "Account is not active", "ACCOUNT_NOT_ACTIVE")
+			return
+		}
+		respondInternalError(c, "Failed to deactivate account", "DEACTIVATE_ACCOUNT_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Account deactivated. Sign in again to reactivate it.",
+		"deactivated": true,
+	})
+}
+
+// ReactivateAccount switches a self-deactivated account back on. Accounts suspended by an
+// admin stay inactive.
+func (h *UserHandler) ReactivateAccount(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	reactivated, err := h.userService.ReactivateAccount(c.Request.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "user_not_found":
+			respondNotFound(c, "User")
+		case "account_suspended":
+			respondError(c, http.StatusForbidden, "This account has been deactivated by an administrator", "ACCOUNT_SUSPENDED")
+		default:
+			respondInternalError(c, "Failed to reactivate account", "REACTIVATE_ACCOUNT_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reactivated": reactivated})
+}
+
+// ChangeUserRole sets a user's role (admin, host or guest). Demoting the last remaining
+// admin is refused. The change is audit-logged.
+func (h *UserHandler) ChangeUserRole(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	var request struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	role := models.UserRole(strings.ToLower(strings.TrimSpace(request.Role)))
+	if !role.IsValid() {
+		respondError(c, http.StatusBadRequest, "Role must be one of admin, host, guest", "INVALID_ROLE")
+		return
+	}
+
+	previousRole, ok := h.changeRole(c, userID, role)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "User role updated successfully",
+		"userId":          userID,
+		"previousRole":    previousRole,
+		"role":            role,
+		"roleDisplayName": role.DisplayName(),
+		"canPost":         role.CanPost(),
+	})
+}
+
+// changeRole applies a validated role change, audit-logs it and writes the error response
+// on failure; ok reports whether the caller should continue
+func (h *UserHandler) changeRole(c *gin.Context, userID string, role models.UserRole) (models.UserRole, bool) {
+	previousRole, err := h.userService.ChangeRole(c.Request.Context(), userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "user_not_found":
+			respondNotFound(c, "User")
+		case "last_admin":
+			respondError(c, http.StatusConflict, "Cannot demote the last remaining admin", "LAST_ADMIN")
+		default:
+			respondInternalError(c, "Failed to update user role", "UPDATE_USER_ROLE_ERROR", err)
+		}
+		return "", false
+	}
+
+	if previousRole != role {
+		h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditUserRoleChanged,
+			models.AuditTargetUser, userID, models.MetadataMap{"from": previousRole, "to": role})
+	}
+	return previousRole, true
+}
+
 // NEW: Get users by role
 func (h *UserHandler) GetUsersByRole(c *gin.Context) {
 	role := c.Param("role")
 	if role == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Role required"})
+		respondError(c, http.StatusBadRequest, "Role required", "ROLE_REQUIRED")
 		return
 	}
 
 	userRole := models.ParseUserRole(role)
 	if !userRole.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		respondError(c, http.StatusBadRequest, "Invalid role", "INVALID_ROLE")
 		return
 	}
 
@@ -720,7 +1186,7 @@ func (h *UserHandler) GetUsersByRole(c *gin.Context) {
 
 	err := h.db.Select(&users, query, userRole, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users by role"})
+		respondInternalError(c, "Failed to fetch users by role", "FETCH_USERS_BY_ROLE_ERROR", err)
 		return
 	}
 
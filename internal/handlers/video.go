@@ -5,26 +5,44 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"weibaobe/internal/config"
 	"weibaobe/internal/models"
 	"weibaobe/internal/services"
+	"weibaobe/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
 type VideoHandler struct {
-	service     *services.VideoService
-	userService *services.UserService
+	service             *services.VideoService
+	userService         *services.UserService
+	rewardService       *services.RewardService
+	notificationService *services.NotificationService
+	uploadService       *services.UploadService
+	purchaseService     *services.VideoPurchaseService
+	auditService        *services.AuditService
+	cacheTTLs           config.CacheTTLConfig
 }
 
-func NewVideoHandler(service *services.VideoService, userService *services.UserService) *VideoHandler {
+func NewVideoHandler(service *services.VideoService, userService *services.UserService, rewardService *services.RewardService, notificationService *services.NotificationService, uploadService *services.UploadService, purchaseService *services.VideoPurchaseService, auditService *services.AuditService, cacheTTLs config.CacheTTLConfig) *VideoHandler {
 	return &VideoHandler{
-		service:     service,
-		userService: userService,
+		service:             service,
+		userService:         userService,
+		rewardService:       rewardService,
+		notificationService: notificationService,
+		uploadService:       uploadService,
+		purchaseService:     purchaseService,
+		auditService:        auditService,
+		cacheTTLs:           cacheTTLs,
 	}
 }
 
@@ -32,23 +50,59 @@ func NewVideoHandler(service *services.VideoService, userService *services.UserS
 // HEADER HELPERS
 // ===============================
 
+// maxAge formats a Cache-Control value and returns the max-age in whole seconds. The
+// cache helpers return that number so response bodies can advertise it as "ttl".
+func maxAge(scope string, ttl time.Duration) (string, int) {
+	seconds := int(ttl / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	return fmt.Sprintf("%s, max-age=%d", scope, seconds), seconds
+}
+
 func (h *VideoHandler) setVideoStreamingHeaders(c *gin.Context) {
+	cacheControl, _ := maxAge("public", h.cacheTTLs.VideoStream)
 	c.Header("Accept-Ranges", "bytes")
-	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("Cache-Control", cacheControl)
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Content-Type-Options", "nosniff")
 	c.Header("X-Frame-Options", "SAMEORIGIN")
 }
 
-func (h *VideoHandler) setVideoAPIHeaders(c *gin.Context) {
-	c.Header("Cache-Control", "public, max-age=1800")
+func (h *VideoHandler) setVideoAPIHeaders(c *gin.Context) int {
+	cacheControl, ttl := maxAge("public", h.cacheTTLs.VideoDetail)
+	c.Header("Cache-Control", cacheControl)
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Content-Type-Options", "nosniff")
+	return ttl
 }
 
-func (h *VideoHandler) setVideoListHeaders(c *gin.Context) {
-	c.Header("Cache-Control", "public, max-age=900")
+// setVideoListHeaders caches lists publicly, or privately for signed-in viewers because
+// priced videos are locked or unlocked per viewer
+func (h *VideoHandler) setVideoListHeaders(c *gin.Context) int {
+	scope := "public"
+	if c.GetString("userID") != "" {
+		scope = "private"
+	}
+	cacheControl, ttl := maxAge(scope, h.cacheTTLs.VideoList)
+	c.Header("Cache-Control", cacheControl)
 	c.Header("Connection", "keep-alive")
+	return ttl
+}
+
+// applyViewerState withholds the media of priced videos the viewer hasn't bought and
+// marks the ones they saved. It responds with an error itself and returns false when
+// access can't be checked; a failed saved lookup only leaves isSaved false.
+func (h *VideoHandler) applyViewerState(c *gin.Context, videos []models.VideoResponse) bool {
+	viewerID := c.GetString("userID")
+	if err := h.service.LockUnpurchasedVideos(c.Request.Context(), viewerID, videos); err != nil {
+		respondInternalError(c, "Failed to check video access", "ACCESS_CHECK_ERROR", err)
+		return false
+	}
+	if err := h.service.MarkSavedVideos(c.Request.Context(), viewerID, videos); err != nil {
+		log.Printf("⚠️ Failed to mark saved videos for %s: %v", viewerID, err)
+	}
+	return true
 }
 
 func (h *VideoHandler) setInteractionHeaders(c *gin.Context) {
@@ -56,9 +110,16 @@ func (h *VideoHandler) setInteractionHeaders(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 }
 
-func (h *VideoHandler) setCommentHeaders(c *gin.Context) {
-	c.Header("Cache-Control", "public, max-age=300")
+// setCommentHeaders caches comment reads publicly, or privately for signed-in viewers
+// because isLiked is per viewer
+func (h *VideoHandler) setCommentHeaders(c *gin.Context) int {
+	cacheControl, ttl := maxAge("public", h.cacheTTLs.Comments)
+	if c.GetString("userID") != "" {
+		cacheControl, ttl = maxAge("private", h.cacheTTLs.CommentsViewer)
+	}
+	c.Header("Cache-Control", cacheControl)
 	c.Header("Connection", "keep-alive")
+	return ttl
 }
 
 // ===============================
@@ -66,14 +127,10 @@ func (h *VideoHandler) setCommentHeaders(c *gin.Context) {
 // ===============================
 
 func (h *VideoHandler) SearchVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
-	query := c.Query("q")
-	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Search query required",
-			"code":  "MISSING_SEARCH_QUERY",
-		})
+	query, ok := searchQueryParam(c)
+	if !ok {
 		return
 	}
 
@@ -95,27 +152,98 @@ func (h *VideoHandler) SearchVideos(c *gin.Context) {
 		}
 	}
 
+	filters, ok := parseVideoSearchFilters(c)
+	if !ok {
+		return
+	}
+
+	// Sensitive queries get the configured safe response instead of (or alongside) results
+	safety := h.service.CheckSearchSafety(query)
+	if safety != nil && safety.WithholdsResults() {
+		c.Header("Cache-Control", "private, no-cache")
+		c.JSON(http.StatusOK, gin.H{
+			"videos":       []models.VideoResponse{},
+			"total":        0,
+			"query":        query,
+			"usernameOnly": usernameOnly,
+			"page":         (offset / limit) + 1,
+			"limit":        limit,
+			"hasMore":      false,
+			"safety":       safety,
+		})
+		return
+	}
+
 	// Perform fuzzy search
-	videos, total, err := h.service.FuzzySearch(c.Request.Context(), query, usernameOnly, limit, offset)
+	videos, total, err := h.service.FuzzySearch(c.Request.Context(), query, c.GetString("userID"), usernameOnly, filters, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Search failed",
-			"code":  "SEARCH_ERROR",
-		})
+		respondInternalError(c, "Search failed", "SEARCH_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	if !h.applyViewerState(c, videos) {
+		return
+	}
+
+	response := gin.H{
 		"videos":       videos,
 		"total":        total,
 		"query":        query,
 		"usernameOnly": usernameOnly,
+		"filters":      filters,
 		"page":         (offset / limit) + 1,
 		"limit":        limit,
 		"hasMore":      len(videos) == limit,
 		"cached_at":    time.Now().Unix(),
-		"ttl":          900,
-	})
+		"ttl":          ttl,
+	}
+	if safety != nil {
+		response["safety"] = safety
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseVideoSearchFilters reads minPrice, maxPrice, verifiedOnly and mediaType from the
+// query string, writing a 400 and reporting false when one is malformed
+func parseVideoSearchFilters(c *gin.Context) (models.VideoSearchFilters, bool) {
+	var filters models.VideoSearchFilters
+
+	for _, bound := range []struct {
+		param  string
+		target **float64
+	}{
+		{"minPrice", &filters.MinPrice},
+		{"maxPrice", &filters.MaxPrice},
+	} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(raw, 64)
+		if err != nil || price < 0 {
+			respondError(c, http.StatusBadRequest, bound.param+" must be a non-negative number", "INVALID_PRICE_FILTER")
+			return filters, false
+		}
+		*bound.target = &price
+	}
+	if filters.MinPrice != nil && filters.MaxPrice != nil && *filters.MinPrice > *filters.MaxPrice {
+		respondError(c, http.StatusBadRequest, "minPrice cannot be greater than maxPrice", "INVALID_PRICE_FILTER")
+		return filters, false
+	}
+
+	filters.VerifiedOnly = c.Query("verifiedOnly") == "true"
+
+	switch mediaType := c.Query("mediaType"); mediaType {
+	case "", "all":
+	case "video", "image":
+		filters.MediaType = mediaType
+	default:
+		respondError(c, http.StatusBadRequest, "mediaType must be one of video, image, all", "INVALID_MEDIA_TYPE")
+		return filters, false
+	}
+
+	return filters, true
 }
 
 // ===============================
@@ -123,7 +251,7 @@ func (h *VideoHandler) SearchVideos(c *gin.Context) {
 // ===============================
 
 func (h *VideoHandler) GetPopularSearchTerms(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	limit := 10
 	if l := c.Query("limit"); l != "" {
@@ -134,10 +262,7 @@ func (h *VideoHandler) GetPopularSearchTerms(c *gin.Context) {
 
 	terms, err := h.service.GetPopularSearchTerms(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get popular search terms",
-			"code":  "SEARCH_TERMS_ERROR",
-		})
+		respondInternalError(c, "Failed to get popular search terms", "SEARCH_TERMS_ERROR", err)
 		return
 	}
 
@@ -146,7 +271,51 @@ func (h *VideoHandler) GetPopularSearchTerms(c *gin.Context) {
 		"total":     len(terms),
 		"limit":     limit,
 		"cached_at": time.Now().Unix(),
-		"ttl":       1800,
+		"ttl":       ttl,
+	})
+}
+
+// maxSearchSuggestions caps type-ahead results; the dropdown shows a handful at most
+const maxSearchSuggestions = 10
+
+// GetSearchSuggestions powers the search box type-ahead. It is called on (debounced)
+// keystrokes, so an empty prefix is an empty list rather than an error and results carry
+// a short cache lifetime.
+func (h *VideoHandler) GetSearchSuggestions(c *gin.Context) {
+	// Blocks and private follows shape the list, so signed-in results must not be shared
+	if c.GetString("userID") != "" {
+		c.Header("Cache-Control", "private, max-age=60")
+	} else {
+		c.Header("Cache-Control", "public, max-age=60")
+	}
+
+	query := c.Query("q")
+
+	limit := 5
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= maxSearchSuggestions {
+			limit = parsed
+		}
+	}
+
+	// Don't complete sensitive queries the search results would withhold
+	if safety := h.service.CheckSearchSafety(query); safety != nil && safety.WithholdsResults() {
+		c.JSON(http.StatusOK, gin.H{
+			"suggestions": []models.SearchSuggestion{},
+			"query":       query,
+		})
+		return
+	}
+
+	suggestions, err := h.service.GetSearchSuggestions(c.Request.Context(), query, c.GetString("userID"), limit)
+	if err != nil {
+		respondInternalError(c, "Failed to get search suggestions", "SEARCH_SUGGESTIONS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+		"query":       query,
 	})
 }
 
@@ -176,10 +345,7 @@ func (h *VideoHandler) GetSearchHistory(c *gin.Context) {
 
 	history, err := h.service.GetSearchHistory(c.Request.Context(), userID, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get search history",
-			"code":  "HISTORY_ERROR",
-		})
+		respondInternalError(c, "Failed to get search history", "HISTORY_ERROR", err)
 		return
 	}
 
@@ -208,11 +374,7 @@ func (h *VideoHandler) AddSearchHistory(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"code":    "INVALID_REQUEST",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
@@ -226,10 +388,7 @@ func (h *VideoHandler) AddSearchHistory(c *gin.Context) {
 
 	err := h.service.AddSearchHistory(c.Request.Context(), userID, request.Query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to add search history",
-			"code":  "ADD_HISTORY_ERROR",
-		})
+		respondInternalError(c, "Failed to add search history", "ADD_HISTORY_ERROR", err)
 		return
 	}
 
@@ -254,10 +413,7 @@ func (h *VideoHandler) ClearSearchHistory(c *gin.Context) {
 
 	err := h.service.ClearSearchHistory(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to clear search history",
-			"code":  "CLEAR_HISTORY_ERROR",
-		})
+		respondInternalError(c, "Failed to clear search history", "CLEAR_HISTORY_ERROR", err)
 		return
 	}
 
@@ -291,10 +447,7 @@ func (h *VideoHandler) RemoveSearchHistory(c *gin.Context) {
 
 	err := h.service.RemoveSearchHistory(c.Request.Context(), userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to remove search history",
-			"code":  "REMOVE_HISTORY_ERROR",
-		})
+		respondInternalError(c, "Failed to remove search history", "REMOVE_HISTORY_ERROR", err)
 		return
 	}
 
@@ -309,12 +462,13 @@ func (h *VideoHandler) RemoveSearchHistory(c *gin.Context) {
 // ===============================
 
 func (h *VideoHandler) GetVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	params := models.VideoSearchParams{
-		Limit:  20,
-		Offset: 0,
-		SortBy: "latest",
+		Limit:    20,
+		Offset:   0,
+		SortBy:   "latest",
+		ViewerID: c.GetString("userID"),
 	}
 
 	if l := c.Query("limit"); l != "" {
@@ -357,10 +511,11 @@ func (h *VideoHandler) GetVideos(c *gin.Context) {
 
 	videos, err := h.service.GetVideosOptimized(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch videos",
-			"code":  "FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch videos", "FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -371,7 +526,7 @@ func (h *VideoHandler) GetVideos(c *gin.Context) {
 		"limit":     params.Limit,
 		"hasMore":   len(videos) == params.Limit,
 		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"ttl":       ttl,
 	})
 }
 
@@ -384,11 +539,7 @@ func (h *VideoHandler) GetVideosBulk(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"code":    "INVALID_REQUEST",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
@@ -408,12 +559,13 @@ func (h *VideoHandler) GetVideosBulk(c *gin.Context) {
 		return
 	}
 
-	videos, err := h.service.GetVideosBulk(c.Request.Context(), request.VideoIDs, request.IncludeInactive)
+	videos, err := h.service.GetVideosBulk(c.Request.Context(), request.VideoIDs, c.GetString("userID"), request.IncludeInactive)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch videos",
-			"code":  "BULK_FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch videos", "BULK_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -427,7 +579,7 @@ func (h *VideoHandler) GetVideosBulk(c *gin.Context) {
 }
 
 func (h *VideoHandler) GetFeaturedVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	limit := 10
 	if l := c.Query("limit"); l != "" {
@@ -436,12 +588,13 @@ func (h *VideoHandler) GetFeaturedVideos(c *gin.Context) {
 		}
 	}
 
-	videos, err := h.service.GetFeaturedVideosOptimized(c.Request.Context(), limit)
+	videos, err := h.service.GetFeaturedVideosOptimized(c.Request.Context(), c.GetString("userID"), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch featured videos",
-			"code":  "FEATURED_FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch featured videos", "FEATURED_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -450,12 +603,12 @@ func (h *VideoHandler) GetFeaturedVideos(c *gin.Context) {
 		"total":     len(videos),
 		"featured":  true,
 		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"ttl":       ttl,
 	})
 }
 
 func (h *VideoHandler) GetTrendingVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	limit := 10
 	if l := c.Query("limit"); l != "" {
@@ -464,12 +617,13 @@ func (h *VideoHandler) GetTrendingVideos(c *gin.Context) {
 		}
 	}
 
-	videos, err := h.service.GetTrendingVideosOptimized(c.Request.Context(), limit)
+	videos, err := h.service.GetTrendingVideosOptimized(c.Request.Context(), c.GetString("userID"), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch trending videos",
-			"code":  "TRENDING_FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch trending videos", "TRENDING_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -478,7 +632,96 @@ func (h *VideoHandler) GetTrendingVideos(c *gin.Context) {
 		"total":     len(videos),
 		"trending":  true,
 		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"ttl":       ttl,
+	})
+}
+
+// ===============================
+// 🏷️ TRENDING TAGS
+// ===============================
+
+func (h *VideoHandler) GetTrendingTags(c *gin.Context) {
+	ttl := h.setVideoListHeaders(c)
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	weighted := c.Query("weighted") == "true"
+
+	tags, err := h.service.GetTrendingTags(c.Request.Context(), limit, weighted)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch trending tags", "TRENDING_TAGS_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":      tags,
+		"total":     len(tags),
+		"weighted":  weighted,
+		"cached_at": time.Now().Unix(),
+		"ttl":       ttl,
+	})
+}
+
+func (h *VideoHandler) GetVideosByTag(c *gin.Context) {
+	ttl := h.setVideoListHeaders(c)
+
+	tag := strings.TrimSpace(strings.TrimPrefix(c.Param("tag"), "#"))
+	if tag == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tag required",
+			"code":  "MISSING_TAG",
+		})
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort", "recent")
+	if sortBy != "recent" && sortBy != "trending" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid sort, expected recent or trending",
+			"code":  "INVALID_SORT",
+		})
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	videos, err := h.service.GetVideosByTag(c.Request.Context(), tag, sortBy, c.GetString("userID"), limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch videos for tag", "TAG_VIDEOS_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videos":    videos,
+		"total":     len(videos),
+		"tag":       strings.ToLower(tag),
+		"sort":      sortBy,
+		"page":      (offset / limit) + 1,
+		"limit":     limit,
+		"hasMore":   len(videos) == limit,
+		"cached_at": time.Now().Unix(),
+		"ttl":       ttl,
 	})
 }
 
@@ -494,7 +737,7 @@ func (h *VideoHandler) GetVideo(c *gin.Context) {
 		return
 	}
 
-	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID)
+	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID, c.GetString("userID"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   "Video not found",
@@ -504,16 +747,166 @@ func (h *VideoHandler) GetVideo(c *gin.Context) {
 		return
 	}
 
-	if video.VideoURL != "" {
+	// Priced videos only expose their media to the owner and buyers
+	if video.Price > 0 {
+		access, err := h.service.GetVideoAccess(c.Request.Context(), videoID, c.GetString("userID"))
+		if err != nil {
+			respondInternalError(c, "Failed to check video access", "ACCESS_CHECK_ERROR", err)
+			return
+		}
+
+		video.IsPurchased = access.IsPurchased
+		video.UserBalance = access.UserBalance
+		video.CanUnlock = access.CanUnlock
+		if !access.HasAccess {
+			video.IsLocked = true
+			video.VideoURL = ""
+			video.ImageUrls = models.StringSlice{}
+		}
+
+		// Response depends on the viewer, so shared caches must not store it
+		c.Header("Cache-Control", "private, no-cache")
+	}
+
+	if video.VideoURL != "" && !video.IsLocked && video.Price <= 0 {
 		h.setVideoStreamingHeaders(c)
 	}
 
+	// isSaved is per viewer, so signed-in responses stay out of shared caches
+	if c.GetString("userID") != "" && video.Price <= 0 {
+		c.Header("Cache-Control", strings.Replace(c.Writer.Header().Get("Cache-Control"), "public", "private", 1))
+	}
+
+	if notModified(c, contentETag(video)) {
+		return
+	}
 	c.JSON(http.StatusOK, video)
 }
 
-func (h *VideoHandler) GetVideoQualities(c *gin.Context) {
-	h.setVideoStreamingHeaders(c)
+// PurchaseVideo buys access to a priced video with wallet coins
+func (h *VideoHandler) PurchaseVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
+		return
+	}
+
+	purchase, err := h.purchaseService.PurchaseVideo(c.Request.Context(), userID, videoID)
+	if err != nil {
+		switch err.Error() {
+		case "video_not_found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found", "code": "VIDEO_NOT_FOUND"})
+		case "cannot_purchase_own_video":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot purchase your own video", "code": "OWN_VIDEO"})
+		case "video_not_for_sale":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This video is free", "code": "VIDEO_NOT_FOR_SALE"})
+		case "already_purchased":
+			c.JSON(http.StatusConflict, gin.H{"error": "Video already purchased", "code": "ALREADY_PURCHASED"})
+		case "insufficient_balance":
+			respondInsufficientBalance(c, err)
+		case "wallet_not_found":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Wallet not found", "code": "WALLET_NOT_FOUND"})
+		default:
+			respondInternalError(c, "Failed to purchase video", "PURCHASE_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Video purchased successfully",
+		"purchase":    purchase,
+		"isPurchased": true,
+	})
+}
+
+// StreamVideo proxies the video bytes from R2 with Range support so priced videos can be
+// played without exposing their storage URL
+func (h *VideoHandler) StreamVideo(c *gin.Context) {
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
+		return
+	}
+
+	access, err := h.service.GetVideoAccess(c.Request.Context(), videoID, c.GetString("userID"))
+	if err != nil {
+		if err.Error() == "video_not_found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found", "code": "VIDEO_NOT_FOUND"})
+			return
+		}
+		respondInternalError(c, "Failed to check video access", "ACCESS_CHECK_ERROR", err)
+		return
+	}
+
+	if !access.HasAccess {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       "Purchase required to watch this video",
+			"code":        "PURCHASE_REQUIRED",
+			"price":       access.Price,
+			"userBalance": access.UserBalance,
+			"canUnlock":   access.CanUnlock,
+		})
+		return
+	}
+
+	stream, err := h.service.OpenVideoStream(c.Request.Context(), access.VideoURL, c.GetHeader("Range"))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrInvalidRange):
+			c.Header("Content-Range", "bytes */*")
+			c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": "Requested range not satisfiable", "code": "INVALID_RANGE"})
+		case err.Error() == "video_not_streamable":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video is not available for streaming", "code": "STREAM_UNAVAILABLE"})
+		case errors.Is(err, storage.ErrStorageUnavailable):
+			respondStorageUnavailable(c, err)
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to stream video", "code": "STREAM_ERROR"})
+		}
+		return
+	}
+	defer stream.Body.Close()
+
+	if access.IsPaid() {
+		// Paid bytes must not be stored by shared caches
+		cacheControl, _ := maxAge("private", h.cacheTTLs.VideoStream)
+		c.Header("Accept-Ranges", "bytes")
+		c.Header("Cache-Control", cacheControl)
+		c.Header("X-Content-Type-Options", "nosniff")
+	} else {
+		h.setVideoStreamingHeaders(c)
+	}
+
+	headers := map[string]string{}
+	if stream.ETag != "" {
+		headers["ETag"] = stream.ETag
+	}
+	if !stream.LastModified.IsZero() {
+		headers["Last-Modified"] = stream.LastModified.UTC().Format(http.TimeFormat)
+	}
+
+	status := http.StatusOK
+	if stream.ContentRange != "" {
+		status = http.StatusPartialContent
+		headers["Content-Range"] = stream.ContentRange
+	}
+
+	contentType := stream.ContentType
+	if contentType == "" {
+		contentType = "video/mp4"
+	}
+
+	c.DataFromReader(status, stream.ContentLength, contentType, stream.Body, headers)
+}
 
+func (h *VideoHandler) GetVideoQualities(c *gin.Context) {
 	videoID := c.Param("videoId")
 	if videoID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -523,7 +916,7 @@ func (h *VideoHandler) GetVideoQualities(c *gin.Context) {
 		return
 	}
 
-	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID)
+	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID, c.GetString("userID"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Video not found",
@@ -532,6 +925,29 @@ func (h *VideoHandler) GetVideoQualities(c *gin.Context) {
 		return
 	}
 
+	// Quality URLs are the media itself, so priced videos go through the same gate as GetVideo
+	if video.Price > 0 {
+		access, err := h.service.GetVideoAccess(c.Request.Context(), videoID, c.GetString("userID"))
+		if err != nil {
+			respondInternalError(c, "Failed to check video access", "ACCESS_CHECK_ERROR", err)
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-cache")
+		if !access.HasAccess {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":       "Purchase required to watch this video",
+				"code":        "PURCHASE_REQUIRED",
+				"price":       access.Price,
+				"userBalance": access.UserBalance,
+				"canUnlock":   access.CanUnlock,
+			})
+			return
+		}
+	} else {
+		h.setVideoStreamingHeaders(c)
+	}
+
 	qualities := []gin.H{
 		{
 			"quality":    "original",
@@ -563,7 +979,7 @@ func (h *VideoHandler) GetVideoQualities(c *gin.Context) {
 }
 
 func (h *VideoHandler) GetUserVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	userID := c.Param("userId")
 	if userID == "" {
@@ -588,12 +1004,20 @@ func (h *VideoHandler) GetUserVideos(c *gin.Context) {
 		}
 	}
 
-	videos, err := h.service.GetUserVideosOptimized(c.Request.Context(), userID, limit, offset)
+	// Private accounts are listed only for approved followers, so signed-in responses vary
+	viewerID := c.GetString("userID")
+	if viewerID != "" {
+		cacheControl, _ := maxAge("private", h.cacheTTLs.VideoList)
+		c.Header("Cache-Control", cacheControl)
+	}
+
+	videos, err := h.service.GetUserVideosOptimized(c.Request.Context(), userID, viewerID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch user videos",
-			"code":  "USER_VIDEOS_FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch user videos", "USER_VIDEOS_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -605,7 +1029,7 @@ func (h *VideoHandler) GetUserVideos(c *gin.Context) {
 		"limit":     limit,
 		"hasMore":   len(videos) == limit,
 		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"ttl":       ttl,
 	})
 }
 
@@ -635,6 +1059,8 @@ func (h *VideoHandler) IncrementViews(c *gin.Context) {
 		return
 	}
 
+	h.rewardService.OnVideoViewed(videoID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "View counted successfully",
 		"videoId": videoID,
@@ -642,7 +1068,10 @@ func (h *VideoHandler) IncrementViews(c *gin.Context) {
 	})
 }
 
-func (h *VideoHandler) LikeVideo(c *gin.Context) {
+// RecordWatchTime stores how long the caller watched a video; the average completion rate
+// feeds the trending score. Sessions the service doesn't count (a repeat within the
+// dedupe window, or a video whose duration isn't known yet) are acknowledged, not failed.
+func (h *VideoHandler) RecordWatchTime(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
 	videoID := c.Param("videoId")
@@ -654,27 +1083,74 @@ func (h *VideoHandler) LikeVideo(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetString("userID")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-			"code":  "AUTH_REQUIRED",
-		})
+	var request struct {
+		WatchedMs int64 `json:"watchedMs" binding:"min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
 		return
 	}
 
-	err := h.service.LikeVideo(c.Request.Context(), videoID, userID)
+	avgCompletionRate, err := h.service.RecordWatchSession(c.Request.Context(), videoID, c.GetString("userID"),
+		request.WatchedMs)
 	if err != nil {
-		if err.Error() == "already_liked" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Video already liked",
+		switch err.Error() {
+		case "duplicate_watch_session", "duration_unknown":
+			c.JSON(http.StatusOK, gin.H{
+				"videoId": videoID,
+				"status":  "acknowledged",
+			})
+		case "video_not_found":
+			respondNotFound(c, "Video")
+		case "invalid_watch_time":
+			respondError(c, http.StatusBadRequest, "Invalid watch time", "INVALID_WATCH_TIME")
+		default:
+			respondInternalError(c, "Failed to record watch time", "RECORD_WATCH_TIME_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videoId":           videoID,
+		"avgCompletionRate": avgCompletionRate,
+		"status":            "success",
+	})
+}
+
+func (h *VideoHandler) LikeVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	err := h.service.LikeVideo(c.Request.Context(), videoID, userID)
+	if err != nil {
+		if respondRateLimited(c, err) {
+			return
+		}
+		if err.Error() == "already_liked" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Video already liked",
 				"code":  "ALREADY_LIKED",
 			})
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to like video",
-				"code":  "LIKE_ERROR",
-			})
+			respondInternalError(c, "Failed to like video", "LIKE_ERROR", err)
 		}
 		return
 	}
@@ -726,10 +1202,7 @@ func (h *VideoHandler) UnlikeVideo(c *gin.Context) {
 				"code":  "NOT_LIKED",
 			})
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to unlike video",
-				"code":  "UNLIKE_ERROR",
-			})
+			respondInternalError(c, "Failed to unlike video", "UNLIKE_ERROR", err)
 		}
 		return
 	}
@@ -766,10 +1239,7 @@ func (h *VideoHandler) ShareVideo(c *gin.Context) {
 
 	err := h.service.IncrementVideoShares(c.Request.Context(), videoID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to record share",
-			"code":  "SHARE_ERROR",
-		})
+		respondInternalError(c, "Failed to record share", "SHARE_ERROR", err)
 		return
 	}
 
@@ -803,35 +1273,383 @@ func (h *VideoHandler) GetVideoCountsSummary(c *gin.Context) {
 		return
 	}
 
-	summary, err := h.service.GetVideoCountsSummary(c.Request.Context(), videoID)
+	summary, err := h.service.GetVideoCountsSummary(c.Request.Context(), videoID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Video not found",
+			"code":  "VIDEO_NOT_FOUND",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetLikeStatus reports whether the caller has liked the video
+func (h *VideoHandler) GetLikeStatus(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	isLiked, err := h.service.CheckVideoLiked(c.Request.Context(), videoID, userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch like status", "LIKE_STATUS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videoId": videoID,
+		"isLiked": isLiked,
+	})
+}
+
+// GetVideoLikers lists the users who liked a video, most recent first
+func (h *VideoHandler) GetVideoLikers(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	limit, offset := parsePagination(c, 20, 100)
+
+	users, total, err := h.service.GetVideoLikers(c.Request.Context(), videoID, userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch video likers", "FETCH_LIKERS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse("users", users, len(users), total, limit, offset))
+}
+
+func (h *VideoHandler) GetUserLikedVideos(c *gin.Context) {
+	ttl := h.setVideoListHeaders(c)
+
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "User ID required",
+			"code":  "MISSING_USER_ID",
+		})
+		return
+	}
+
+	requestingUserID := c.GetString("userID")
+	if requestingUserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Access denied",
+			"code":  "ACCESS_DENIED",
+		})
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	videos, err := h.service.GetUserLikedVideosOptimized(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch liked videos", "LIKED_VIDEOS_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videos":    videos,
+		"total":     len(videos),
+		"userId":    userID,
+		"liked":     true,
+		"cached_at": time.Now().Unix(),
+		"ttl":       ttl,
+	})
+}
+
+// ===============================
+// 🔖 SAVED VIDEOS
+// ===============================
+
+func (h *VideoHandler) SaveVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	err := h.service.SaveVideo(c.Request.Context(), videoID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "video_not_found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Video not found",
+				"code":  "VIDEO_NOT_FOUND",
+			})
+		case "already_saved":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Video already saved",
+				"code":  "ALREADY_SAVED",
+			})
+		default:
+			respondInternalError(c, "Failed to save video", "SAVE_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Video saved successfully",
+		"videoId": videoID,
+		"isSaved": true,
+		"status":  "success",
+	})
+}
+
+func (h *VideoHandler) UnsaveVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	err := h.service.UnsaveVideo(c.Request.Context(), videoID, userID)
+	if err != nil {
+		if err.Error() == "not_saved" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Video not saved",
+				"code":  "NOT_SAVED",
+			})
+		} else {
+			respondInternalError(c, "Failed to unsave video", "UNSAVE_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Video removed from saved",
+		"videoId": videoID,
+		"isSaved": false,
+		"status":  "success",
+	})
+}
+
+func (h *VideoHandler) GetUserSavedVideos(c *gin.Context) {
+	// Saved videos are private to the owner
+	h.setInteractionHeaders(c)
+
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "User ID required",
+			"code":  "MISSING_USER_ID",
+		})
+		return
+	}
+
+	requestingUserID := c.GetString("userID")
+	if requestingUserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Access denied",
+			"code":  "ACCESS_DENIED",
+		})
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	videos, err := h.service.GetUserSavedVideosOptimized(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch saved videos", "SAVED_VIDEOS_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videos":  videos,
+		"total":   len(videos),
+		"userId":  userID,
+		"saved":   true,
+		"hasMore": len(videos) == limit,
+	})
+}
+
+// ===============================
+// 🙈 NOT INTERESTED
+// ===============================
+
+func (h *VideoHandler) HideVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	err := h.service.HideVideo(c.Request.Context(), videoID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "video_not_found":
+			respondNotFound(c, "Video")
+		case "already_hidden":
+			respondError(c, http.StatusBadRequest, "Video already marked as not interested", "ALREADY_HIDDEN")
+		default:
+			respondInternalError(c, "Failed to hide video", "HIDE_VIDEO_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "You won't see this video in your feed",
+		"videoId":  videoID,
+		"isHidden": true,
+		"status":   "success",
+	})
+}
+
+func (h *VideoHandler) UnhideVideo(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Video ID required",
+			"code":  "MISSING_VIDEO_ID",
+		})
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
+		})
+		return
+	}
+
+	err := h.service.UnhideVideo(c.Request.Context(), videoID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Video not found",
-			"code":  "VIDEO_NOT_FOUND",
-		})
+		if err.Error() == "not_hidden" {
+			respondError(c, http.StatusBadRequest, "Video is not hidden", "NOT_HIDDEN")
+		} else {
+			respondInternalError(c, "Failed to unhide video", "UNHIDE_VIDEO_ERROR", err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, summary)
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Video restored to your feed",
+		"videoId":  videoID,
+		"isHidden": false,
+		"status":   "success",
+	})
 }
 
-func (h *VideoHandler) GetUserLikedVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
+// GetHiddenVideos lists the caller's "not interested" videos so they can be undone
+func (h *VideoHandler) GetHiddenVideos(c *gin.Context) {
+	h.setInteractionHeaders(c)
 
-	userID := c.Param("userId")
+	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "User ID required",
-			"code":  "MISSING_USER_ID",
-		})
-		return
-	}
-
-	requestingUserID := c.GetString("userID")
-	if requestingUserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Access denied",
-			"code":  "ACCESS_DENIED",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+			"code":  "AUTH_REQUIRED",
 		})
 		return
 	}
@@ -850,22 +1668,21 @@ func (h *VideoHandler) GetUserLikedVideos(c *gin.Context) {
 		}
 	}
 
-	videos, err := h.service.GetUserLikedVideosOptimized(c.Request.Context(), userID, limit, offset)
+	videos, err := h.service.GetHiddenVideos(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch liked videos",
-			"code":  "LIKED_VIDEOS_FETCH_ERROR",
-		})
+		respondInternalError(c, "Failed to fetch hidden videos", "HIDDEN_VIDEOS_FETCH_ERROR", err)
+		return
+	}
+
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"videos":    videos,
-		"total":     len(videos),
-		"userId":    userID,
-		"liked":     true,
-		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"videos":  videos,
+		"total":   len(videos),
+		"userId":  userID,
+		"hasMore": len(videos) == limit,
 	})
 }
 
@@ -873,6 +1690,28 @@ func (h *VideoHandler) GetUserLikedVideos(c *gin.Context) {
 // ✅ UPDATED: AUTHENTICATED VIDEO ENDPOINTS - All Active Users Can Post
 // ===============================
 
+// ValidateVideo checks a draft post the way CreateVideo would, plus media reachability
+// and metadata, without publishing anything. Always 200 unless the check itself failed;
+// the result's valid/errors/warnings say whether the draft would publish.
+func (h *VideoHandler) ValidateVideo(c *gin.Context) {
+	var request models.ValidateVideoRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.service.ValidateVideoDraft(c.Request.Context(), request)
+	if err != nil {
+		if respondStorageUnavailable(c, err) {
+			return
+		}
+		respondInternalError(c, "Failed to validate video", "VALIDATE_VIDEO_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 func (h *VideoHandler) CreateVideo(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
@@ -888,30 +1727,19 @@ func (h *VideoHandler) CreateVideo(c *gin.Context) {
 	// Validate user exists and is active (no role restriction)
 	err := h.userService.ValidateUserForVideoCreation(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   "Video creation not allowed",
-			"code":    "USER_VALIDATION_FAILED",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusForbidden, "Video creation not allowed", "USER_VALIDATION_FAILED")
 		return
 	}
 
 	var request models.CreateVideoRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"code":    "INVALID_REQUEST",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
 	userName, userImage, _, err := h.userService.GetUserBasicInfo(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get user information",
-			"code":  "USER_INFO_ERROR",
-		})
+		respondInternalError(c, "Failed to get user information", "USER_INFO_ERROR", err)
 		return
 	}
 
@@ -939,6 +1767,11 @@ func (h *VideoHandler) CreateVideo(c *gin.Context) {
 		ImageUrls:        models.StringSlice(request.ImageUrls),
 	}
 
+	// Fall back to the thumbnail generated when the video was uploaded
+	if video.ThumbnailURL == "" && !video.IsMultipleImages && h.uploadService != nil {
+		video.ThumbnailURL = h.uploadService.GeneratedThumbnailURL(c.Request.Context(), video.VideoURL)
+	}
+
 	if request.Price != nil && *request.Price >= 0 {
 		video.Price = *request.Price
 	} else {
@@ -947,19 +1780,37 @@ func (h *VideoHandler) CreateVideo(c *gin.Context) {
 
 	videoID, err := h.service.CreateVideoOptimized(c.Request.Context(), video)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create video",
-			"code":  "CREATE_ERROR",
-		})
+		switch err.Error() {
+		case "too_many_tags":
+			respondError(c, http.StatusBadRequest, "A video can have at most 30 tags", "TOO_MANY_TAGS")
+		case "caption_required":
+			respondError(c, http.StatusBadRequest, "Caption cannot be empty", "CAPTION_REQUIRED")
+		case "caption_too_long":
+			respondError(c, http.StatusBadRequest, "Caption must be 2200 characters or less", "CAPTION_TOO_LONG")
+		case "too_many_images":
+			respondError(c, http.StatusBadRequest,
+				fmt.Sprintf("A post can have at most %d images", models.GetMaxImagesPerPost()), "TOO_MANY_IMAGES")
+		case "content_rejected":
+			respondError(c, http.StatusUnprocessableEntity, "Caption contains disallowed content", "CONTENT_REJECTED")
+		case "duplicate_content":
+			respondError(c, http.StatusConflict, "This media has already been posted", "DUPLICATE_CONTENT")
+		default:
+			respondInternalError(c, "Failed to create video", "CREATE_ERROR", err)
+		}
 		return
 	}
 
+	// videos_count and last_post_at changed
+	h.userService.InvalidateProfile(userID)
+	h.rewardService.OnVideoPosted(userID)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"videoId":  videoID,
 		"message":  "Video created successfully",
 		"status":   "created",
 		"price":    video.Price,
 		"verified": video.IsVerified,
+		"tags":     video.Tags,
 	})
 }
 
@@ -968,19 +1819,19 @@ func (h *VideoHandler) UpdateVideo(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	var video models.Video
 	if err := c.ShouldBindJSON(&video); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -989,15 +1840,27 @@ func (h *VideoHandler) UpdateVideo(c *gin.Context) {
 
 	err := h.service.UpdateVideo(c.Request.Context(), &video)
 	if err != nil {
-		if err.Error() == "video_not_found_or_no_access" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found or access denied"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update video"})
+		switch err.Error() {
+		case "video_not_found_or_no_access":
+			respondError(c, http.StatusNotFound, "Video not found or access denied", "VIDEO_NOT_FOUND")
+		case "too_many_tags":
+			respondError(c, http.StatusBadRequest, "A video can have at most 30 tags", "TOO_MANY_TAGS")
+		case "caption_required":
+			respondError(c, http.StatusBadRequest, "Caption cannot be empty", "CAPTION_REQUIRED")
+		case "caption_too_long":
+			respondError(c, http.StatusBadRequest, "Caption must be 2200 characters or less", "CAPTION_TOO_LONG")
+		case "content_rejected":
+			respondError(c, http.StatusUnprocessableEntity, "Caption contains disallowed content", "CONTENT_REJECTED")
+		default:
+			respondInternalError(c, "Failed to update video", "UPDATE_VIDEO_ERROR", err)
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Video updated successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Video updated successfully",
+		"tags":    video.Tags,
+	})
 }
 
 func (h *VideoHandler) DeleteVideo(c *gin.Context) {
@@ -1005,22 +1868,22 @@ func (h *VideoHandler) DeleteVideo(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	err := h.service.DeleteVideo(c.Request.Context(), videoID, userID)
 	if err != nil {
 		if err.Error() == "video_not_found_or_no_access" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found or access denied"})
+			respondError(c, http.StatusNotFound, "Video not found or access denied", "VIDEO_NOT_FOUND")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete video"})
+			respondInternalError(c, "Failed to delete video", "DELETE_VIDEO_ERROR", err)
 		}
 		return
 	}
@@ -1033,33 +1896,57 @@ func (h *VideoHandler) GetFollowingFeed(c *gin.Context) {
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	limit := 20
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
-		}
+	limit, offset := parsePagination(c, 20, 100)
+
+	videos, total, err := h.service.GetFollowingVideoFeed(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch following feed", "FETCH_FOLLOWING_FEED_ERROR", err)
+		return
 	}
 
-	offset := 0
-	if o := c.Query("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
+	if !h.applyViewerState(c, videos) {
+		return
+	}
+
+	// Loading the top of the feed clears the "new posts" badge
+	if offset == 0 {
+		if err := h.service.MarkFollowingFeedSeen(c.Request.Context(), userID); err != nil {
+			log.Printf("⚠️ Failed to mark following feed seen for %s: %v", userID, err)
 		}
 	}
 
-	videos, err := h.service.GetFollowingVideoFeed(c.Request.Context(), userID, limit, offset)
+	c.JSON(http.StatusOK, paginatedResponse("videos", videos, len(videos), total, limit, offset))
+}
+
+// GetFollowingNewCount returns how many videos followed creators have posted since the
+// user last loaded the following feed, for the "new posts" badge
+func (h *VideoHandler) GetFollowingNewCount(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	count, err := h.service.GetFollowingNewCount(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch following feed"})
+		respondInternalError(c, "Failed to count new following videos", "FOLLOWING_NEW_COUNT_ERROR", err)
 		return
 	}
 
+	capped := count > services.MaxFollowingNewCount
+	if capped {
+		count = services.MaxFollowingNewCount
+	}
+
+	c.Header("Cache-Control", "private, no-cache")
 	c.JSON(http.StatusOK, gin.H{
-		"videos": videos,
-		"total":  len(videos),
+		"count":  count,
+		"capped": capped,
+		"hasNew": count > 0,
 	})
 }
 
@@ -1072,25 +1959,25 @@ func (h *VideoHandler) CreateComment(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	var request models.CreateCommentRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userName, userImage, _, err := h.userService.GetUserBasicInfo(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
+		respondInternalError(c, "User not found", "USER_NOT_FOUND_ERROR", err)
 		return
 	}
 
@@ -1107,22 +1994,35 @@ func (h *VideoHandler) CreateComment(c *gin.Context) {
 
 	commentID, err := h.service.CreateComment(c.Request.Context(), comment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		if respondRateLimited(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "user_blocked":
+			respondError(c, http.StatusForbidden, "You cannot comment on this video", "USER_BLOCKED")
+		case "content_rejected":
+			respondError(c, http.StatusUnprocessableEntity, "Comment contains disallowed content", "CONTENT_REJECTED")
+		default:
+			respondInternalError(c, "Failed to create comment", "CREATE_COMMENT_ERROR", err)
+		}
 		return
 	}
 
+	h.notificationService.NotifyMentionsAsync(*comment)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"commentId": commentID,
+		"mentions":  comment.Mentions,
 		"message":   "Comment created successfully",
 	})
 }
 
 func (h *VideoHandler) GetVideoComments(c *gin.Context) {
-	h.setCommentHeaders(c)
+	ttl := h.setCommentHeaders(c)
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
@@ -1140,176 +2040,427 @@ func (h *VideoHandler) GetVideoComments(c *gin.Context) {
 		}
 	}
 
-	comments, err := h.service.GetVideoComments(c.Request.Context(), videoID, limit, offset)
+	sortBy := c.DefaultQuery("sortBy", "newest")
+	if sortBy != "newest" && sortBy != "oldest" && sortBy != "top" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid sortBy, expected newest, oldest or top",
+			"code":  "INVALID_SORT",
+		})
+		return
+	}
+
+	comments, err := h.service.GetVideoComments(c.Request.Context(), videoID, c.GetString("userID"), sortBy, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		respondInternalError(c, "Failed to fetch comments", "FETCH_COMMENTS_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"comments":  comments,
 		"total":     len(comments),
+		"sortBy":    sortBy,
 		"cached_at": time.Now().Unix(),
-		"ttl":       300,
+		"ttl":       ttl,
 	})
 }
 
+// GetCommentReplies lazy-loads a comment thread: replies oldest first, with reply count
+func (h *VideoHandler) GetCommentReplies(c *gin.Context) {
+	h.setCommentHeaders(c)
+
+	commentID := c.Param("commentId")
+	if commentID == "" {
+		respondError(c, http.StatusBadRequest, "Comment ID required", "MISSING_COMMENT_ID")
+		return
+	}
+
+	limit, offset := parsePagination(c, 20, 100)
+
+	replies, total, err := h.service.GetCommentReplies(c.Request.Context(), commentID, c.GetString("userID"), limit, offset)
+	if err != nil {
+		switch err.Error() {
+		case "invalid_id":
+			respondError(c, http.StatusBadRequest, "Invalid comment ID", "INVALID_ID")
+		case "comment_not_found":
+			respondNotFound(c, "Comment")
+		default:
+			respondInternalError(c, "Failed to fetch replies", "FETCH_REPLIES_ERROR", err)
+		}
+		return
+	}
+
+	response := paginatedResponse("replies", replies, len(replies), total, limit, offset)
+	response["commentId"] = commentID
+	response["replyCount"] = total
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *VideoHandler) DeleteComment(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
 	commentID := c.Param("commentId")
 	if commentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment ID required"})
+		respondError(c, http.StatusBadRequest, "Comment ID required", "MISSING_COMMENT_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	err := h.service.DeleteComment(c.Request.Context(), commentID, userID)
 	if err != nil {
-		if err.Error() == "access_denied" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		if err.Error() == "access_denied" {
+			respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
+		} else {
+			respondInternalError(c, "Failed to delete comment", "DELETE_COMMENT_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// PinComment pins a comment to the top of the owner's video
+func (h *VideoHandler) PinComment(c *gin.Context) {
+	h.updateCommentPin(c, true)
+}
+
+// UnpinComment removes the owner's pin from a comment
+func (h *VideoHandler) UnpinComment(c *gin.Context) {
+	h.updateCommentPin(c, false)
+}
+
+func (h *VideoHandler) updateCommentPin(c *gin.Context, pinned bool) {
+	h.setInteractionHeaders(c)
+
+	videoID := c.Param("videoId")
+	commentID := c.Param("commentId")
+	if videoID == "" || commentID == "" {
+		respondError(c, http.StatusBadRequest, "Video ID and comment ID required", "MISSING_ID")
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	var err error
+	if pinned {
+		err = h.service.PinComment(c.Request.Context(), videoID, commentID, userID)
+	} else {
+		err = h.service.UnpinComment(c.Request.Context(), videoID, commentID, userID)
+	}
+	if err != nil {
+		switch err.Error() {
+		case "invalid_id":
+			respondError(c, http.StatusBadRequest, "Invalid video or comment ID", "INVALID_ID")
+		case "video_not_found":
+			respondNotFound(c, "Video")
+		case "comment_not_found":
+			respondNotFound(c, "Comment")
+		case "access_denied":
+			respondError(c, http.StatusForbidden, "Only the video owner can pin comments", "NOT_VIDEO_OWNER")
+		case "cannot_pin_reply":
+			respondError(c, http.StatusBadRequest, "Replies cannot be pinned", "CANNOT_PIN_REPLY")
+		case "comment_not_pinned":
+			respondError(c, http.StatusBadRequest, "Comment is not pinned", "COMMENT_NOT_PINNED")
+		default:
+			respondInternalError(c, "Failed to update pinned comment", "PIN_COMMENT_ERROR", err)
+		}
+		return
+	}
+
+	message := "Comment pinned"
+	if !pinned {
+		message = "Comment unpinned"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":   message,
+		"videoId":   videoID,
+		"commentId": commentID,
+		"isPinned":  pinned,
+	})
+}
+
+func (h *VideoHandler) LikeComment(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	commentID := c.Param("commentId")
+	if commentID == "" {
+		respondError(c, http.StatusBadRequest, "Comment ID required", "MISSING_COMMENT_ID")
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	err := h.service.LikeComment(c.Request.Context(), commentID, userID)
+	if err != nil {
+		if err.Error() == "already_liked" {
+			respondError(c, http.StatusBadRequest, "Comment already liked", "COMMENT_ALREADY_LIKED")
+		} else {
+			respondInternalError(c, "Failed to like comment", "LIKE_COMMENT_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment liked successfully"})
+}
+
+func (h *VideoHandler) UnlikeComment(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	commentID := c.Param("commentId")
+	if commentID == "" {
+		respondError(c, http.StatusBadRequest, "Comment ID required", "MISSING_COMMENT_ID")
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	err := h.service.UnlikeComment(c.Request.Context(), commentID, userID)
+	if err != nil {
+		if err.Error() == "not_liked" {
+			respondError(c, http.StatusBadRequest, "Comment not liked", "COMMENT_NOT_LIKED")
+		} else {
+			respondInternalError(c, "Failed to unlike comment", "UNLIKE_COMMENT_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment unliked successfully"})
+}
+
+// ===============================
+// SOCIAL ENDPOINTS
+// ===============================
+
+func (h *VideoHandler) FollowUser(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	targetUserID := c.Param("userId")
+	if targetUserID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	requested, err := h.service.FollowUser(c.Request.Context(), userID, targetUserID)
+	if err != nil {
+		if respondRateLimited(c, err) {
+			return
+		}
+		if err.Error() == "cannot_follow_self" {
+			respondError(c, http.StatusBadRequest, "Cannot follow yourself", "CANNOT_FOLLOW_SELF")
+		} else if err.Error() == "already_following" {
+			respondError(c, http.StatusBadRequest, "Already following this user", "ALREADY_FOLLOWING")
+		} else if err.Error() == "already_requested" {
+			respondError(c, http.StatusConflict, "Follow request already sent", "FOLLOW_REQUEST_PENDING")
+		} else if err.Error() == "user_not_found" {
+			respondNotFound(c, "User")
+		} else if err.Error() == "user_blocked" {
+			respondError(c, http.StatusForbidden, "You cannot follow this user", "USER_BLOCKED")
+		} else {
+			respondInternalError(c, "Failed to follow user", "FOLLOW_USER_ERROR", err)
+		}
+		return
+	}
+
+	if requested {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Follow request sent",
+			"status":  models.FollowRequestPending,
+		})
+		return
+	}
+
+	h.userService.InvalidateProfile(userID, targetUserID)
+	h.rewardService.OnUserFollowed(targetUserID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "User followed successfully"})
+}
+
+func (h *VideoHandler) UnfollowUser(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	targetUserID := c.Param("userId")
+	if targetUserID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	err := h.service.UnfollowUser(c.Request.Context(), userID, targetUserID)
+	if err != nil {
+		if err.Error() == "not_following" {
+			respondError(c, http.StatusBadRequest, "Not following this user", "NOT_FOLLOWING")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+			respondInternalError(c, "Failed to unfollow user", "UNFOLLOW_USER_ERROR", err)
 		}
 		return
 	}
+	h.userService.InvalidateProfile(userID, targetUserID)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "User unfollowed successfully"})
 }
 
-func (h *VideoHandler) LikeComment(c *gin.Context) {
+// GetFollowRequests lists the pending requests to follow the caller's private account
+func (h *VideoHandler) GetFollowRequests(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
-	commentID := c.Param("commentId")
-	if commentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment ID required"})
-		return
-	}
-
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	err := h.service.LikeComment(c.Request.Context(), commentID, userID)
+	limit, offset := parsePagination(c, 20, 100)
+
+	requests, total, err := h.service.GetFollowRequests(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		if err.Error() == "already_liked" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Comment already liked"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like comment"})
-		}
+		respondInternalError(c, "Failed to fetch follow requests", "FETCH_FOLLOW_REQUESTS_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment liked successfully"})
+	c.JSON(http.StatusOK, paginatedResponse("requests", requests, len(requests), total, limit, offset))
 }
 
-func (h *VideoHandler) UnlikeComment(c *gin.Context) {
-	h.setInteractionHeaders(c)
+// ApproveFollowRequest accepts a pending request, making the requester a follower
+func (h *VideoHandler) ApproveFollowRequest(c *gin.Context) {
+	h.respondToFollowRequest(c, h.service.ApproveFollowRequest, "Follow request approved")
+}
 
-	commentID := c.Param("commentId")
-	if commentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment ID required"})
-		return
-	}
+// RejectFollowRequest declines a pending request
+func (h *VideoHandler) RejectFollowRequest(c *gin.Context) {
+	h.respondToFollowRequest(c, h.service.RejectFollowRequest, "Follow request rejected")
+}
+
+func (h *VideoHandler) respondToFollowRequest(c *gin.Context,
+	respond func(ctx context.Context, targetID, requestID string) (*models.UserFollowRequest, error), message string) {
+	h.setInteractionHeaders(c)
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	err := h.service.UnlikeComment(c.Request.Context(), commentID, userID)
+	request, err := respond(c.Request.Context(), userID, c.Param("requestId"))
 	if err != nil {
-		if err.Error() == "not_liked" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Comment not liked"})
+		if err.Error() == "follow_request_not_found" {
+			respondNotFound(c, "Follow request")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike comment"})
+			respondInternalError(c, "Failed to update follow request", "FOLLOW_REQUEST_ERROR", err)
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment unliked successfully"})
+	if request.Status == models.FollowRequestApproved {
+		h.userService.InvalidateProfile(request.RequesterID, userID)
+		h.rewardService.OnUserFollowed(userID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"request": request,
+	})
 }
 
-// ===============================
-// SOCIAL ENDPOINTS
-// ===============================
+// Follow-status lookups are capped per request to keep the ANY() list small
+const maxFollowStatusUserIDs = 100
 
-func (h *VideoHandler) FollowUser(c *gin.Context) {
+// GetFollowStatus returns uid -> bool for whether the viewer follows each requested user
+func (h *VideoHandler) GetFollowStatus(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
-	targetUserID := c.Param("userId")
-	if targetUserID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	userID := c.GetString("userID")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	var request struct {
+		UserIDs []string `json:"userIds" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if len(request.UserIDs) > maxFollowStatusUserIDs {
+		respondError(c, http.StatusBadRequest,
+			fmt.Sprintf("At most %d user IDs can be checked at once", maxFollowStatusUserIDs), "TOO_MANY_USER_IDS")
 		return
 	}
 
-	err := h.service.FollowUser(c.Request.Context(), userID, targetUserID)
+	status, err := h.service.GetFollowStatusBulk(c.Request.Context(), userID, request.UserIDs)
 	if err != nil {
-		if err.Error() == "cannot_follow_self" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot follow yourself"})
-		} else if err.Error() == "already_following" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Already following this user"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow user"})
-		}
+		respondInternalError(c, "Failed to fetch follow status", "FETCH_FOLLOW_STATUS_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User followed successfully"})
+	c.JSON(http.StatusOK, gin.H{"following": status})
 }
 
-func (h *VideoHandler) UnfollowUser(c *gin.Context) {
-	h.setInteractionHeaders(c)
-
-	targetUserID := c.Param("userId")
-	if targetUserID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
-		return
-	}
+func (h *VideoHandler) GetUserFollowers(c *gin.Context) {
+	h.setVideoListHeaders(c)
 
-	userID := c.GetString("userID")
+	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
-	err := h.service.UnfollowUser(c.Request.Context(), userID, targetUserID)
+	limit, offset := parsePagination(c, 20, 100)
+
+	users, total, err := h.service.GetUserFollowers(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		if err.Error() == "not_following" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Not following this user"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
-		}
+		respondInternalError(c, "Failed to fetch followers", "FETCH_FOLLOWERS_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User unfollowed successfully"})
+	c.JSON(http.StatusOK, paginatedResponse("users", users, len(users), total, limit, offset))
 }
 
-func (h *VideoHandler) GetUserFollowers(c *gin.Context) {
+func (h *VideoHandler) GetMutualFollowers(c *gin.Context) {
 	h.setVideoListHeaders(c)
 
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
-	limit := 20
+	viewerID := c.GetString("userID")
+	if viewerID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	limit := 3
 	if l := c.Query("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
 			limit = parsed
@@ -1323,51 +2474,66 @@ func (h *VideoHandler) GetUserFollowers(c *gin.Context) {
 		}
 	}
 
-	users, err := h.service.GetUserFollowers(c.Request.Context(), userID, limit, offset)
+	users, total, err := h.service.GetMutualFollowers(c.Request.Context(), viewerID, userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch followers"})
+		respondInternalError(c, "Failed to fetch mutual followers", "FETCH_MUTUAL_FOLLOWERS_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"users": users,
-		"total": len(users),
+		"users":   users,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(users) < total,
 	})
 }
 
-func (h *VideoHandler) GetUserFollowing(c *gin.Context) {
-	h.setVideoListHeaders(c)
+// GetCommonFollowing returns users that both the viewer and the target user follow
+func (h *VideoHandler) GetCommonFollowing(c *gin.Context) {
+	h.setInteractionHeaders(c)
 
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
-	limit := 20
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
-		}
+	viewerID := c.GetString("userID")
+	if viewerID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
 	}
 
-	offset := 0
-	if o := c.Query("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	limit, offset := parsePagination(c, 20, 100)
+
+	users, total, err := h.service.GetCommonFollowing(c.Request.Context(), viewerID, userID, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch mutuals", "FETCH_MUTUALS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse("users", users, len(users), total, limit, offset))
+}
+
+func (h *VideoHandler) GetUserFollowing(c *gin.Context) {
+	h.setVideoListHeaders(c)
+
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
 	}
 
-	users, err := h.service.GetUserFollowing(c.Request.Context(), userID, limit, offset)
+	limit, offset := parsePagination(c, 20, 100)
+
+	users, total, err := h.service.GetUserFollowing(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch following"})
+		respondInternalError(c, "Failed to fetch following", "FETCH_FOLLOWING_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"users": users,
-		"total": len(users),
-	})
+	c.JSON(http.StatusOK, paginatedResponse("users", users, len(users), total, limit, offset))
 }
 
 // ===============================
@@ -1379,7 +2545,7 @@ func (h *VideoHandler) ToggleFeatured(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
@@ -1388,20 +2554,23 @@ func (h *VideoHandler) ToggleFeatured(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.ToggleFeatured(c.Request.Context(), videoID, request.IsFeatured)
 	if err != nil {
 		if err.Error() == "video_not_found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+			respondNotFound(c, "Video")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle featured status"})
+			respondInternalError(c, "Failed to toggle featured status", "TOGGLE_FEATURED_STATUS_ERROR", err)
 		}
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditVideoFeatured,
+		models.AuditTargetVideo, videoID, models.MetadataMap{"isFeatured": request.IsFeatured})
+
 	status := "featured"
 	if !request.IsFeatured {
 		status = "unfeatured"
@@ -1415,7 +2584,7 @@ func (h *VideoHandler) ToggleActive(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
@@ -1424,20 +2593,23 @@ func (h *VideoHandler) ToggleActive(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	err := h.service.ToggleActive(c.Request.Context(), videoID, request.IsActive)
+	err := h.service.ToggleActive(c.Request.Context(), videoID, request.IsActive, c.GetString("userID"))
 	if err != nil {
 		if err.Error() == "video_not_found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+			respondNotFound(c, "Video")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle active status"})
+			respondInternalError(c, "Failed to toggle active status", "TOGGLE_ACTIVE_STATUS_ERROR", err)
 		}
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditVideoActive,
+		models.AuditTargetVideo, videoID, models.MetadataMap{"isActive": request.IsActive})
+
 	status := "activated"
 	if !request.IsActive {
 		status = "deactivated"
@@ -1451,7 +2623,7 @@ func (h *VideoHandler) ToggleVerified(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
@@ -1460,33 +2632,22 @@ func (h *VideoHandler) ToggleVerified(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID)
+	err := h.service.ToggleVerified(c.Request.Context(), videoID, request.IsVerified, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		if err.Error() == "video_not_found" {
+			respondNotFound(c, "Video")
+			return
+		}
+		respondInternalError(c, "Failed to update verification status", "UPDATE_VERIFICATION_STATUS_ERROR", err)
 		return
 	}
 
-	videoModel := &models.Video{
-		ID:               video.ID,
-		UserID:           video.UserID,
-		Caption:          video.Caption,
-		Price:            video.Price,
-		Tags:             video.Tags,
-		IsActive:         video.IsActive,
-		IsFeatured:       video.IsFeatured,
-		IsVerified:       request.IsVerified,
-		IsMultipleImages: video.IsMultipleImages,
-	}
-
-	err = h.service.UpdateVideo(c.Request.Context(), videoModel)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update verification status"})
-		return
-	}
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditVideoVerified,
+		models.AuditTargetVideo, videoID, models.MetadataMap{"isVerified": request.IsVerified})
 
 	status := "verified"
 	if !request.IsVerified {
@@ -1500,25 +2661,66 @@ func (h *VideoHandler) ToggleVerified(c *gin.Context) {
 	})
 }
 
+// GetMediaBreakdown returns the platform-wide split of active video and image posts
+func (h *VideoHandler) GetMediaBreakdown(c *gin.Context) {
+	breakdown, err := h.service.GetMediaBreakdown(c.Request.Context(), "")
+	if err != nil {
+		respondInternalError(c, "Failed to fetch media breakdown", "MEDIA_BREAKDOWN_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// GetPublicStats serves the landing page counters; no auth, cached by browsers and CDNs
+func (h *VideoHandler) GetPublicStats(c *gin.Context) {
+	stats, err := h.service.GetPublicStats(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to fetch platform stats", "PUBLIC_STATS_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=600")
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetUserMediaBreakdown returns how many active video and image posts a creator has
+func (h *VideoHandler) GetUserMediaBreakdown(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	breakdown, err := h.service.GetMediaBreakdown(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch media breakdown", "MEDIA_BREAKDOWN_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, breakdown)
+}
+
 func (h *VideoHandler) GetVideoStats(c *gin.Context) {
 	h.setVideoListHeaders(c)
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	stats, err := h.service.GetVideoStats(c.Request.Context(), userID)
+	limit, offset := parsePagination(c, 50, 100)
+
+	stats, total, err := h.service.GetVideoStats(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch video stats"})
+		respondInternalError(c, "Failed to fetch video stats", "FETCH_VIDEO_STATS_ERROR", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"stats": stats,
-		"total": len(stats),
-	})
+	c.JSON(http.StatusOK, paginatedResponse("stats", stats, len(stats), total, limit, offset))
 }
 
 // ===============================
@@ -1528,30 +2730,38 @@ func (h *VideoHandler) GetVideoStats(c *gin.Context) {
 func (h *VideoHandler) BatchUpdateCounts(c *gin.Context) {
 	h.setInteractionHeaders(c)
 
-	err := h.service.BatchUpdateViewCounts(c.Request.Context())
+	result, err := h.service.ReconcileCounts(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update counts"})
+		respondInternalError(c, "Failed to update counts", "UPDATE_COUNTS_ERROR", err)
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditVideoCountsRebuilt,
+		models.AuditTargetPlatform, "", models.MetadataMap{
+			"videosCorrected": result.VideosCorrected,
+			"usersCorrected":  result.UsersCorrected,
+		})
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "Counts updated successfully",
-		"timestamp": time.Now(),
+		"message":         "Counts updated successfully",
+		"videosCorrected": result.VideosCorrected,
+		"usersCorrected":  result.UsersCorrected,
+		"timestamp":       result.CompletedAt,
 	})
 }
 
 func (h *VideoHandler) GetVideoMetrics(c *gin.Context) {
-	h.setVideoAPIHeaders(c)
+	ttl := h.setVideoAPIHeaders(c)
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
-	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID)
+	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		respondNotFound(c, "Video")
 		return
 	}
 
@@ -1561,7 +2771,7 @@ func (h *VideoHandler) GetVideoMetrics(c *gin.Context) {
 		engagementRate = (float64(totalEngagement) / float64(video.ViewsCount)) * 100
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	metrics := gin.H{
 		"videoId":        video.ID,
 		"views":          video.ViewsCount,
 		"likes":          video.LikesCount,
@@ -1574,18 +2784,33 @@ func (h *VideoHandler) GetVideoMetrics(c *gin.Context) {
 		"createdAt":      video.CreatedAt,
 		"isActive":       video.IsActive,
 		"isFeatured":     video.IsFeatured,
-		"cached_at":      time.Now().Unix(),
-		"ttl":            1800,
-	})
+	}
+
+	// cached_at changes every request, so it stays out of the ETag
+	if notModified(c, contentETag(metrics)) {
+		return
+	}
+	metrics["cached_at"] = time.Now().Unix()
+	metrics["ttl"] = ttl
+	c.JSON(http.StatusOK, metrics)
 }
 
+// GetPopularVideos serves the day/week/month tabs from ?period (default week)
 func (h *VideoHandler) GetPopularVideos(c *gin.Context) {
-	h.setVideoListHeaders(c)
-
 	period := c.Query("period")
 	if period == "" {
 		period = "week"
 	}
+	h.respondTrendingInPeriod(c, period)
+}
+
+// GetTrendingInPeriod ranks videos by engagement that happened within :period only
+func (h *VideoHandler) GetTrendingInPeriod(c *gin.Context) {
+	h.respondTrendingInPeriod(c, c.Param("period"))
+}
+
+func (h *VideoHandler) respondTrendingInPeriod(c *gin.Context, period string) {
+	ttl := h.setVideoListHeaders(c)
 
 	limit := 20
 	if l := c.Query("limit"); l != "" {
@@ -1594,27 +2819,17 @@ func (h *VideoHandler) GetPopularVideos(c *gin.Context) {
 		}
 	}
 
-	var sortBy string
-	switch period {
-	case "day":
-		sortBy = "trending"
-	case "week":
-		sortBy = "popular"
-	case "month":
-		sortBy = "popular"
-	default:
-		sortBy = "popular"
-	}
-
-	params := models.VideoSearchParams{
-		Limit:  limit,
-		Offset: 0,
-		SortBy: sortBy,
+	videos, err := h.service.GetTrendingInPeriod(c.Request.Context(), period, c.GetString("userID"), limit)
+	if err != nil {
+		if err.Error() == "invalid_period" {
+			respondError(c, http.StatusBadRequest, "period must be one of day, week, month", "INVALID_PERIOD")
+			return
+		}
+		respondInternalError(c, "Failed to fetch popular videos", "FETCH_POPULAR_VIDEOS_ERROR", err)
+		return
 	}
 
-	videos, err := h.service.GetVideosOptimized(c.Request.Context(), params)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch popular videos"})
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -1622,14 +2837,13 @@ func (h *VideoHandler) GetPopularVideos(c *gin.Context) {
 		"videos":    videos,
 		"total":     len(videos),
 		"period":    period,
-		"sortBy":    sortBy,
 		"cached_at": time.Now().Unix(),
-		"ttl":       900,
+		"ttl":       ttl,
 	})
 }
 
 func (h *VideoHandler) GetVideoRecommendations(c *gin.Context) {
-	h.setVideoListHeaders(c)
+	ttl := h.setVideoListHeaders(c)
 
 	userID := c.GetString("userID")
 	limit := 20
@@ -1640,15 +2854,13 @@ func (h *VideoHandler) GetVideoRecommendations(c *gin.Context) {
 		}
 	}
 
-	params := models.VideoSearchParams{
-		Limit:  limit,
-		Offset: 0,
-		SortBy: "trending",
+	videos, err := h.service.GetPersonalizedRecommendations(c.Request.Context(), userID, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch recommendations", "FETCH_RECOMMENDATIONS_ERROR", err)
+		return
 	}
 
-	videos, err := h.service.GetVideosOptimized(c.Request.Context(), params)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recommendations"})
+	if !h.applyViewerState(c, videos) {
 		return
 	}
 
@@ -1656,10 +2868,10 @@ func (h *VideoHandler) GetVideoRecommendations(c *gin.Context) {
 		"videos":       videos,
 		"total":        len(videos),
 		"userId":       userID,
-		"algorithm":    "trending-based-optimized",
+		"algorithm":    "personalized-follows-tags",
 		"generated_at": time.Now(),
 		"cached_at":    time.Now().Unix(),
-		"ttl":          900,
+		"ttl":          ttl,
 	})
 }
 
@@ -1668,13 +2880,13 @@ func (h *VideoHandler) ReportVideo(c *gin.Context) {
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
@@ -1684,7 +2896,26 @@ func (h *VideoHandler) ReportVideo(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
+		return
+	}
+
+	reportID, err := h.service.ReportVideo(c.Request.Context(), videoID, userID, request.Reason, request.Description)
+	if err != nil {
+		switch err.Error() {
+		case "invalid_reason":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid report reason",
+				"code":    "INVALID_REASON",
+				"allowed": models.VideoReportReasons,
+			})
+		case "video_not_found":
+			respondNotFound(c, "Video")
+		case "already_reported":
+			respondError(c, http.StatusConflict, "You have already reported this video", "ALREADY_REPORTED")
+		default:
+			respondInternalError(c, "Failed to report video", "REPORT_ERROR", err)
+		}
 		return
 	}
 
@@ -1692,34 +2923,150 @@ func (h *VideoHandler) ReportVideo(c *gin.Context) {
 		"message":  "Video reported successfully",
 		"videoId":  videoID,
 		"reason":   request.Reason,
-		"reportId": "placeholder_report_id",
+		"reportId": reportID,
 		"status":   "pending_review",
 	})
 }
 
+// GetPendingModeration lists videos needing moderator attention (admin only)
+func (h *VideoHandler) GetPendingModeration(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	videos, total, err := h.service.GetPendingModeration(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch moderation queue", "MODERATION_QUEUE_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"videos":  videos,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(videos) < total,
+	})
+}
+
+// BulkModerateVideos applies deactivate/activate/feature/verify to up to 100 videos at once
+func (h *VideoHandler) BulkModerateVideos(c *gin.Context) {
+	h.setInteractionHeaders(c)
+
+	var request struct {
+		VideoIDs []string `json:"videoIds" binding:"required,min=1"`
+		Action   string   `json:"action" binding:"required,oneof=deactivate activate feature verify"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if len(request.VideoIDs) > models.MaxBulkModerationVideos {
+		respondError(c, http.StatusBadRequest,
+			fmt.Sprintf("At most %d videos can be moderated at once", models.MaxBulkModerationVideos), "TOO_MANY_VIDEO_IDS")
+		return
+	}
+
+	results, err := h.service.BulkModerateVideos(c.Request.Context(), request.VideoIDs, request.Action, c.GetString("userID"))
+	if err != nil {
+		if err.Error() == "invalid_action" {
+			respondError(c, http.StatusBadRequest, "Invalid moderation action", "INVALID_ACTION")
+		} else {
+			respondInternalError(c, "Failed to moderate videos", "BULK_MODERATION_ERROR", err)
+		}
+		return
+	}
+
+	succeeded := 0
+	updatedIDs := []string{}
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+			updatedIDs = append(updatedIDs, result.VideoID)
+		}
+	}
+
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditVideoBulkModerate,
+		models.AuditTargetVideo, "", models.MetadataMap{"action": request.Action, "videoIds": updatedIDs})
+
+	c.JSON(http.StatusOK, gin.H{
+		"action":    request.Action,
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// GetFlaggedComments lists comments held for review by the content moderator
+func (h *VideoHandler) GetFlaggedComments(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	comments, total, err := h.service.GetFlaggedComments(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch flagged comments", "FLAGGED_COMMENTS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"hasMore":  offset+len(comments) < total,
+	})
+}
+
 func (h *VideoHandler) GetVideoAnalytics(c *gin.Context) {
-	h.setVideoAPIHeaders(c)
+	ttl := h.setVideoAPIHeaders(c)
 
 	videoID := c.Param("videoId")
 	if videoID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video ID required"})
+		respondError(c, http.StatusBadRequest, "Video ID required", "MISSING_VIDEO_ID")
 		return
 	}
 
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
-	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID)
+	video, err := h.service.GetVideoOptimized(c.Request.Context(), videoID, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		respondNotFound(c, "Video")
 		return
 	}
 
 	if video.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 		return
 	}
 
@@ -1765,6 +3112,6 @@ func (h *VideoHandler) GetVideoAnalytics(c *gin.Context) {
 		"performance":     "good",
 		"optimized":       true,
 		"cached_at":       time.Now().Unix(),
-		"ttl":             1800,
+		"ttl":             ttl,
 	})
 }
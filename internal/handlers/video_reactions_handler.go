@@ -5,8 +5,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"weibaobe/internal/models"
 	"weibaobe/internal/services"
@@ -33,13 +35,13 @@ func NewVideoReactionsHandler(service *services.VideoReactionsService) *VideoRea
 func (h *VideoReactionsHandler) CreateVideoReactionChat(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	var request models.CreateVideoReactionChatRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -50,7 +52,11 @@ func (h *VideoReactionsHandler) CreateVideoReactionChat(c *gin.Context) {
 		&request.VideoReaction,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create chat", "details": err.Error()})
+		if err.Error() == "users are blocked" {
+			respondError(c, http.StatusForbidden, "Cannot start a chat with this user", "USER_BLOCKED")
+			return
+		}
+		respondInternalError(c, "Failed to create chat", "CREATE_CHAT_ERROR", err)
 		return
 	}
 
@@ -65,7 +71,7 @@ func (h *VideoReactionsHandler) CreateVideoReactionChat(c *gin.Context) {
 func (h *VideoReactionsHandler) GetUserChats(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
@@ -85,7 +91,7 @@ func (h *VideoReactionsHandler) GetUserChats(c *gin.Context) {
 
 	chats, err := h.service.GetUserChats(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chats", "details": err.Error()})
+		respondInternalError(c, "Failed to fetch chats", "FETCH_CHATS_ERROR", err)
 		return
 	}
 
@@ -108,24 +114,24 @@ func (h *VideoReactionsHandler) GetUserChats(c *gin.Context) {
 func (h *VideoReactionsHandler) GetChatByID(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	chat, err := h.service.GetChatByID(c.Request.Context(), chatID, userID)
 	if err != nil {
 		if err.Error() == "chat not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Chat not found"})
+			respondNotFound(c, "Chat")
 		} else if err.Error() == "access denied" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chat", "details": err.Error()})
+			respondInternalError(c, "Failed to fetch chat", "FETCH_CHAT_ERROR", err)
 		}
 		return
 	}
@@ -138,19 +144,19 @@ func (h *VideoReactionsHandler) GetChatByID(c *gin.Context) {
 func (h *VideoReactionsHandler) MarkChatAsRead(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	err := h.service.MarkChatAsRead(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark chat as read", "details": err.Error()})
+		respondInternalError(c, "Failed to mark chat as read", "MARK_CHAT_AS_READ_ERROR", err)
 		return
 	}
 
@@ -162,19 +168,19 @@ func (h *VideoReactionsHandler) MarkChatAsRead(c *gin.Context) {
 func (h *VideoReactionsHandler) ToggleChatPin(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	err := h.service.ToggleChatPin(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle pin", "details": err.Error()})
+		respondInternalError(c, "Failed to toggle pin", "TOGGLE_PIN_ERROR", err)
 		return
 	}
 
@@ -186,19 +192,19 @@ func (h *VideoReactionsHandler) ToggleChatPin(c *gin.Context) {
 func (h *VideoReactionsHandler) ToggleChatArchive(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	err := h.service.ToggleChatArchive(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle archive", "details": err.Error()})
+		respondInternalError(c, "Failed to toggle archive", "TOGGLE_ARCHIVE_ERROR", err)
 		return
 	}
 
@@ -210,19 +216,19 @@ func (h *VideoReactionsHandler) ToggleChatArchive(c *gin.Context) {
 func (h *VideoReactionsHandler) ToggleChatMute(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	err := h.service.ToggleChatMute(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle mute", "details": err.Error()})
+		respondInternalError(c, "Failed to toggle mute", "TOGGLE_MUTE_ERROR", err)
 		return
 	}
 
@@ -234,25 +240,25 @@ func (h *VideoReactionsHandler) ToggleChatMute(c *gin.Context) {
 func (h *VideoReactionsHandler) UpdateChatSettings(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	var request models.ChatSettingsRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.UpdateChatSettings(c.Request.Context(), chatID, userID, request.Wallpaper, request.FontSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update settings", "details": err.Error()})
+		respondInternalError(c, "Failed to update settings", "UPDATE_SETTINGS_ERROR", err)
 		return
 	}
 
@@ -264,13 +270,13 @@ func (h *VideoReactionsHandler) UpdateChatSettings(c *gin.Context) {
 func (h *VideoReactionsHandler) DeleteChat(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
@@ -278,7 +284,7 @@ func (h *VideoReactionsHandler) DeleteChat(c *gin.Context) {
 
 	err := h.service.DeleteChat(c.Request.Context(), chatID, userID, deleteForEveryone)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete chat", "details": err.Error()})
+		respondInternalError(c, "Failed to delete chat", "DELETE_CHAT_ERROR", err)
 		return
 	}
 
@@ -290,19 +296,19 @@ func (h *VideoReactionsHandler) DeleteChat(c *gin.Context) {
 func (h *VideoReactionsHandler) ClearChatHistory(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	err := h.service.ClearChatHistory(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear history", "details": err.Error()})
+		respondInternalError(c, "Failed to clear history", "CLEAR_HISTORY_ERROR", err)
 		return
 	}
 
@@ -318,19 +324,19 @@ func (h *VideoReactionsHandler) ClearChatHistory(c *gin.Context) {
 func (h *VideoReactionsHandler) SendMessage(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	var request models.SendMessageRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -341,7 +347,11 @@ func (h *VideoReactionsHandler) SendMessage(c *gin.Context) {
 
 	message, err := h.service.SendMessage(c.Request.Context(), chatID, userID, &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message", "details": err.Error()})
+		if err.Error() == "users are blocked" {
+			respondError(c, http.StatusForbidden, "Cannot send messages to this user", "USER_BLOCKED")
+			return
+		}
+		respondInternalError(c, "Failed to send message", "SEND_MESSAGE_ERROR", err)
 		return
 	}
 
@@ -356,13 +366,13 @@ func (h *VideoReactionsHandler) SendMessage(c *gin.Context) {
 func (h *VideoReactionsHandler) GetChatMessages(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
@@ -382,7 +392,7 @@ func (h *VideoReactionsHandler) GetChatMessages(c *gin.Context) {
 
 	response, err := h.service.GetChatMessages(c.Request.Context(), chatID, userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages", "details": err.Error()})
+		respondInternalError(c, "Failed to fetch messages", "FETCH_MESSAGES_ERROR", err)
 		return
 	}
 
@@ -394,25 +404,25 @@ func (h *VideoReactionsHandler) GetChatMessages(c *gin.Context) {
 func (h *VideoReactionsHandler) EditMessage(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	var request models.UpdateMessageRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.EditMessage(c.Request.Context(), messageID, userID, request.Content)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to edit message", "details": err.Error()})
+		respondInternalError(c, "Failed to edit message", "EDIT_MESSAGE_ERROR", err)
 		return
 	}
 
@@ -424,13 +434,13 @@ func (h *VideoReactionsHandler) EditMessage(c *gin.Context) {
 func (h *VideoReactionsHandler) DeleteMessage(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
@@ -438,7 +448,7 @@ func (h *VideoReactionsHandler) DeleteMessage(c *gin.Context) {
 
 	err := h.service.DeleteMessage(c.Request.Context(), messageID, userID, deleteForEveryone)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete message", "details": err.Error()})
+		respondInternalError(c, "Failed to delete message", "DELETE_MESSAGE_ERROR", err)
 		return
 	}
 
@@ -450,49 +460,137 @@ func (h *VideoReactionsHandler) DeleteMessage(c *gin.Context) {
 func (h *VideoReactionsHandler) ToggleMessagePin(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	err := h.service.ToggleMessagePin(c.Request.Context(), messageID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle pin", "details": err.Error()})
+		respondInternalError(c, "Failed to toggle pin", "TOGGLE_PIN_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Message pin toggled"})
 }
 
+// ReportMessage reports a chat message for moderator review
+// POST /api/v1/video-reactions/chats/:chatId/messages/:messageId/report
+func (h *VideoReactionsHandler) ReportMessage(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	chatID := c.Param("chatId")
+	messageID := c.Param("messageId")
+	if chatID == "" || messageID == "" {
+		respondError(c, http.StatusBadRequest, "Chat ID and message ID required", "MISSING_ID")
+		return
+	}
+
+	var request models.ReportMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.service.ReportMessage(c.Request.Context(), chatID, messageID, userID, request.Reason, request.Description)
+	if err != nil {
+		switch err.Error() {
+		case "invalid_reason":
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid report reason",
+				"code":    "INVALID_REASON",
+				"allowed": models.VideoReportReasons,
+			})
+		case "message_not_found":
+			respondNotFound(c, "Message")
+		case "access_denied":
+			respondError(c, http.StatusForbidden, "Only chat participants can report messages", "ACCESS_DENIED")
+		case "cannot_report_own_message":
+			respondError(c, http.StatusBadRequest, "You cannot report your own message", "CANNOT_REPORT_OWN_MESSAGE")
+		case "already_reported":
+			respondError(c, http.StatusConflict, "You have already reported this message", "ALREADY_REPORTED")
+		default:
+			respondInternalError(c, "Failed to report message", "REPORT_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Message reported successfully",
+		"messageId": messageID,
+		"reason":    request.Reason,
+		"reportId":  result.ReportID,
+		"status":    "pending_review",
+	})
+}
+
+// GetReportedMessages lists chat messages with open reports (admin only)
+// GET /api/v1/admin/messages/reported
+func (h *VideoReactionsHandler) GetReportedMessages(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	messages, total, err := h.service.GetReportedMessages(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch reported messages", "MODERATION_QUEUE_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"hasMore":  offset+len(messages) < total,
+	})
+}
+
 // AddMessageReaction adds a reaction to a message
 // POST /api/v1/video-reactions/messages/:messageId/reactions
 func (h *VideoReactionsHandler) AddMessageReaction(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	var request models.MessageReactionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.AddMessageReaction(c.Request.Context(), messageID, userID, request.Reaction)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reaction", "details": err.Error()})
+		respondInternalError(c, "Failed to add reaction", "ADD_REACTION_ERROR", err)
 		return
 	}
 
@@ -504,19 +602,19 @@ func (h *VideoReactionsHandler) AddMessageReaction(c *gin.Context) {
 func (h *VideoReactionsHandler) RemoveMessageReaction(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	err := h.service.RemoveMessageReaction(c.Request.Context(), messageID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction", "details": err.Error()})
+		respondInternalError(c, "Failed to remove reaction", "REMOVE_REACTION_ERROR", err)
 		return
 	}
 
@@ -528,19 +626,19 @@ func (h *VideoReactionsHandler) RemoveMessageReaction(c *gin.Context) {
 func (h *VideoReactionsHandler) MarkMessageAsDelivered(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	err := h.service.MarkMessageAsDelivered(c.Request.Context(), messageID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark as delivered", "details": err.Error()})
+		respondInternalError(c, "Failed to mark as delivered", "MARK_AS_DELIVERED_ERROR", err)
 		return
 	}
 
@@ -552,19 +650,19 @@ func (h *VideoReactionsHandler) MarkMessageAsDelivered(c *gin.Context) {
 func (h *VideoReactionsHandler) MarkMessageAsRead(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	messageID := c.Param("messageId")
 	if messageID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Message ID required"})
+		respondError(c, http.StatusBadRequest, "Message ID required", "MISSING_MESSAGE_ID")
 		return
 	}
 
 	err := h.service.MarkMessageAsRead(c.Request.Context(), messageID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark as read", "details": err.Error()})
+		respondInternalError(c, "Failed to mark as read", "MARK_AS_READ_ERROR", err)
 		return
 	}
 
@@ -576,19 +674,19 @@ func (h *VideoReactionsHandler) MarkMessageAsRead(c *gin.Context) {
 func (h *VideoReactionsHandler) SearchMessages(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	query := c.Query("q")
 	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query required"})
+		respondError(c, http.StatusBadRequest, "Search query required", "MISSING_SEARCH_QUERY")
 		return
 	}
 
@@ -601,7 +699,7 @@ func (h *VideoReactionsHandler) SearchMessages(c *gin.Context) {
 
 	messages, err := h.service.SearchMessages(c.Request.Context(), chatID, userID, query, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages", "details": err.Error()})
+		respondInternalError(c, "Failed to search messages", "SEARCH_MESSAGES_ERROR", err)
 		return
 	}
 
@@ -621,13 +719,13 @@ func (h *VideoReactionsHandler) SearchMessages(c *gin.Context) {
 func (h *VideoReactionsHandler) SetTypingIndicator(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
@@ -635,13 +733,13 @@ func (h *VideoReactionsHandler) SetTypingIndicator(c *gin.Context) {
 		IsTyping bool `json:"isTyping"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.SetTypingIndicator(c.Request.Context(), chatID, userID, request.IsTyping)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set typing indicator", "details": err.Error()})
+		respondInternalError(c, "Failed to set typing indicator", "SET_TYPING_INDICATOR_ERROR", err)
 		return
 	}
 
@@ -653,13 +751,13 @@ func (h *VideoReactionsHandler) SetTypingIndicator(c *gin.Context) {
 func (h *VideoReactionsHandler) GetTypingUsers(c *gin.Context) {
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	users, err := h.service.GetTypingUsers(c.Request.Context(), chatID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get typing users", "details": err.Error()})
+		respondInternalError(c, "Failed to get typing users", "GET_TYPING_USERS_ERROR", err)
 		return
 	}
 
@@ -669,6 +767,62 @@ func (h *VideoReactionsHandler) GetTypingUsers(c *gin.Context) {
 	})
 }
 
+// ===============================
+// EXPORT
+// ===============================
+
+// ExportChat downloads the chat transcript as JSON (default) or plain text
+// GET /api/v1/video-reactions/chats/:chatId/export?format=json|text
+func (h *VideoReactionsHandler) ExportChat(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
+		return
+	}
+
+	chatID := c.Param("chatId")
+	if chatID == "" {
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" {
+		respondError(c, http.StatusBadRequest, "Format must be json or text", "INVALID_FORMAT")
+		return
+	}
+
+	export, err := h.service.ExportChat(c.Request.Context(), chatID, userID)
+	if err != nil {
+		if err.Error() == "chat not found" {
+			respondNotFound(c, "Chat")
+		} else if err.Error() == "access denied" {
+			respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
+		} else {
+			respondInternalError(c, "Failed to export chat", "EXPORT_CHAT_ERROR", err)
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	filename := fmt.Sprintf("chat-%s-%s", chatID, export.ExportedAt.UTC().Format("20060102-150405"))
+
+	if format == "text" {
+		var transcript strings.Builder
+		for i := range export.Messages {
+			transcript.WriteString(export.Messages[i].TranscriptLine())
+			transcript.WriteString("\n")
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".txt"))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(transcript.String()))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+	c.JSON(http.StatusOK, export)
+}
+
 // ===============================
 // STATISTICS
 // ===============================
@@ -678,19 +832,19 @@ func (h *VideoReactionsHandler) GetTypingUsers(c *gin.Context) {
 func (h *VideoReactionsHandler) GetChatStats(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	chatID := c.Param("chatId")
 	if chatID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Chat ID required"})
+		respondError(c, http.StatusBadRequest, "Chat ID required", "MISSING_CHAT_ID")
 		return
 	}
 
 	stats, err := h.service.GetChatStats(c.Request.Context(), chatID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get stats", "details": err.Error()})
+		respondInternalError(c, "Failed to get stats", "GET_STATS_ERROR", err)
 		return
 	}
 
@@ -702,13 +856,13 @@ func (h *VideoReactionsHandler) GetChatStats(c *gin.Context) {
 func (h *VideoReactionsHandler) GetUserChatStats(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, http.StatusUnauthorized, "User not authenticated", "AUTH_REQUIRED")
 		return
 	}
 
 	stats, err := h.service.GetUserChatStats(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get stats", "details": err.Error()})
+		respondInternalError(c, "Failed to get stats", "GET_STATS_ERROR", err)
 		return
 	}
 
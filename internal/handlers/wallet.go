@@ -5,6 +5,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -15,33 +16,59 @@ import (
 )
 
 type WalletHandler struct {
-	service *services.WalletService
+	service       *services.WalletService
+	rewardService *services.RewardService
+	auditService  *services.AuditService
 }
 
-func NewWalletHandler(service *services.WalletService) *WalletHandler {
-	return &WalletHandler{service: service}
+func NewWalletHandler(service *services.WalletService, rewardService *services.RewardService, auditService *services.AuditService) *WalletHandler {
+	return &WalletHandler{service: service, rewardService: rewardService, auditService: auditService}
 }
 
 func (h *WalletHandler) GetWallet(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
 	wallet, err := h.service.GetWallet(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wallet"})
+		respondInternalError(c, "Failed to fetch wallet", "FETCH_WALLET_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, wallet)
 }
 
+// GetBalance returns only the caller's coin balance. It is polled often by the app
+// header, so it is a single-column read with a short private cache.
+func (h *WalletHandler) GetBalance(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	if userID != c.GetString("userID") {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
+		return
+	}
+
+	balance, err := h.service.GetBalanceSummary(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch balance", "FETCH_BALANCE_ERROR", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=5")
+	c.JSON(http.StatusOK, balance)
+}
+
 func (h *WalletHandler) GetTransactions(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
@@ -52,19 +79,91 @@ func (h *WalletHandler) GetTransactions(c *gin.Context) {
 		}
 	}
 
-	transactions, err := h.service.GetTransactions(c.Request.Context(), userID, limit)
+	includeArchived := c.Query("include_archived") == "true"
+
+	transactions, err := h.service.GetTransactions(c.Request.Context(), userID, limit, includeArchived)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+		respondInternalError(c, "Failed to fetch transactions", "FETCH_TRANSACTIONS_ERROR", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, transactions)
 }
 
+// GetDailyClaimStatus returns the caller's daily claim streak and when they can next claim
+func (h *WalletHandler) GetDailyClaimStatus(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	if userID != c.GetString("userID") {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+
+	status, err := h.rewardService.GetDailyClaimStatus(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "daily_reward_disabled" {
+			respondError(c, http.StatusNotFound, "Daily rewards are not available", "DAILY_REWARD_DISABLED")
+			return
+		}
+		respondInternalError(c, "Failed to fetch daily claim status", "DAILY_CLAIM_STATUS_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ClaimDaily credits the caller's daily coins, once per calendar day, with a streak bonus
+// for consecutive days
+func (h *WalletHandler) ClaimDaily(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
+		return
+	}
+
+	if userID != c.GetString("userID") {
+		respondError(c, http.StatusForbidden, "Access denied", "ACCESS_DENIED")
+		return
+	}
+
+	result, err := h.rewardService.ClaimDaily(c.Request.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "already_claimed":
+			body := gin.H{
+				"error": "Daily reward already claimed today, come back tomorrow",
+				"code":  "ALREADY_CLAIMED",
+			}
+			if status, statusErr := h.rewardService.GetDailyClaimStatus(c.Request.Context(), userID); statusErr == nil {
+				body["status"] = status
+			}
+			c.JSON(http.StatusConflict, body)
+		case "daily_reward_disabled":
+			respondError(c, http.StatusNotFound, "Daily rewards are not available", "DAILY_REWARD_DISABLED")
+		default:
+			respondInternalError(c, "Failed to claim daily reward", "DAILY_CLAIM_ERROR", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      fmt.Sprintf("Claimed %d coins", result.Claim.Coins),
+		"claim":        result.Claim,
+		"balanceAfter": result.BalanceAfter,
+		"status":       result.Status,
+	})
+}
+
 func (h *WalletHandler) CreatePurchaseRequest(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
@@ -75,14 +174,14 @@ func (h *WalletHandler) CreatePurchaseRequest(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Validate package exists
 	pkg, exists := models.CoinPackages[request.PackageID]
 	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		respondError(c, http.StatusBadRequest, "Invalid package ID", "INVALID_PACKAGE_ID")
 		return
 	}
 
@@ -98,7 +197,7 @@ func (h *WalletHandler) CreatePurchaseRequest(c *gin.Context) {
 
 	requestID, err := h.service.CreatePurchaseRequest(c.Request.Context(), purchaseRequest)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create purchase request"})
+		respondInternalError(c, "Failed to create purchase request", "CREATE_PURCHASE_REQUEST_ERROR", err)
 		return
 	}
 
@@ -112,7 +211,7 @@ func (h *WalletHandler) CreatePurchaseRequest(c *gin.Context) {
 func (h *WalletHandler) AddCoins(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID required"})
+		respondError(c, http.StatusBadRequest, "User ID required", "MISSING_USER_ID")
 		return
 	}
 
@@ -123,21 +222,29 @@ func (h *WalletHandler) AddCoins(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if request.CoinAmount <= 0 || request.CoinAmount > 10000 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid coin amount"})
+		respondError(c, http.StatusBadRequest, "Invalid coin amount", "INVALID_COIN_AMOUNT")
 		return
 	}
 
 	newBalance, err := h.service.AddCoins(c.Request.Context(), userID, request.CoinAmount, request.Description, request.AdminNote)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add coins"})
+		respondInternalError(c, "Failed to add coins", "ADD_COINS_ERROR", err)
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditWalletCoinsAdded,
+		models.AuditTargetUser, userID, models.MetadataMap{
+			"coinAmount":  request.CoinAmount,
+			"description": request.Description,
+			"adminNote":   request.AdminNote,
+			"newBalance":  newBalance,
+		})
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Coins added successfully",
 		"newBalance": newBalance,
@@ -154,7 +261,7 @@ func (h *WalletHandler) GetPendingPurchases(c *gin.Context) {
 
 	requests, err := h.service.GetPendingPurchases(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending purchases"})
+		respondInternalError(c, "Failed to fetch pending purchases", "FETCH_PENDING_PURCHASES_ERROR", err)
 		return
 	}
 
@@ -164,7 +271,7 @@ func (h *WalletHandler) GetPendingPurchases(c *gin.Context) {
 func (h *WalletHandler) ApprovePurchase(c *gin.Context) {
 	requestID := c.Param("requestId")
 	if requestID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Request ID required"})
+		respondError(c, http.StatusBadRequest, "Request ID required", "MISSING_REQUEST_ID")
 		return
 	}
 
@@ -176,17 +283,24 @@ func (h *WalletHandler) ApprovePurchase(c *gin.Context) {
 
 	err := h.service.ProcessPurchaseRequest(c.Request.Context(), requestID, "approved", request.AdminNote)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve purchase"})
+		if err.Error() == "request_already_processed" {
+			respondError(c, http.StatusConflict, "Purchase request already processed", "PURCHASE_ALREADY_PROCESSED")
+			return
+		}
+		respondInternalError(c, "Failed to approve purchase", "APPROVE_PURCHASE_ERROR", err)
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditPurchaseApproved,
+		models.AuditTargetPurchaseRequest, requestID, models.MetadataMap{"adminNote": request.AdminNote})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Purchase request approved and coins added"})
 }
 
 func (h *WalletHandler) RejectPurchase(c *gin.Context) {
 	requestID := c.Param("requestId")
 	if requestID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Request ID required"})
+		respondError(c, http.StatusBadRequest, "Request ID required", "MISSING_REQUEST_ID")
 		return
 	}
 
@@ -195,15 +309,18 @@ func (h *WalletHandler) RejectPurchase(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.service.ProcessPurchaseRequest(c.Request.Context(), requestID, "rejected", request.AdminNote)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject purchase"})
+		respondInternalError(c, "Failed to reject purchase", "REJECT_PURCHASE_ERROR", err)
 		return
 	}
 
+	h.auditService.Log(c.Request.Context(), c.GetString("userID"), models.AuditPurchaseRejected,
+		models.AuditTargetPurchaseRequest, requestID, models.MetadataMap{"adminNote": request.AdminNote})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Purchase request rejected"})
 }
@@ -0,0 +1,170 @@
+// ===============================
+// internal/handlers/webhook.go - Outbound Webhook Admin Handler
+// ===============================
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"weibaobe/internal/models"
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	service      *services.WebhookService
+	auditService *services.AuditService
+}
+
+func NewWebhookHandler(service *services.WebhookService, auditService *services.AuditService) *WebhookHandler {
+	return &WebhookHandler{service: service, auditService: auditService}
+}
+
+// respondWebhookError maps webhook service errors to responses
+func respondWebhookError(c *gin.Context, err error, message, code string) {
+	switch err.Error() {
+	case "invalid_url":
+		respondError(c, http.StatusBadRequest, "URL must be an absolute https URL on a public host", "INVALID_URL")
+	case "invalid_event":
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unknown webhook event",
+			"code":    "INVALID_EVENT",
+			"allowed": models.WebhookEvents,
+		})
+	case "webhook_not_found":
+		respondNotFound(c, "Webhook")
+	default:
+		respondInternalError(c, message, code, err)
+	}
+}
+
+// ListWebhooks returns every webhook subscription
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	webhooks, err := h.service.ListWebhooks(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to fetch webhooks", "WEBHOOK_FETCH_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+		"events":   models.WebhookEvents,
+	})
+}
+
+// CreateWebhook subscribes a URL to events. The signing secret is only returned here.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var request models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(c.Request.Context(), request, adminID)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to create webhook", "WEBHOOK_CREATE_ERROR")
+		return
+	}
+
+	h.auditService.Log(c.Request.Context(), adminID, models.AuditWebhookCreated,
+		models.AuditTargetWebhook, webhook.ID, models.MetadataMap{"url": webhook.URL, "events": webhook.Events})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created. Store the secret now; it is not shown again.",
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// UpdateWebhook changes a webhook's URL, events, description or active flag
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	adminID := c.GetString("userID")
+	webhookID := c.Param("webhookId")
+
+	var request models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(c.Request.Context(), webhookID, request)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to update webhook", "WEBHOOK_UPDATE_ERROR")
+		return
+	}
+
+	h.auditService.Log(c.Request.Context(), adminID, models.AuditWebhookUpdated,
+		models.AuditTargetWebhook, webhook.ID, models.MetadataMap{
+			"url": webhook.URL, "events": webhook.Events, "isActive": webhook.IsActive,
+		})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated",
+		"webhook": webhook,
+	})
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	adminID := c.GetString("userID")
+	webhookID := c.Param("webhookId")
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), webhookID); err != nil {
+		respondWebhookError(c, err, "Failed to delete webhook", "WEBHOOK_DELETE_ERROR")
+		return
+	}
+
+	h.auditService.Log(c.Request.Context(), adminID, models.AuditWebhookDeleted,
+		models.AuditTargetWebhook, webhookID, models.MetadataMap{})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// GetDeliveries returns a webhook's delivery log, optionally filtered by ?status=
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+
+	webhookID := c.Param("webhookId")
+
+	status := c.Query("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		respondError(c, http.StatusBadRequest, "status must be pending, delivered or failed", "INVALID_STATUS")
+		return
+	}
+
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 200 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	deliveries, err := h.service.GetDeliveries(c.Request.Context(), webhookID, status, limit, offset)
+	if err != nil {
+		respondInternalError(c, "Failed to fetch webhook deliveries", "WEBHOOK_DELIVERIES_ERROR", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"limit":      limit,
+		"offset":     offset,
+		"hasMore":    len(deliveries) == limit,
+	})
+}
@@ -0,0 +1,44 @@
+// ===============================
+// internal/logging/logging.go - Structured Logging with Request IDs
+// ===============================
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+type requestIDKey struct{}
+
+// Init installs a JSON slog handler as the default logger. The standard library log
+// package is routed through it too, so existing log.Printf lines come out as JSON.
+func Init(environment string) {
+	level := slog.LevelInfo
+	if environment == "debug" {
+		level = slog.LevelDebug
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+}
+
+// WithRequestID returns a context carrying the request ID for FromContext
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext returns the default logger tagged with the request ID, when there is one
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestID(ctx); requestID != "" {
+		return slog.Default().With("request_id", requestID)
+	}
+	return slog.Default()
+}
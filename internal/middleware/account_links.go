@@ -0,0 +1,74 @@
+// ===============================
+// internal/middleware/account_links.go - Recovered Account UID Resolution
+// ===============================
+
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"weibaobe/internal/database"
+)
+
+// accountUIDCacheTTL bounds how long a Firebase UID -> account resolution is cached.
+// Links are only ever added, so the worst case is a just-recovered device on another
+// instance waiting this long before its requests map to the recovered account.
+const accountUIDCacheTTL = time.Minute
+
+// accountUIDCachePruneSize triggers a sweep of expired entries once the cache grows past it
+const accountUIDCachePruneSize = 10000
+
+type accountUIDEntry struct {
+	userID    string
+	expiresAt time.Time
+}
+
+var accountUIDCache = struct {
+	sync.RWMutex
+	entries map[string]accountUIDEntry
+}{entries: make(map[string]accountUIDEntry)}
+
+// resolveAccountUID maps a Firebase UID to the account it was recovered into via
+// POST /auth/recover, or returns it unchanged. Lookup errors fall back to the raw UID.
+func resolveAccountUID(ctx context.Context, firebaseUID string) string {
+	now := time.Now()
+
+	accountUIDCache.RLock()
+	entry, ok := accountUIDCache.entries[firebaseUID]
+	accountUIDCache.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.userID
+	}
+
+	userID := firebaseUID
+	err := database.GetDB().GetContext(ctx, &userID,
+		"SELECT user_id FROM user_uid_links WHERE firebase_uid = $1", firebaseUID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("⚠️ Failed to resolve account link for %s: %v", firebaseUID, err)
+		return firebaseUID
+	}
+
+	accountUIDCache.Lock()
+	if len(accountUIDCache.entries) >= accountUIDCachePruneSize {
+		for uid, cached := range accountUIDCache.entries {
+			if now.After(cached.expiresAt) {
+				delete(accountUIDCache.entries, uid)
+			}
+		}
+	}
+	accountUIDCache.entries[firebaseUID] = accountUIDEntry{userID: userID, expiresAt: now.Add(accountUIDCacheTTL)}
+	accountUIDCache.Unlock()
+
+	return userID
+}
+
+// ForgetAccountUID drops a cached resolution after the UID is linked to an account
+func ForgetAccountUID(firebaseUID string) {
+	accountUIDCache.Lock()
+	delete(accountUIDCache.entries, firebaseUID)
+	accountUIDCache.Unlock()
+}
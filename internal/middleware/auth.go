@@ -5,6 +5,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -12,9 +13,53 @@ import (
 	"weibaobe/internal/models"
 	"weibaobe/internal/services"
 
+	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
+// verifiedTokenKey holds the token verified earlier in the same request
+const verifiedTokenKey = "verifiedFirebaseToken"
+
+type verifiedToken struct {
+	raw    string
+	token  *auth.Token
+	userID string
+}
+
+// verifyToken verifies a Firebase ID token at most once per request and resolves the
+// account it belongs to, which differs from the token UID for recovered accounts. The
+// rate limiter runs before the auth middlewares, so the result is kept on the context
+// for them to reuse.
+func verifyToken(c *gin.Context, firebaseService *services.FirebaseService, idToken string) (*auth.Token, string, error) {
+	if cached, ok := c.Get(verifiedTokenKey); ok {
+		if verified, ok := cached.(verifiedToken); ok && verified.raw == idToken {
+			return verified.token, verified.userID, nil
+		}
+	}
+
+	token, err := firebaseService.VerifyIDToken(c.Request.Context(), idToken)
+	if err != nil {
+		return nil, "", err
+	}
+	userID := resolveAccountUID(c.Request.Context(), token.UID)
+	c.Set(verifiedTokenKey, verifiedToken{raw: idToken, token: token, userID: userID})
+	return token, userID, nil
+}
+
+// BearerUserID returns the user ID from a valid "Bearer <token>" Authorization header, or
+// "" when the header is missing or the token is invalid. It never rejects the request.
+func BearerUserID(c *gin.Context, firebaseService *services.FirebaseService) string {
+	tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		return ""
+	}
+	_, userID, err := verifyToken(c, firebaseService, tokenParts[1])
+	if err != nil {
+		return ""
+	}
+	return userID
+}
+
 // FirebaseAuth creates a middleware that verifies Firebase tokens
 func FirebaseAuth(firebaseService *services.FirebaseService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -36,7 +81,7 @@ func FirebaseAuth(firebaseService *services.FirebaseService) gin.HandlerFunc {
 		token := tokenParts[1]
 
 		// Verify Firebase token using the service
-		firebaseToken, err := firebaseService.VerifyIDToken(c.Request.Context(), token)
+		firebaseToken, userID, err := verifyToken(c, firebaseService, token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
@@ -44,12 +89,42 @@ func FirebaseAuth(firebaseService *services.FirebaseService) gin.HandlerFunc {
 		}
 
 		// Set user ID in context
-		c.Set("userID", firebaseToken.UID)
+		c.Set("userID", userID)
 		c.Set("firebaseToken", firebaseToken)
 		c.Next()
 	}
 }
 
+// OptionalAuth sets the user ID when a valid Firebase token is present but never rejects
+// the request, so public endpoints can personalise results (e.g. hide blocked creators)
+func OptionalAuth(firebaseService *services.FirebaseService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
+			if firebaseToken, userID, err := verifyToken(c, firebaseService, tokenParts[1]); err == nil {
+				c.Set("userID", userID)
+				c.Set("firebaseToken", firebaseToken)
+			}
+		}
+		c.Next()
+	}
+}
+
+// StaticBearerToken admits requests whose Authorization header is "Bearer <token>" and
+// answers 401 to everything else. It guards machine endpoints such as /metrics that a
+// scraper calls without a Firebase account.
+func StaticBearerToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(provided, expected) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "code": "INVALID_TOKEN"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // AdminOnly middleware that requires admin privileges
 func AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -63,7 +138,7 @@ func AdminOnly() gin.HandlerFunc {
 		// Check if user is admin
 		db := database.GetDB()
 		var user models.User
-		err := db.Get(&user, "SELECT user_type FROM users WHERE uid = $1", userID)
+		err := db.Get(&user, "SELECT role, user_type FROM users WHERE uid = $1", userID)
 		if err != nil {
 			c.JSON(http.StatusForbidden, gin.H{"error": "User not found"})
 			c.Abort()
@@ -79,3 +154,40 @@ func AdminOnly() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequirePermission restricts an admin route to admins holding the given permission.
+// super_admin satisfies every permission. Must be used after AdminOnly.
+func RequirePermission(permission models.AdminPermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		var allowed bool
+		err := database.GetDB().GetContext(c.Request.Context(), &allowed, `
+			SELECT EXISTS(
+				SELECT 1 FROM admin_permissions
+				WHERE user_id = $1 AND permission IN ($2, $3)
+			)`, userID, permission, models.PermissionSuperAdmin)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check admin permissions"})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":      "Admin permission required",
+				"code":       "PERMISSION_DENIED",
+				"permission": permission,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+// ===============================
+// internal/middleware/concurrency.go - Per-Client Concurrency Limiting
+// ===============================
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// concurrencyRetryAfterSeconds is a hint only; a slot frees as soon as any in-flight
+// request from the same client finishes
+const concurrencyRetryAfterSeconds = 1
+
+// ConcurrencyLimit caps how many requests a single client may have in flight at once.
+// It is independent of the windowed rate limiter, which counts requests but not how long
+// they stay open, so slow exports can't pin down the server from one client. Like the rate
+// limiter, signed-in users are counted per user ID so users sharing a carrier NAT don't
+// crowd each other out, and anonymous requests per client IP. Video streams and websockets
+// stay open for as long as someone watches or chats, so they don't take a slot.
+// maxPerClient <= 0 disables the limit.
+func ConcurrencyLimit(maxPerClient int, firebaseService *services.FirebaseService) gin.HandlerFunc {
+	if maxPerClient <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+
+	release := func(client string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if inFlight[client] <= 1 {
+			delete(inFlight, client)
+			return
+		}
+		inFlight[client]--
+	}
+
+	return func(c *gin.Context) {
+		if isLongLivedRequest(c) {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if userID := BearerUserID(c, firebaseService); userID != "" {
+			client = "user:" + userID
+		}
+
+		mu.Lock()
+		if inFlight[client] >= maxPerClient {
+			mu.Unlock()
+			c.Header("Retry-After", strconv.Itoa(concurrencyRetryAfterSeconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many concurrent requests",
+				"message": "Too many requests in progress from this client, please wait for some to finish",
+				"code":    "CONCURRENCY_LIMIT_EXCEEDED",
+				"limit":   maxPerClient,
+			})
+			return
+		}
+		inFlight[client]++
+		mu.Unlock()
+
+		// Deferred so the slot is returned even if a handler panics into Recovery
+		defer release(client)
+		c.Next()
+	}
+}
+
+// isLongLivedRequest reports whether the request is a video stream or a websocket
+func isLongLivedRequest(c *gin.Context) bool {
+	return c.IsWebsocket() || strings.HasSuffix(c.FullPath(), "/stream")
+}
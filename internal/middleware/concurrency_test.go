@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const maxPerIP = 3
+	started := make(chan struct{}, maxPerIP)
+	unblock := make(chan struct{})
+
+	router := gin.New()
+	router.Use(ConcurrencyLimit(maxPerIP, nil))
+	router.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-unblock
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Fill every slot with a handler that blocks until released
+	var wg sync.WaitGroup
+	codes := make(chan int, maxPerIP)
+	for i := 0; i < maxPerIP; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- request("/slow").Code
+		}()
+	}
+	for i := 0; i < maxPerIP; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of %d blocking requests started", i, maxPerIP)
+		}
+	}
+
+	rec := request("/fast")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request %d: got status %d, want %d", maxPerIP+1, rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 response is missing Retry-After")
+	}
+
+	// Another client is not affected by the first one's slots
+	other := httptest.NewRequest(http.MethodGet, "/fast", nil)
+	other.RemoteAddr = "198.51.100.9:1234"
+	otherRec := httptest.NewRecorder()
+	router.ServeHTTP(otherRec, other)
+	if otherRec.Code != http.StatusOK {
+		t.Errorf("other client: got status %d, want %d", otherRec.Code, http.StatusOK)
+	}
+
+	close(unblock)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("blocked request: got status %d, want %d", code, http.StatusOK)
+		}
+	}
+
+	// Every slot is released once the handlers return
+	for i := 0; i < maxPerIP+1; i++ {
+		if rec := request("/fast"); rec.Code != http.StatusOK {
+			t.Fatalf("after release, request %d: got status %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestConcurrencyLimitReleasesSlotOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(ConcurrencyLimit(1, nil))
+	router.GET("/panic", func(c *gin.Context) {
+		panic("handler failure")
+	})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("request %d: got status %d, want %d", i+1, rec.Code, http.StatusInternalServerError)
+		}
+	}
+}
+
+func TestConcurrencyLimitSkipsStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+
+	router := gin.New()
+	router.Use(ConcurrencyLimit(1, nil))
+	router.GET("/videos/:videoId/stream", func(c *gin.Context) {
+		started <- struct{}{}
+		<-unblock
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	done := make(chan int, 1)
+	go func() { done <- request("/videos/abc/stream").Code }()
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream request did not start")
+	}
+
+	// An open stream doesn't use up the client's only slot
+	if rec := request("/fast"); rec.Code != http.StatusOK {
+		t.Errorf("request during stream: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	close(unblock)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("stream: got status %d, want %d", code, http.StatusOK)
+	}
+}
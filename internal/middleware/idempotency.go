@@ -0,0 +1,165 @@
+// ===============================
+// internal/middleware/idempotency.go - Idempotency-Key handling for retry-safe writes
+// ===============================
+
+package middleware
+
+import (
+	"bytes"
+	"database/sql"
+	"log"
+	"net/http"
+
+	"weibaobe/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// idempotencyResponseWriter captures the response body so it can be stored
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a request is retried with the same
+// Idempotency-Key header within 24 hours. Keys are scoped per authenticated user and
+// must be used after FirebaseAuth. Requests without the header pass through unchanged.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLen {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency key too long",
+				"code":  "INVALID_IDEMPOTENCY_KEY",
+			})
+			c.Abort()
+			return
+		}
+
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		db := database.GetDB()
+		ctx := c.Request.Context()
+		method := c.Request.Method
+		path := c.Request.URL.Path
+
+		// Drop an expired key so it can be reused
+		_, _ = db.ExecContext(ctx, `
+			DELETE FROM idempotency_keys
+			WHERE user_id = $1 AND idempotency_key = $2
+			  AND created_at < NOW() - INTERVAL '24 hours'`,
+			userID, key)
+
+		// Reserve the key; a conflict means it was already used
+		result, err := db.ExecContext(ctx, `
+			INSERT INTO idempotency_keys (user_id, idempotency_key, request_method, request_path)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, idempotency_key) DO NOTHING`,
+			userID, key, method, path)
+		if err != nil {
+			log.Printf("⚠️ Idempotency key reservation failed, processing without it: %v", err)
+			c.Next()
+			return
+		}
+
+		if reserved, _ := result.RowsAffected(); reserved == 0 {
+			replayIdempotentResponse(c, userID, key, method, path)
+			return
+		}
+
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			// Server errors are not cached so the client can retry
+			_, _ = db.Exec(`DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`, userID, key)
+			return
+		}
+
+		_, err = db.Exec(`
+			UPDATE idempotency_keys
+			SET status_code = $3, response_body = $4
+			WHERE user_id = $1 AND idempotency_key = $2`,
+			userID, key, status, writer.body.String())
+		if err != nil {
+			log.Printf("⚠️ Failed to store idempotent response for key %s: %v", key, err)
+		}
+	}
+}
+
+func replayIdempotentResponse(c *gin.Context, userID, key, method, path string) {
+	var storedMethod, storedPath, body string
+	var status int
+
+	err := database.GetDB().QueryRowContext(c.Request.Context(), `
+		SELECT request_method, request_path, status_code, response_body
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, key).Scan(&storedMethod, &storedPath, &status, &body)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Request with this idempotency key is being retried, please try again",
+			"code":  "IDEMPOTENCY_KEY_RETRY",
+		})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check idempotency key",
+			"code":  "IDEMPOTENCY_CHECK_ERROR",
+		})
+		c.Abort()
+		return
+	}
+
+	if storedMethod != method || storedPath != path {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency key was already used for a different request",
+			"code":  "IDEMPOTENCY_KEY_REUSED",
+		})
+		c.Abort()
+		return
+	}
+
+	if status == 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A request with this idempotency key is still being processed",
+			"code":  "IDEMPOTENCY_KEY_IN_PROGRESS",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(status, "application/json; charset=utf-8", []byte(body))
+	c.Abort()
+}
@@ -0,0 +1,44 @@
+// ===============================
+// internal/middleware/image_width.go - Requested Image Width
+// ===============================
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"weibaobe/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImageWidthParam lets clients pick the CDN transform width for images in the response,
+// e.g. a small width for avatars and feed tiles and a large one for fullscreen
+const ImageWidthParam = "imageWidth"
+
+// ImageWidth validates ?imageWidth= against the configured allowlist and records it on
+// the request context, where the video services read it when building image URLs.
+// Requests without the param get the configured default width.
+func ImageWidth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query(ImageWidthParam)
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		width, err := strconv.Atoi(raw)
+		if err != nil || !services.IsAllowedImageWidth(width) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":         "Unsupported image width",
+				"code":          "INVALID_IMAGE_WIDTH",
+				"allowedWidths": services.AllowedImageWidths(),
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(services.WithImageWidth(c.Request.Context(), width))
+		c.Next()
+	}
+}
@@ -0,0 +1,136 @@
+// ===============================
+// internal/middleware/request_logger.go - Request IDs and Access Logging
+// ===============================
+
+package middleware
+
+import (
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in both directions so clients and upstream
+// proxies can correlate their logs with ours
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps client-supplied IDs so they can't bloat every log line
+const maxRequestIDLength = 128
+
+const redactedValue = "[REDACTED]"
+
+// RequestLogger assigns each request an ID (reusing a sane incoming X-Request-ID), threads
+// it through the request context for service logs, and writes one structured access log
+// line per request once the handler chain has finished. Successful requests are sampled
+// 1-in-SuccessSampleRate; 4xx/5xx are always logged. Sensitive query params and headers
+// are redacted.
+func RequestLogger(cfg config.LoggingConfig) gin.HandlerFunc {
+	sampleRate := uint64(1)
+	if cfg.SuccessSampleRate > 1 {
+		sampleRate = uint64(cfg.SuccessSampleRate)
+	}
+
+	redact := make(map[string]bool, len(cfg.RedactKeys))
+	for _, key := range cfg.RedactKeys {
+		redact[strings.ToLower(key)] = true
+	}
+
+	var successCount atomic.Uint64
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		default:
+			if successCount.Add(1)%sampleRate != 0 {
+				return
+			}
+		}
+
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if c.Request.URL.RawQuery != "" {
+			attrs = append(attrs, slog.String("query", redactQuery(c.Request.URL.Query(), redact)))
+		}
+		for _, name := range cfg.Headers {
+			if value := c.GetHeader(name); value != "" {
+				if redact[strings.ToLower(name)] {
+					value = redactedValue
+				}
+				attrs = append(attrs, slog.String("header_"+strings.ToLower(strings.ReplaceAll(name, "-", "_")), value))
+			}
+		}
+		if userID := c.GetString("userID"); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if level == slog.LevelInfo && sampleRate > 1 {
+			// Lets aggregations scale sampled counts back up
+			attrs = append(attrs, slog.Uint64("sample_rate", sampleRate))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// redactQuery re-encodes the query string, sorted by key, with sensitive param values
+// replaced
+func redactQuery(query url.Values, redact map[string]bool) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			if redact[strings.ToLower(key)] {
+				b.WriteString(redactedValue)
+			} else {
+				b.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+// ===============================
+// internal/models/admin.go - Admin Permission Models
+// ===============================
+
+package models
+
+import "time"
+
+// AdminPermission is a capability granted to an individual admin
+type AdminPermission string
+
+const (
+	PermissionSuperAdmin      AdminPermission = "super_admin" // catch-all, also manages other admins
+	PermissionModerateContent AdminPermission = "moderate_content"
+	PermissionManageUsers     AdminPermission = "manage_users"
+	PermissionManageWallet    AdminPermission = "manage_wallet"
+	PermissionViewReports     AdminPermission = "view_reports"
+)
+
+// AllAdminPermissions lists every permission that can be granted
+var AllAdminPermissions = []AdminPermission{
+	PermissionSuperAdmin,
+	PermissionModerateContent,
+	PermissionManageUsers,
+	PermissionManageWallet,
+	PermissionViewReports,
+}
+
+// IsValid checks if the permission is a known permission
+func (p AdminPermission) IsValid() bool {
+	for _, permission := range AllAdminPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminPermissionGrant - A permission held by an admin
+type AdminPermissionGrant struct {
+	UserID     string          `json:"userId" db:"user_id"`
+	Permission AdminPermission `json:"permission" db:"permission"`
+	GrantedBy  *string         `json:"grantedBy" db:"granted_by"`
+	GrantedAt  time.Time       `json:"grantedAt" db:"granted_at"`
+}
+
+// AdminWithPermissions - An admin account and the permissions it holds
+type AdminWithPermissions struct {
+	UserID       string      `json:"userId" db:"uid"`
+	Name         string      `json:"name" db:"name"`
+	ProfileImage string      `json:"profileImage" db:"profile_image"`
+	Permissions  StringSlice `json:"permissions" db:"permissions"`
+}
+
+// Admin audit log actions
+const (
+	AuditVideoFeatured      = "video.featured"
+	AuditVideoActive        = "video.active"
+	AuditVideoVerified      = "video.verified"
+	AuditVideoBulkModerate  = "video.bulk_moderate"
+	AuditVideoCountsRebuilt = "video.counts_rebuilt"
+	AuditUserStatusUpdated  = "user.status_updated"
+	AuditUserRoleChanged    = "user.role_changed"
+	AuditWalletCoinsAdded   = "wallet.coins_added"
+	AuditPurchaseApproved   = "purchase.approved"
+	AuditPurchaseRejected   = "purchase.rejected"
+	AuditPermissionGranted  = "admin.permission_granted"
+	AuditPermissionRevoked  = "admin.permission_revoked"
+	AuditWebhookCreated     = "webhook.created"
+	AuditWebhookUpdated     = "webhook.updated"
+	AuditWebhookDeleted     = "webhook.deleted"
+)
+
+// Admin audit log target types
+const (
+	AuditTargetVideo           = "video"
+	AuditTargetUser            = "user"
+	AuditTargetPurchaseRequest = "purchase_request"
+	AuditTargetPlatform        = "platform"
+	AuditTargetWebhook         = "webhook"
+)
+
+// AdminAuditEntry - One recorded admin action
+type AdminAuditEntry struct {
+	ID         string      `json:"id" db:"id"`
+	AdminID    string      `json:"adminId" db:"admin_id"`
+	AdminName  *string     `json:"adminName" db:"admin_name"`
+	Action     string      `json:"action" db:"action"`
+	TargetType string      `json:"targetType" db:"target_type"`
+	TargetID   string      `json:"targetId" db:"target_id"`
+	Details    MetadataMap `json:"details" db:"details"`
+	CreatedAt  time.Time   `json:"createdAt" db:"created_at"`
+}
+
+// AuditLogFilter narrows GET /admin/audit-log; zero values match everything
+type AuditLogFilter struct {
+	AdminID string
+	Action  string
+	From    *time.Time
+	To      *time.Time
+}
@@ -0,0 +1,17 @@
+// ===============================
+// internal/models/block.go - User Blocking Models
+// ===============================
+
+package models
+
+import "time"
+
+// BlockedUser - Public profile of a user the caller has blocked
+type BlockedUser struct {
+	UID          string    `json:"uid" db:"uid"`
+	Name         string    `json:"name" db:"name"`
+	ProfileImage string    `json:"profileImage" db:"profile_image"`
+	Bio          string    `json:"bio" db:"bio"`
+	IsVerified   bool      `json:"isVerified" db:"is_verified"`
+	BlockedAt    time.Time `json:"blockedAt" db:"blocked_at"`
+}
@@ -0,0 +1,29 @@
+// ===============================
+// internal/models/follow_request.go - Follow Requests for Private Accounts
+// ===============================
+
+package models
+
+import "time"
+
+// Follow request statuses
+const (
+	FollowRequestPending  = "pending"
+	FollowRequestApproved = "approved"
+	FollowRequestRejected = "rejected"
+)
+
+// UserFollowRequest is a pending or answered request to follow a private account
+type UserFollowRequest struct {
+	ID          string     `json:"id" db:"id"`
+	RequesterID string     `json:"requesterId" db:"requester_id"`
+	TargetID    string     `json:"targetId" db:"target_id"`
+	Status      string     `json:"status" db:"status"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty" db:"responded_at"`
+
+	// Requester profile for the pending requests list
+	RequesterName       string `json:"requesterName" db:"requester_name"`
+	RequesterImage      string `json:"requesterImage" db:"requester_image"`
+	RequesterIsVerified bool   `json:"requesterIsVerified" db:"requester_is_verified"`
+}
@@ -44,6 +44,7 @@ type GiftTransaction struct {
 	RecipientTransactionID *string         `json:"recipientTransactionId" db:"recipient_transaction_id"`
 	Message                *string         `json:"message" db:"message"`
 	Context                *string         `json:"context" db:"context"`
+	VideoID                *string         `json:"videoId" db:"video_id"`
 	Metadata               GiftMetadataMap `json:"metadata" db:"metadata"`
 	CreatedAt              time.Time       `json:"createdAt" db:"created_at"`
 }
@@ -83,6 +84,7 @@ type SendGiftRequest struct {
 	GiftID      string  `json:"giftId" binding:"required"`
 	Message     *string `json:"message"`
 	Context     *string `json:"context"` // e.g., "video", "profile", "live_stream"
+	VideoID     *string `json:"videoId"` // video the gift was sent on; must belong to the recipient
 }
 
 // SendGiftResponse represents the response after sending a gift
@@ -120,6 +122,30 @@ type PlatformCommissionSummary struct {
 	CommissionThisMonth int64   `json:"commissionThisMonth" db:"commission_this_month"`
 }
 
+// PlatformRevenueReport represents platform commission revenue over a date range
+type PlatformRevenueReport struct {
+	From                  time.Time              `json:"from"`
+	To                    time.Time              `json:"to"`
+	TotalCommissions      int64                  `json:"totalCommissions" db:"total_commissions"`
+	TotalGiftsFacilitated int64                  `json:"totalGiftsFacilitated" db:"total_gifts_facilitated"`
+	TotalGiftVolume       int64                  `json:"totalGiftVolume" db:"total_gift_volume"`
+	AverageCommission     float64                `json:"averageCommission" db:"average_commission"`
+	Daily                 []DailyPlatformRevenue `json:"daily"`
+}
+
+// DailyPlatformRevenue represents one day of platform commission revenue
+type DailyPlatformRevenue struct {
+	Date        string `json:"date" db:"day"`
+	Commissions int64  `json:"commissions" db:"commissions"`
+	Gifts       int64  `json:"gifts" db:"gifts"`
+}
+
+// Limits for admin revenue date ranges
+const (
+	DefaultRevenueRangeDays = 30
+	MaxRevenueRangeDays     = 366
+)
+
 // TopGiftSender represents a top gift sender
 type TopGiftSender struct {
 	UserID       string  `json:"userId" db:"user_id"`
@@ -138,6 +164,26 @@ type TopGiftReceiver struct {
 	MostReceivedGift *string `json:"mostReceivedGift" db:"most_received_gift"`
 }
 
+// TopGiftedVideo - One of a creator's videos ranked by gift revenue
+type TopGiftedVideo struct {
+	VideoID       string    `json:"videoId" db:"video_id"`
+	Caption       string    `json:"caption" db:"caption"`
+	ThumbnailURL  string    `json:"thumbnailUrl" db:"thumbnail_url"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+	GiftCount     int       `json:"giftCount" db:"gift_count"`
+	CoinsReceived int64     `json:"coinsReceived" db:"coins_received"` // after platform commission
+	GiftValue     int64     `json:"giftValue" db:"gift_value"`         // what senders paid
+}
+
+// TopGiftedVideosReport - A creator's most-gifted videos in a window with window totals
+type TopGiftedVideosReport struct {
+	Since              time.Time        `json:"since"`
+	Videos             []TopGiftedVideo `json:"videos"`
+	TotalGifts         int              `json:"totalGifts" db:"total_gifts"`
+	TotalCoinsReceived int64            `json:"totalCoinsReceived" db:"total_coins_received"`
+	TotalGiftValue     int64            `json:"totalGiftValue" db:"total_gift_value"`
+}
+
 // GiftHistory represents gift transaction history
 type GiftHistoryItem struct {
 	GiftTransaction
@@ -0,0 +1,140 @@
+// ===============================
+// internal/models/moderation.go - Content Moderation Models
+// ===============================
+
+package models
+
+import "time"
+
+// Video report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// ModerationAction is the outcome of screening user-written text
+type ModerationAction string
+
+const (
+	ModerationAllow  ModerationAction = "allow"
+	ModerationFlag   ModerationAction = "flag"   // stored, but queued for admin review
+	ModerationReject ModerationAction = "reject" // not stored
+)
+
+// ModerationDecision - Result of a ContentModerator check
+type ModerationDecision struct {
+	Action ModerationAction
+	Reason string
+}
+
+// SearchSafetyAction is how a sensitive search query is answered
+type SearchSafetyAction string
+
+const (
+	SearchSafetyBlock    SearchSafetyAction = "block"    // no results
+	SearchSafetyWarn     SearchSafetyAction = "warn"     // normal results with a notice
+	SearchSafetyRedirect SearchSafetyAction = "redirect" // no results, point at a resource instead
+)
+
+// SearchSafetyNotice - The configured safe response for a sensitive search query
+type SearchSafetyNotice struct {
+	Action      SearchSafetyAction `json:"action"`
+	Message     string             `json:"message,omitempty"`
+	ResourceURL string             `json:"resourceUrl,omitempty"`
+}
+
+// WithholdsResults reports whether the query should return no videos
+func (n *SearchSafetyNotice) WithholdsResults() bool {
+	return n.Action == SearchSafetyBlock || n.Action == SearchSafetyRedirect
+}
+
+// VideoReportReasons lists the accepted report reasons
+var VideoReportReasons = []string{
+	"spam", "nudity", "violence", "harassment", "hate_speech",
+	"misinformation", "copyright", "scam", "other",
+}
+
+// IsValidReportReason checks if a reason is one of VideoReportReasons
+func IsValidReportReason(reason string) bool {
+	for _, r := range VideoReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Bulk moderation actions
+const (
+	BulkActionDeactivate = "deactivate"
+	BulkActionActivate   = "activate"
+	BulkActionFeature    = "feature"
+	BulkActionVerify     = "verify"
+)
+
+// MaxBulkModerationVideos caps how many videos one bulk moderation request may touch
+const MaxBulkModerationVideos = 100
+
+// BulkModerationResult - Outcome of a bulk moderation action for one video
+type BulkModerationResult struct {
+	VideoID string `json:"videoId"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ModerationQueueItem - A video awaiting moderation with its open reports summarised
+type ModerationQueueItem struct {
+	VideoID        string      `json:"videoId" db:"id"`
+	UserID         string      `json:"userId" db:"user_id"`
+	UserName       string      `json:"userName" db:"user_name"`
+	Caption        string      `json:"caption" db:"caption"`
+	VideoURL       string      `json:"videoUrl" db:"video_url"`
+	ThumbnailURL   string      `json:"thumbnailUrl" db:"thumbnail_url"`
+	IsActive       bool        `json:"isActive" db:"is_active"`
+	IsVerified     bool        `json:"isVerified" db:"is_verified"`
+	IsFlagged      bool        `json:"isFlagged" db:"is_flagged"`
+	FlagReason     string      `json:"flagReason" db:"flag_reason"`
+	CreatedAt      time.Time   `json:"createdAt" db:"created_at"`
+	ReportCount    int         `json:"reportCount" db:"report_count"`
+	ReportReasons  StringSlice `json:"reportReasons" db:"report_reasons"`
+	LastReportedAt *time.Time  `json:"lastReportedAt" db:"last_reported_at"`
+}
+
+// FlaggedComment - A comment the content moderator held for admin review
+type FlaggedComment struct {
+	CommentID  string    `json:"commentId" db:"id"`
+	VideoID    string    `json:"videoId" db:"video_id"`
+	AuthorID   string    `json:"authorId" db:"author_id"`
+	AuthorName string    `json:"authorName" db:"author_name"`
+	Content    string    `json:"content" db:"content"`
+	FlagReason string    `json:"flagReason" db:"flag_reason"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ReportMessageRequest - A chat participant reporting a message
+type ReportMessageRequest struct {
+	Reason      string `json:"reason" binding:"required"`
+	Description string `json:"description"`
+}
+
+// MessageReportResult - Outcome of reporting a chat message. SenderFlagged is true when
+// this report pushed the sender over the repeated-report threshold.
+type MessageReportResult struct {
+	ReportID      string `json:"reportId"`
+	SenderFlagged bool   `json:"senderFlagged"`
+}
+
+// ReportedMessage - A chat message with open reports, for the admin moderation queue
+type ReportedMessage struct {
+	MessageID       string      `json:"messageId" db:"message_id"`
+	ChatID          string      `json:"chatId" db:"chat_id"`
+	SenderID        string      `json:"senderId" db:"sender_id"`
+	SenderName      string      `json:"senderName" db:"sender_name"`
+	SenderFlagged   bool        `json:"senderFlagged" db:"sender_flagged"`
+	Content         string      `json:"content" db:"content"`
+	ReportCount     int         `json:"reportCount" db:"report_count"`
+	ReportReasons   StringSlice `json:"reportReasons" db:"report_reasons"`
+	FirstReportedAt time.Time   `json:"firstReportedAt" db:"first_reported_at"`
+	LastReportedAt  time.Time   `json:"lastReportedAt" db:"last_reported_at"`
+}
@@ -0,0 +1,35 @@
+// ===============================
+// internal/models/notification.go - Notification Models
+// ===============================
+
+package models
+
+import "time"
+
+// Notification types
+const (
+	NotificationTypeMention = "mention"
+)
+
+// Notification - An in-app notification for a user
+type Notification struct {
+	ID         string      `json:"id" db:"id"`
+	UserID     string      `json:"userId" db:"user_id"`
+	ActorID    *string     `json:"actorId" db:"actor_id"`
+	Type       string      `json:"type" db:"type"`
+	EntityType *string     `json:"entityType" db:"entity_type"`
+	EntityID   *string     `json:"entityId" db:"entity_id"`
+	Message    string      `json:"message" db:"message"`
+	Data       MetadataMap `json:"data" db:"data"`
+	IsRead     bool        `json:"isRead" db:"is_read"`
+	ReadAt     *time.Time  `json:"readAt" db:"read_at"`
+	CreatedAt  time.Time   `json:"createdAt" db:"created_at"`
+}
+
+// MarkNotificationsReadRequest narrows a bulk mark-read. Type limits it to one notification
+// type; BeforeID limits it to notifications created no later than that notification.
+// Both empty marks everything read.
+type MarkNotificationsReadRequest struct {
+	Type     string `json:"type" binding:"omitempty,max=50"`
+	BeforeID string `json:"beforeId" binding:"omitempty,uuid"`
+}
@@ -0,0 +1,143 @@
+// ===============================
+// internal/models/phone.go - Country phone formats for WhatsApp numbers
+// ===============================
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CountryPhoneFormat describes how mobile numbers are written for a country
+type CountryPhoneFormat struct {
+	Country        string `json:"country"`        // ISO 3166-1 alpha-2
+	DialCode       string `json:"dialCode"`       // international prefix without "+"
+	NationalLength int    `json:"nationalLength"` // digits after the dial code
+}
+
+// CountryPhoneFormats lists the supported countries keyed by ISO code
+var CountryPhoneFormats = map[string]CountryPhoneFormat{
+	"KE": {Country: "KE", DialCode: "254", NationalLength: 9},
+	"UG": {Country: "UG", DialCode: "256", NationalLength: 9},
+	"TZ": {Country: "TZ", DialCode: "255", NationalLength: 9},
+	"RW": {Country: "RW", DialCode: "250", NationalLength: 9},
+	"ET": {Country: "ET", DialCode: "251", NationalLength: 9},
+	"GH": {Country: "GH", DialCode: "233", NationalLength: 9},
+	"NG": {Country: "NG", DialCode: "234", NationalLength: 10},
+	"ZA": {Country: "ZA", DialCode: "27", NationalLength: 9},
+	"GB": {Country: "GB", DialCode: "44", NationalLength: 10},
+	"US": {Country: "US", DialCode: "1", NationalLength: 10},
+	"IN": {Country: "IN", DialCode: "91", NationalLength: 10},
+	"CN": {Country: "CN", DialCode: "86", NationalLength: 11},
+}
+
+// DefaultPhoneCountry is used when a number is entered without a country code
+const DefaultPhoneCountry = "KE"
+
+var (
+	defaultPhoneCountry = DefaultPhoneCountry
+	nonDigitPattern     = regexp.MustCompile(`\D`)
+)
+
+// SetDefaultPhoneCountry changes the country assumed for national-format numbers
+func SetDefaultPhoneCountry(country string) error {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if _, ok := CountryPhoneFormats[country]; !ok {
+		return fmt.Errorf("unsupported phone country: %s", country)
+	}
+	defaultPhoneCountry = country
+	return nil
+}
+
+// GetDefaultPhoneCountry returns the country assumed for national-format numbers
+func GetDefaultPhoneCountry() string {
+	return defaultPhoneCountry
+}
+
+// FormatPhoneNumberForCountry converts input to international digits (no "+") using
+// country for numbers written in national form. Input with a "+" or "00" prefix is
+// always international; bare digits are read as a national number of country first,
+// so an 11-digit Chinese mobile starting with 1 is not mistaken for a US number, and
+// only then as an international number missing its "+".
+func FormatPhoneNumberForCountry(input, country string) (string, error) {
+	format, ok := CountryPhoneFormats[strings.ToUpper(country)]
+	if !ok {
+		return "", fmt.Errorf("unsupported phone country: %s", country)
+	}
+
+	trimmed := strings.TrimSpace(input)
+	cleaned := nonDigitPattern.ReplaceAllString(trimmed, "")
+	international := strings.HasPrefix(trimmed, "+")
+	if strings.HasPrefix(trimmed, "00") {
+		cleaned = strings.TrimPrefix(cleaned, "00")
+		international = true
+	}
+
+	if !international {
+		switch {
+		case len(cleaned) == format.NationalLength+1 && cleaned[0] == '0':
+			return format.DialCode + cleaned[1:], nil
+		case len(cleaned) == format.NationalLength && cleaned[0] != '0':
+			return format.DialCode + cleaned, nil
+		}
+	}
+
+	if _, ok := MatchPhoneCountry(cleaned); ok {
+		return cleaned, nil
+	}
+	return "", fmt.Errorf("invalid phone number format for %s: %s", format.Country, input)
+}
+
+// NormalizePhoneNumber canonicalises an account phone number to E.164 ("+254712345678"),
+// assuming the configured default country for numbers written in national form, so
+// "0712 345 678" and "+254712345678" are stored identically
+func NormalizePhoneNumber(input string) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		return "", fmt.Errorf("phone number is required")
+	}
+
+	formatted, err := FormatPhoneNumberForCountry(input, GetDefaultPhoneCountry())
+	if err != nil {
+		format := CountryPhoneFormats[GetDefaultPhoneCountry()]
+		return "", fmt.Errorf("%q is not a valid mobile number: use the international format with a supported country code (e.g. +%s followed by %d digits) or a local number starting with 0",
+			input, format.DialCode, format.NationalLength)
+	}
+	return "+" + formatted, nil
+}
+
+// PhoneNumberStoredForms lists every form an E.164 number may be stored in: as given,
+// without the "+" as older releases wrote it, and, for the default country, the
+// national forms ("0712345678", "712345678") saved before numbers were normalised
+func PhoneNumberStoredForms(e164 string) StringSlice {
+	digits := strings.TrimPrefix(e164, "+")
+	forms := StringSlice{e164, digits}
+
+	format := CountryPhoneFormats[GetDefaultPhoneCountry()]
+	national := strings.TrimPrefix(digits, format.DialCode)
+	if national != digits && len(national) == format.NationalLength {
+		forms = append(forms, "0"+national, national)
+	}
+	return forms
+}
+
+// MatchPhoneCountry returns the supported country whose dial code and length match
+// an international number. Longer dial codes are tried first.
+func MatchPhoneCountry(number string) (CountryPhoneFormat, bool) {
+	formats := make([]CountryPhoneFormat, 0, len(CountryPhoneFormats))
+	for _, format := range CountryPhoneFormats {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return len(formats[i].DialCode) > len(formats[j].DialCode)
+	})
+
+	for _, format := range formats {
+		if strings.HasPrefix(number, format.DialCode) && len(number) == len(format.DialCode)+format.NationalLength {
+			return format, true
+		}
+	}
+	return CountryPhoneFormat{}, false
+}
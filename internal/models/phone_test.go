@@ -0,0 +1,92 @@
+package models
+
+import "testing"
+
+func TestFormatPhoneNumberForCountry(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		country string
+		want    string
+		wantErr bool
+	}{
+		{name: "KE national with leading zero", input: "0712 345 678", country: "KE", want: "254712345678"},
+		{name: "KE national without leading zero", input: "712345678", country: "KE", want: "254712345678"},
+		{name: "KE international with plus", input: "+254 712 345 678", country: "KE", want: "254712345678"},
+		{name: "KE international with 00", input: "00254712345678", country: "KE", want: "254712345678"},
+		{name: "international digits without plus", input: "254712345678", country: "KE", want: "254712345678"},
+		{name: "other country with plus", input: "+256712345678", country: "KE", want: "256712345678"},
+		{name: "CN mobile starting with 1 stays CN", input: "13812345678", country: "CN", want: "8613812345678"},
+		{name: "CN mobile with leading zero", input: "013812345678", country: "CN", want: "8613812345678"},
+		{name: "US number with plus under CN default", input: "+12025550123", country: "CN", want: "12025550123"},
+		{name: "US national", input: "(202) 555-0123", country: "US", want: "12025550123"},
+		{name: "too short", input: "12345", country: "KE", wantErr: true},
+		{name: "plus with unknown dial code", input: "+999712345678", country: "KE", wantErr: true},
+		{name: "unsupported country", input: "0712345678", country: "XX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatPhoneNumberForCountry(tt.input, tt.country)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FormatPhoneNumberForCountry(%q, %q) = %q, want error", tt.input, tt.country, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatPhoneNumberForCountry(%q, %q) error: %v", tt.input, tt.country, err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatPhoneNumberForCountry(%q, %q) = %q, want %q", tt.input, tt.country, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePhoneNumberUsesDefaultCountry(t *testing.T) {
+	defer SetDefaultPhoneCountry(GetDefaultPhoneCountry())
+	if err := SetDefaultPhoneCountry("CN"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NormalizePhoneNumber("138 1234 5678")
+	if err != nil {
+		t.Fatalf("NormalizePhoneNumber error: %v", err)
+	}
+	if got != "+8613812345678" {
+		t.Errorf("NormalizePhoneNumber = %q, want %q", got, "+8613812345678")
+	}
+}
+
+func TestPhoneNumberStoredForms(t *testing.T) {
+	defer SetDefaultPhoneCountry(GetDefaultPhoneCountry())
+	if err := SetDefaultPhoneCountry("KE"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "default country adds national forms", input: "+254712345678",
+			want: []string{"+254712345678", "254712345678", "0712345678", "712345678"}},
+		{name: "other country has no national forms", input: "+256712345678",
+			want: []string{"+256712345678", "256712345678"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PhoneNumberStoredForms(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("PhoneNumberStoredForms(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("PhoneNumberStoredForms(%q) = %v, want %v", tt.input, got, tt.want)
+				}
+			}
+		})
+	}
+}
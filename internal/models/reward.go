@@ -0,0 +1,62 @@
+// ===============================
+// internal/models/reward.go - Engagement Milestone Reward Models
+// ===============================
+
+package models
+
+import "time"
+
+// Milestone kinds checked by RewardService
+const (
+	MilestoneFirstPost  = "first_post"
+	MilestoneFollowers  = "followers"
+	MilestoneVideoViews = "video_views"
+)
+
+// RewardMilestone - A configured milestone and the coins it pays out
+type RewardMilestone struct {
+	Kind      string `json:"kind"`
+	Threshold int    `json:"threshold"`
+	Coins     int    `json:"coins"`
+}
+
+// RewardGrant - A milestone reward already credited to a user
+type RewardGrant struct {
+	ID            string    `json:"id" db:"id"`
+	UserID        string    `json:"userId" db:"user_id"`
+	MilestoneKey  string    `json:"milestoneKey" db:"milestone_key"`
+	ReferenceID   *string   `json:"referenceId" db:"reference_id"`
+	Coins         int       `json:"coins" db:"coins"`
+	TransactionID *string   `json:"transactionId" db:"transaction_id"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
+
+// DailyClaim - A user's daily coin claim for one calendar day
+type DailyClaim struct {
+	ID            string    `json:"id" db:"id"`
+	UserID        string    `json:"userId" db:"user_id"`
+	ClaimDate     string    `json:"claimDate" db:"claim_date"`
+	Streak        int       `json:"streak" db:"streak"`
+	Coins         int       `json:"coins" db:"coins"`
+	TransactionID *string   `json:"transactionId" db:"transaction_id"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
+
+// DailyClaimStatus - Where a user stands with the daily claim. Streak is the current
+// unbroken streak (0 once a day has been missed); NextClaimCoins is what the next claim
+// pays, available from NextClaimAt.
+type DailyClaimStatus struct {
+	Streak         int       `json:"streak"`
+	ClaimedToday   bool      `json:"claimedToday"`
+	CanClaim       bool      `json:"canClaim"`
+	LastClaimDate  *string   `json:"lastClaimDate"`
+	NextClaimAt    time.Time `json:"nextClaimAt"`
+	NextClaimCoins int       `json:"nextClaimCoins"`
+}
+
+// DailyClaimResult - The outcome of a successful daily claim
+type DailyClaimResult struct {
+	Claim        DailyClaim       `json:"claim"`
+	BalanceAfter int              `json:"balanceAfter"`
+	Status       DailyClaimStatus `json:"status"`
+}
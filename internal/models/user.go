@@ -8,7 +8,6 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 )
@@ -136,7 +135,8 @@ type User struct {
 	IsVerified     bool        `json:"isVerified" db:"is_verified"`
 	IsActive       bool        `json:"isActive" db:"is_active"`
 	IsFeatured     bool        `json:"isFeatured" db:"is_featured"`
-	IsLive         bool        `json:"isLive" db:"is_live"` // Track if user is currently live streaming
+	IsLive         bool        `json:"isLive" db:"is_live"`       // Track if user is currently live streaming
+	IsPrivate      bool        `json:"isPrivate" db:"is_private"` // Follows need approval and videos are followers-only
 	Tags           StringSlice `json:"tags" db:"tags"`
 
 	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
@@ -144,9 +144,18 @@ type User struct {
 	LastSeen   time.Time  `json:"lastSeen" db:"last_seen"`
 	LastPostAt *time.Time `json:"lastPostAt" db:"last_post_at"`
 
+	// When the user last loaded the following feed; drives the "new posts" badge
+	LastFeedSeenAt *time.Time `json:"-" db:"last_feed_seen_at"`
+
+	// Set when the owner deactivated the account themselves; signing in reactivates it
+	SelfDeactivatedAt *time.Time `json:"-" db:"self_deactivated_at"`
+
 	// Runtime fields (not stored in DB)
 	IsFollowing   bool `json:"isFollowing" db:"-"`
 	IsCurrentUser bool `json:"isCurrentUser" db:"-"`
+
+	// Set by follower/following lists: the listed user and the list owner follow each other
+	IsMutual bool `json:"isMutual" db:"is_mutual"`
 }
 
 type UserPreferences struct {
@@ -283,11 +292,20 @@ func (u *User) HasWhatsApp() bool {
 	return u.WhatsappNumber != nil && *u.WhatsappNumber != ""
 }
 
+// whatsAppDigits returns the stored number in international form for wa.me links,
+// normalising legacy national-format values with the default country
+func (u *User) whatsAppDigits() string {
+	if formatted, err := FormatWhatsAppNumber(*u.WhatsappNumber); err == nil && formatted != nil {
+		return *formatted
+	}
+	return *u.WhatsappNumber
+}
+
 func (u *User) GetWhatsAppLink() *string {
 	if !u.HasWhatsApp() {
 		return nil
 	}
-	link := fmt.Sprintf("https://wa.me/%s", *u.WhatsappNumber)
+	link := fmt.Sprintf("https://wa.me/%s", u.whatsAppDigits())
 	return &link
 }
 
@@ -298,7 +316,7 @@ func (u *User) GetWhatsAppLinkWithMessage() *string {
 	message := fmt.Sprintf("Hi %s! I found your profile on the app.", u.Name)
 	encodedMessage := strings.ReplaceAll(message, " ", "%20")
 	encodedMessage = strings.ReplaceAll(encodedMessage, "!", "%21")
-	link := fmt.Sprintf("https://wa.me/%s?text=%s", *u.WhatsappNumber, encodedMessage)
+	link := fmt.Sprintf("https://wa.me/%s?text=%s", u.whatsAppDigits(), encodedMessage)
 	return &link
 }
 
@@ -307,37 +325,26 @@ func (u *User) ValidateWhatsAppNumber() error {
 		return nil
 	}
 
-	matched, err := regexp.MatchString(`^254[0-9]{9}$`, *u.WhatsappNumber)
-	if err != nil {
-		return fmt.Errorf("error validating WhatsApp number: %w", err)
-	}
-	if !matched {
-		return fmt.Errorf("WhatsApp number must be in format 254XXXXXXXXX")
+	if _, ok := MatchPhoneCountry(*u.WhatsappNumber); !ok {
+		return fmt.Errorf("WhatsApp number must be in international format with a supported country code (e.g. %s...)",
+			CountryPhoneFormats[GetDefaultPhoneCountry()].DialCode)
 	}
 
 	return nil
 }
 
+// FormatWhatsAppNumber normalises input to international digits, assuming the
+// configured default country for numbers without a country code
 func FormatWhatsAppNumber(input string) (*string, error) {
 	if input == "" {
 		return nil, nil
 	}
 
-	re := regexp.MustCompile(`\D`)
-	cleaned := re.ReplaceAllString(input, "")
-
-	switch {
-	case len(cleaned) == 12 && cleaned[:3] == "254":
-		return &cleaned, nil
-	case len(cleaned) == 10 && cleaned[0] == '0':
-		formatted := "254" + cleaned[1:]
-		return &formatted, nil
-	case len(cleaned) == 9:
-		formatted := "254" + cleaned
-		return &formatted, nil
-	default:
-		return nil, fmt.Errorf("invalid phone number format: %s", input)
+	formatted, err := FormatPhoneNumberForCountry(input, GetDefaultPhoneCountry())
+	if err != nil {
+		return nil, err
 	}
+	return &formatted, nil
 }
 
 func (u *User) HasPostedVideos() bool {
@@ -518,6 +525,7 @@ type UpdateUserRequest struct {
 	Gender         *string  `json:"gender"`   // Optional: "male" or "female"
 	Location       *string  `json:"location"` // Optional: Ward location (format: "Ward, Constituency, County")
 	Language       *string  `json:"language"` // Optional: Native tribe/language (one of 43 Kenyan tribes or "Foreign")
+	IsPrivate      *bool    `json:"isPrivate"`
 }
 
 // User response models
@@ -542,6 +550,38 @@ type UserResponse struct {
 	LastPostTimeAgo string `json:"lastPostTimeAgo"`
 }
 
+// PublicUser is the projection of a user shown to other people in search results and
+// like lists. It has no contact fields: phone and WhatsApp numbers stay hidden unless the
+// user opts in through UserPreferences.ShowPhoneNumber, which these lists don't carry.
+type PublicUser struct {
+	UID            string      `json:"uid" db:"uid"`
+	Name           string      `json:"name" db:"name"`
+	ProfileImage   string      `json:"profileImage" db:"profile_image"`
+	CoverImage     string      `json:"coverImage" db:"cover_image"`
+	Bio            string      `json:"bio" db:"bio"`
+	Role           UserRole    `json:"role" db:"role"`
+	FollowersCount int         `json:"followersCount" db:"followers_count"`
+	FollowingCount int         `json:"followingCount" db:"following_count"`
+	VideosCount    int         `json:"videosCount" db:"videos_count"`
+	LikesCount     int         `json:"likesCount" db:"likes_count"`
+	IsVerified     bool        `json:"isVerified" db:"is_verified"`
+	IsFeatured     bool        `json:"isFeatured" db:"is_featured"`
+	IsPrivate      bool        `json:"isPrivate" db:"is_private"`
+	Tags           StringSlice `json:"tags" db:"tags"`
+	CreatedAt      time.Time   `json:"createdAt" db:"created_at"`
+	LastPostAt     *time.Time  `json:"lastPostAt" db:"last_post_at"`
+}
+
+// ProfileCacheStats - Public profile cache counters reported by the admin health check
+type ProfileCacheStats struct {
+	Enabled    bool    `json:"enabled"`
+	TTLSeconds int     `json:"ttlSeconds,omitempty"`
+	Entries    int     `json:"entries"`
+	Hits       uint64  `json:"hits"`
+	Misses     uint64  `json:"misses"`
+	HitRate    float64 `json:"hitRate"`
+}
+
 type UserListResponse struct {
 	Users   []UserResponse `json:"users"`
 	HasMore bool           `json:"hasMore"`
@@ -603,6 +643,97 @@ type UserActivity struct {
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
+// UserDashboard - Home screen summary for the authenticated user
+type UserDashboard struct {
+	UserID                  string    `json:"userId" db:"uid"`
+	CoinsBalance            int       `json:"coinsBalance" db:"coins_balance"`
+	UnreadNotificationCount int       `json:"unreadNotificationCount" db:"unread_notifications"`
+	UnreadChatCount         int       `json:"unreadChatCount" db:"unread_chats"`
+	FollowersCount          int       `json:"followersCount" db:"followers_count"`
+	RecentEarnings          int       `json:"recentEarnings" db:"recent_earnings"`
+	EarningsSince           time.Time `json:"earningsSince" db:"-"`
+}
+
+// DashboardEarningsWindow is how far back recentEarnings looks
+const DashboardEarningsWindow = 30 * 24 * time.Hour
+
+// Wallet transaction types that count as creator earnings
+var EarningTransactionTypes = []string{"gift_received", "reward", "video_sale"}
+
+// CreatorLeaderboardEntry - A creator ranked by engagement received in a recent window
+type CreatorLeaderboardEntry struct {
+	UserID          string  `json:"userId" db:"uid"`
+	Name            string  `json:"name" db:"name"`
+	ProfileImage    string  `json:"profileImage" db:"profile_image"`
+	IsVerified      bool    `json:"isVerified" db:"is_verified"`
+	Location        *string `json:"location" db:"location"`
+	Language        *string `json:"language" db:"language"`
+	FollowersCount  int     `json:"followersCount" db:"followers_count"`
+	RecentLikes     int     `json:"recentLikes" db:"recent_likes"`
+	RecentComments  int     `json:"recentComments" db:"recent_comments"`
+	RecentWatches   int     `json:"recentWatches" db:"recent_watches"`
+	EngagementScore float64 `json:"engagementScore" db:"engagement_score"`
+}
+
+// UserDemographicsSummary - Platform-wide gender split and most common locations and
+// languages among active users (get_user_demographics_summary)
+type UserDemographicsSummary struct {
+	TotalUsers             int      `json:"totalUsers"`
+	MaleCount              int      `json:"maleCount"`
+	FemaleCount            int      `json:"femaleCount"`
+	UnspecifiedGenderCount int      `json:"unspecifiedGenderCount"`
+	TopLocations           []string `json:"topLocations"`
+	TopLanguages           []string `json:"topLanguages"`
+}
+
+// AudienceMinBucketSize is the smallest follower count reported for any demographic
+// bucket. Smaller buckets are folded into "other" so no individual follower can be singled
+// out, and audiences smaller than this are not broken down at all.
+const AudienceMinBucketSize = 10
+
+// AudienceBucket - One slice of a creator's follower distribution
+type AudienceBucket struct {
+	Label   string  `json:"label"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// AudienceDemographics - Aggregated gender, county and language split of a creator's
+// active followers. Location is bucketed to county.
+type AudienceDemographics struct {
+	TotalFollowers   int              `json:"totalFollowers"`
+	MinBucketSize    int              `json:"minBucketSize"`
+	InsufficientData bool             `json:"insufficientData"`
+	Gender           []AudienceBucket `json:"gender"`
+	Location         []AudienceBucket `json:"location"`
+	Language         []AudienceBucket `json:"language"`
+}
+
+// DefaultAnalyticsTimezone - Timezone creator analytics are bucketed in unless the
+// client asks for another
+const DefaultAnalyticsTimezone = "Africa/Nairobi"
+
+// PostingTimeSlot - Engagement of a creator's videos posted in one weekday/hour slot.
+// DayOfWeek runs from 0 (Sunday) to 6.
+type PostingTimeSlot struct {
+	DayOfWeek     int     `json:"dayOfWeek" db:"day_of_week"`
+	Hour          int     `json:"hour" db:"hour"`
+	Videos        int     `json:"videos" db:"videos"`
+	AvgViews      float64 `json:"avgViews" db:"avg_views"`
+	AvgEngagement float64 `json:"avgEngagement" db:"avg_engagement"`
+}
+
+// BestPostingTimes - When a creator's posts get the most engagement. Heatmap[day][hour]
+// holds the average engagement score for that slot (0 where nothing was posted).
+type BestPostingTimes struct {
+	Timezone       string            `json:"timezone"`
+	Days           int               `json:"days"`
+	VideosAnalyzed int               `json:"videosAnalyzed"`
+	Heatmap        [7][24]float64    `json:"heatmap"`
+	Slots          []PostingTimeSlot `json:"slots"`
+	TopSlots       []PostingTimeSlot `json:"topSlots"`
+}
+
 const (
 	MaxNameLength       = 50
 	MaxBioLength        = 160
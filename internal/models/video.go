@@ -8,8 +8,10 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ===============================
@@ -76,6 +78,9 @@ type Video struct {
 	IsVerified       bool        `db:"is_verified" json:"isVerified"`
 	IsMultipleImages bool        `db:"is_multiple_images" json:"isMultipleImages"`
 	ImageUrls        StringSlice `db:"image_urls" json:"imageUrls"`
+	IsFlagged        bool        `db:"is_flagged" json:"isFlagged"` // held for admin review by the content moderator
+	FlagReason       string      `db:"flag_reason" json:"-"`
+	ContentHash      *string     `db:"video_content_hash" json:"-"` // storage ETag of the media, for duplicate detection
 	CreatedAt        time.Time   `db:"created_at" json:"createdAt"`
 	UpdatedAt        time.Time   `db:"updated_at" json:"updatedAt"`
 }
@@ -104,6 +109,11 @@ type VideoResponse struct {
 	UpdatedAt        time.Time   `json:"updatedAt"`
 	IsLiked          bool        `json:"isLiked"`
 	IsFollowing      bool        `json:"isFollowing"`
+	IsSaved          bool        `json:"isSaved"`
+	IsPurchased      bool        `json:"isPurchased"`
+	IsLocked         bool        `json:"isLocked"`              // priced video the viewer has not bought; media URLs are withheld
+	UserBalance      *int        `json:"userBalance,omitempty"` // signed-in viewer's coins, set on locked videos
+	CanUnlock        *bool       `json:"canUnlock,omitempty"`   // whether UserBalance covers the price
 }
 
 type CreateVideoRequest struct {
@@ -116,11 +126,71 @@ type CreateVideoRequest struct {
 	ImageUrls        []string `json:"imageUrls"`
 }
 
+// ValidateVideoRequest carries the same fields as CreateVideoRequest, but nothing is
+// required at bind time so every problem is reported in the validation result
+type ValidateVideoRequest struct {
+	VideoURL         string   `json:"videoUrl"`
+	ThumbnailURL     string   `json:"thumbnailUrl"`
+	Caption          string   `json:"caption"`
+	Price            *float64 `json:"price"`
+	Tags             []string `json:"tags"`
+	IsMultipleImages bool     `json:"isMultipleImages"`
+	ImageUrls        []string `json:"imageUrls"`
+}
+
+// MediaCheck - What a HEAD (and, for videos, ffprobe) found at one media URL
+type MediaCheck struct {
+	URL             string   `json:"url"`
+	Kind            string   `json:"kind"` // video, thumbnail or image
+	Reachable       bool     `json:"reachable"`
+	ContentType     string   `json:"contentType,omitempty"`
+	SizeBytes       int64    `json:"sizeBytes,omitempty"`
+	DurationSeconds *float64 `json:"durationSeconds,omitempty"`
+	Width           *int     `json:"width,omitempty"`
+	Height          *int     `json:"height,omitempty"`
+}
+
+// VideoValidationResult - Pre-publish check of a draft post. Errors would make
+// CreateVideo fail; warnings would not. Caption, Tags and Price are the values the post
+// would be stored with.
+type VideoValidationResult struct {
+	Valid    bool         `json:"valid"`
+	Errors   []string     `json:"errors"`
+	Warnings []string     `json:"warnings"`
+	Caption  string       `json:"caption"`
+	Tags     []string     `json:"tags"`
+	Price    float64      `json:"price"`
+	Media    []MediaCheck `json:"media"`
+}
+
+// MaxImagesPerPostCeiling matches the videos_image_urls_count_check constraint; the
+// configured limit can be lowered but not raised past it
+const MaxImagesPerPostCeiling = 20
+
+// DefaultMaxImagesPerPost is used when no limit is configured
+const DefaultMaxImagesPerPost = 10
+
+var maxImagesPerPost = DefaultMaxImagesPerPost
+
+// SetMaxImagesPerPost changes how many images a multi-image post may have
+func SetMaxImagesPerPost(limit int) error {
+	if limit < 1 || limit > MaxImagesPerPostCeiling {
+		return fmt.Errorf("images per post must be between 1 and %d, got %d", MaxImagesPerPostCeiling, limit)
+	}
+	maxImagesPerPost = limit
+	return nil
+}
+
+// GetMaxImagesPerPost returns how many images a multi-image post may have
+func GetMaxImagesPerPost() int {
+	return maxImagesPerPost
+}
+
 func (v *Video) IsValidForCreation() bool {
 	if v.Caption == "" {
 		return false
 	}
-	if len(v.Caption) > 2200 {
+	if utf8.RuneCountInString(v.Caption) > 2200 {
 		return false
 	}
 	if !v.IsMultipleImages && v.VideoURL == "" {
@@ -129,6 +199,9 @@ func (v *Video) IsValidForCreation() bool {
 	if v.IsMultipleImages && len(v.ImageUrls) == 0 {
 		return false
 	}
+	if len(v.ImageUrls) > maxImagesPerPost {
+		return false
+	}
 	return true
 }
 
@@ -138,7 +211,7 @@ func (v *Video) ValidateForCreation() []string {
 	if v.Caption == "" {
 		errors = append(errors, "caption is required")
 	}
-	if len(v.Caption) > 2200 {
+	if utf8.RuneCountInString(v.Caption) > 2200 {
 		errors = append(errors, "caption must be 2200 characters or less")
 	}
 	if !v.IsMultipleImages && v.VideoURL == "" {
@@ -147,6 +220,9 @@ func (v *Video) ValidateForCreation() []string {
 	if v.IsMultipleImages && len(v.ImageUrls) == 0 {
 		errors = append(errors, "at least one image URL is required for image posts")
 	}
+	if len(v.ImageUrls) > maxImagesPerPost {
+		errors = append(errors, fmt.Sprintf("a post can have at most %d images", maxImagesPerPost))
+	}
 
 	return errors
 }
@@ -164,6 +240,38 @@ type VideoSearchParams struct {
 	MediaType string
 	Featured  *bool
 	Role      *UserRole
+	ViewerID  string // excludes creators blocked by or blocking the viewer
+}
+
+// ===============================
+// COUNT RECONCILIATION
+// ===============================
+
+// CountReconciliation - How many rows had drifted denormalized counts corrected
+type CountReconciliation struct {
+	VideosCorrected int       `json:"videosCorrected"`
+	UsersCorrected  int       `json:"usersCorrected"`
+	CompletedAt     time.Time `json:"completedAt"`
+}
+
+// PublicStats - Platform totals safe to show on the public landing page
+type PublicStats struct {
+	TotalCreators int       `json:"totalCreators" db:"total_creators"`
+	TotalVideos   int       `json:"totalVideos" db:"total_videos"`
+	TotalViews    int64     `json:"totalViews" db:"total_views"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"-"`
+}
+
+// ===============================
+// MEDIA BREAKDOWN
+// ===============================
+
+// MediaBreakdown - Active posts split by media type, platform-wide or for one creator
+type MediaBreakdown struct {
+	UserID     string `json:"userId,omitempty"`
+	VideoPosts int    `json:"videoPosts"`
+	ImagePosts int    `json:"imagePosts"`
+	Total      int    `json:"total"`
 }
 
 // ===============================
@@ -201,6 +309,17 @@ func (vp *VideoPerformance) CalculateEngagementRate() {
 	}
 }
 
+// ===============================
+// TRENDING TAGS
+// ===============================
+
+// TrendingTag - Tag aggregated over the recent window
+type TrendingTag struct {
+	Tag             string  `json:"tag" db:"tag"`
+	VideoCount      int     `json:"videoCount" db:"video_count"`
+	EngagementScore float64 `json:"engagementScore" db:"engagement_score"` // Only meaningful when weighted
+}
+
 // ===============================
 // 🆕 SIMPLIFIED SEARCH MODELS
 // ===============================
@@ -242,6 +361,21 @@ type SearchFilters struct {
 	MinLikes  int    `json:"minLikes"`
 }
 
+// VideoSearchFilters - Optional narrowing applied to fuzzy search results. A nil price
+// bound is unbounded; MediaType is "video", "image" or empty for both.
+type VideoSearchFilters struct {
+	MinPrice     *float64 `json:"minPrice,omitempty"`
+	MaxPrice     *float64 `json:"maxPrice,omitempty"`
+	VerifiedOnly bool     `json:"verifiedOnly"`
+	MediaType    string   `json:"mediaType,omitempty"`
+}
+
+// SearchSuggestion - One type-ahead completion from get_search_suggestions()
+type SearchSuggestion struct {
+	Suggestion string `json:"suggestion" db:"suggestion"`
+	MatchType  string `json:"matchType" db:"match_type"` // "caption" or "username"
+}
+
 // ===============================
 // 🆕 SEARCH HISTORY MODELS
 // ===============================
@@ -288,18 +422,23 @@ type SearchRequest struct {
 // ===============================
 
 type Comment struct {
-	ID                  string    `db:"id" json:"id"`
-	VideoID             string    `db:"video_id" json:"videoId"`
-	AuthorID            string    `db:"author_id" json:"authorId"`
-	AuthorName          string    `db:"author_name" json:"authorName"`
-	AuthorImage         string    `db:"author_image" json:"authorImage"`
-	Content             string    `db:"content" json:"content"`
-	LikesCount          int       `db:"likes_count" json:"likesCount"`
-	IsReply             bool      `db:"is_reply" json:"isReply"`
-	RepliedToCommentID  *string   `db:"replied_to_comment_id" json:"repliedToCommentId,omitempty"`
-	RepliedToAuthorName *string   `db:"replied_to_author_name" json:"repliedToAuthorName,omitempty"`
-	CreatedAt           time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt           time.Time `db:"updated_at" json:"updatedAt"`
+	ID                  string      `db:"id" json:"id"`
+	VideoID             string      `db:"video_id" json:"videoId"`
+	AuthorID            string      `db:"author_id" json:"authorId"`
+	AuthorName          string      `db:"author_name" json:"authorName"`
+	AuthorImage         string      `db:"author_image" json:"authorImage"`
+	Content             string      `db:"content" json:"content"`
+	LikesCount          int         `db:"likes_count" json:"likesCount"`
+	IsReply             bool        `db:"is_reply" json:"isReply"`
+	RepliedToCommentID  *string     `db:"replied_to_comment_id" json:"repliedToCommentId,omitempty"`
+	RepliedToAuthorName *string     `db:"replied_to_author_name" json:"repliedToAuthorName,omitempty"`
+	Mentions            StringSlice `db:"mentions" json:"mentions"`    // UIDs of @mentioned users
+	IsFlagged           bool        `db:"is_flagged" json:"isFlagged"` // held for admin review by the content moderator
+	FlagReason          string      `db:"flag_reason" json:"-"`
+	IsPinned            bool        `db:"is_pinned" json:"isPinned"` // pinned by the video owner; listed first
+	CreatedAt           time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt           time.Time   `db:"updated_at" json:"updatedAt"`
+	IsLiked             bool        `db:"is_liked" json:"isLiked"` // viewer state; computed per query
 }
 
 type CreateCommentRequest struct {
@@ -325,6 +464,30 @@ func (c *Comment) ValidateForCreation() []string {
 	return errors
 }
 
+// MaxCommentMentions caps how many @mentions in a single comment are resolved
+const MaxCommentMentions = 10
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.]{2,30})`)
+
+// ExtractMentions returns the distinct, lowercased @handles in content, up to limit.
+// A handle is the user's display name with spaces replaced by underscores.
+func ExtractMentions(content string, limit int) []string {
+	seen := make(map[string]bool)
+	handles := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], "."))
+		if len(handle) < 2 || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+		if len(handles) >= limit {
+			break
+		}
+	}
+	return handles
+}
+
 // ===============================
 // HELPER FUNCTIONS
 // ===============================
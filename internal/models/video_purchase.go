@@ -0,0 +1,50 @@
+// ===============================
+// internal/models/video_purchase.go - Paid Video Access Models
+// ===============================
+
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// VideoAccess - Whether a viewer may watch a video and why
+type VideoAccess struct {
+	VideoID     string  `json:"videoId" db:"id"`
+	OwnerID     string  `json:"-" db:"user_id"`
+	VideoURL    string  `json:"-" db:"video_url"`
+	Price       float64 `json:"price" db:"price"`
+	IsActive    bool    `json:"-" db:"is_active"`
+	IsPurchased bool    `json:"isPurchased" db:"is_purchased"`
+	IsOwner     bool    `json:"isOwner" db:"-"`
+	HasAccess   bool    `json:"hasAccess" db:"-"`
+	UserBalance *int    `json:"userBalance,omitempty" db:"-"` // only for signed-in viewers without access
+	CanUnlock   *bool   `json:"canUnlock,omitempty" db:"-"`
+}
+
+// IsPaid reports whether the video currently has a price
+func (a *VideoAccess) IsPaid() bool {
+	return a.Price > 0
+}
+
+// HasVideoAccess decides whether a viewer may watch a video: free videos are open to all,
+// priced ones to the owner and to anyone holding a purchase. The purchase is what counts,
+// not the price paid, so a later price change never revokes it.
+func HasVideoAccess(price float64, isOwner, isPurchased bool) bool {
+	return price <= 0 || isOwner || isPurchased
+}
+
+// VideoPurchase - A viewer's paid entitlement to a video
+type VideoPurchase struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"userId" db:"user_id"`
+	VideoID   string    `json:"videoId" db:"video_id"`
+	PricePaid float64   `json:"pricePaid" db:"price_paid"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// VideoPriceInCoins converts a video price to the whole number of coins charged
+func VideoPriceInCoins(price float64) int {
+	return int(math.Ceil(price))
+}
@@ -290,6 +290,57 @@ type MessagesListResponse struct {
 	PinnedMessages []VideoReactionMessageResponse `json:"pinnedMessages"`
 }
 
+// ===============================
+// CHAT EXPORT MODELS
+// ===============================
+
+type ChatExportMessage struct {
+	MessageID  string      `json:"messageId" db:"message_id"`
+	SenderID   string      `json:"senderId" db:"sender_id"`
+	SenderName string      `json:"senderName" db:"-"`
+	Content    string      `json:"content" db:"content"`
+	Type       MessageType `json:"type" db:"type"`
+	MediaURL   *string     `json:"mediaUrl" db:"media_url"`
+	FileName   *string     `json:"fileName" db:"file_name"`
+	IsEdited   bool        `json:"isEdited" db:"is_edited"`
+	Timestamp  time.Time   `json:"timestamp" db:"timestamp"`
+}
+
+// TranscriptLine renders the message as a single line of a text transcript
+func (m *ChatExportMessage) TranscriptLine() string {
+	content := m.Content
+	switch m.Type {
+	case MessageTypeImage, MessageTypeVideo, MessageTypeFile, MessageTypeAudio:
+		name := ""
+		if m.FileName != nil {
+			name = " " + *m.FileName
+		}
+		if m.MediaURL != nil && *m.MediaURL != "" {
+			content = fmt.Sprintf("[%s%s] %s %s", m.Type, name, *m.MediaURL, m.Content)
+		} else {
+			content = fmt.Sprintf("[%s%s] %s", m.Type, name, m.Content)
+		}
+	case MessageTypeLocation, MessageTypeContact:
+		content = fmt.Sprintf("[%s] %s", m.Type, m.Content)
+	}
+
+	edited := ""
+	if m.IsEdited {
+		edited = " (edited)"
+	}
+
+	return fmt.Sprintf("[%s] %s: %s%s", m.Timestamp.UTC().Format("2006-01-02 15:04:05"), m.SenderName, content, edited)
+}
+
+type ChatExport struct {
+	ChatID       string              `json:"chatId"`
+	Participants StringSlice         `json:"participants"`
+	Messages     []ChatExportMessage `json:"messages"`
+	Total        int                 `json:"total"`
+	Truncated    bool                `json:"truncated"`
+	ExportedAt   time.Time           `json:"exportedAt"`
+}
+
 // ===============================
 // CUSTOM TYPES FOR JSONB FIELDS
 // ===============================
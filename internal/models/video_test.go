@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetMaxImagesPerPost(t *testing.T) {
+	defer SetMaxImagesPerPost(GetMaxImagesPerPost())
+
+	tests := []struct {
+		limit   int
+		wantErr bool
+	}{
+		{limit: 0, wantErr: true},
+		{limit: 1},
+		{limit: MaxImagesPerPostCeiling - 1},
+		{limit: MaxImagesPerPostCeiling},
+		{limit: MaxImagesPerPostCeiling + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.limit), func(t *testing.T) {
+			before := GetMaxImagesPerPost()
+			err := SetMaxImagesPerPost(tt.limit)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SetMaxImagesPerPost(%d) succeeded, want error", tt.limit)
+				}
+				if got := GetMaxImagesPerPost(); got != before {
+					t.Errorf("rejected limit changed the setting: got %d, want %d", got, before)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetMaxImagesPerPost(%d) error: %v", tt.limit, err)
+			}
+			if got := GetMaxImagesPerPost(); got != tt.limit {
+				t.Errorf("GetMaxImagesPerPost() = %d, want %d", got, tt.limit)
+			}
+		})
+	}
+}
+
+func TestValidateImageCount(t *testing.T) {
+	defer SetMaxImagesPerPost(GetMaxImagesPerPost())
+
+	const limit = 5
+	if err := SetMaxImagesPerPost(limit); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		images int
+		valid  bool
+	}{
+		{images: limit - 1, valid: true},
+		{images: limit, valid: true},
+		{images: limit + 1, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.images), func(t *testing.T) {
+			video := Video{Caption: "post", IsMultipleImages: true}
+			for i := 0; i < tt.images; i++ {
+				video.ImageUrls = append(video.ImageUrls, fmt.Sprintf("https://cdn.example.com/%d.jpg", i))
+			}
+
+			if got := video.IsValidForCreation(); got != tt.valid {
+				t.Errorf("IsValidForCreation() with %d images = %v, want %v", tt.images, got, tt.valid)
+			}
+			errs := video.ValidateForCreation()
+			if valid := len(errs) == 0; valid != tt.valid {
+				t.Errorf("ValidateForCreation() with %d images = %v, want valid %v", tt.images, errs, tt.valid)
+			}
+		})
+	}
+}
@@ -20,6 +20,13 @@ type Wallet struct {
 	UpdatedAt       time.Time `json:"updatedAt" db:"updated_at"`
 }
 
+// WalletBalance - Just the balance, for the app header's frequent poll. UpdatedAt is nil
+// until the user has a wallet.
+type WalletBalance struct {
+	CoinsBalance int        `json:"coinsBalance" db:"coins_balance"`
+	UpdatedAt    *time.Time `json:"updatedAt" db:"updated_at"`
+}
+
 type WalletTransaction struct {
 	TransactionID    string      `json:"transactionId" db:"transaction_id"`
 	WalletID         string      `json:"walletId" db:"wallet_id"`
@@ -39,6 +46,20 @@ type WalletTransaction struct {
 	PaidAmount       *float64    `json:"paidAmount" db:"paid_amount"`
 	Metadata         MetadataMap `json:"metadata" db:"metadata"`
 	CreatedAt        time.Time   `json:"createdAt" db:"created_at"`
+	IsArchived       bool        `json:"isArchived,omitempty" db:"is_archived"`
+}
+
+// WalletLedgerMeta - Optional details recorded on the wallet_transactions row
+// written by WalletService.Debit/Credit
+type WalletLedgerMeta struct {
+	Description      string
+	ReferenceID      *string
+	AdminNote        *string
+	PaymentMethod    *string
+	PaymentReference *string
+	PackageID        *string
+	PaidAmount       *float64
+	Metadata         MetadataMap
 }
 
 type MetadataMap map[string]interface{}
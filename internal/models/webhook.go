@@ -0,0 +1,139 @@
+// ===============================
+// internal/models/webhook.go - Outbound Webhook Models
+// ===============================
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventGiftSent         = "gift.sent"
+	WebhookEventPurchaseApproved = "purchase.approved"
+	WebhookEventVideoPurchased   = "video.purchased"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook request headers. The signature is "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookEvents lists the events a webhook may subscribe to
+var WebhookEvents = []string{
+	WebhookEventGiftSent,
+	WebhookEventPurchaseApproved,
+	WebhookEventVideoPurchased,
+}
+
+// IsValidWebhookEvent reports whether event is one of WebhookEvents
+func IsValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook - A subscribed integrator URL. The secret is only returned when the webhook
+// is created.
+type Webhook struct {
+	ID          string      `json:"id" db:"id"`
+	URL         string      `json:"url" db:"url"`
+	Secret      string      `json:"-" db:"secret"`
+	Events      StringSlice `json:"events" db:"events"`
+	Description string      `json:"description" db:"description"`
+	IsActive    bool        `json:"isActive" db:"is_active"`
+	CreatedBy   *string     `json:"createdBy" db:"created_by"`
+	CreatedAt   time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updatedAt" db:"updated_at"`
+}
+
+// CreateWebhookRequest - Subscribing a URL to one or more events
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Description string   `json:"description"`
+}
+
+// UpdateWebhookRequest - Partial update; omitted fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+// WebhookPayload - The JSON body POSTed to subscribers
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery - One attempt log entry for an event sent to a webhook
+type WebhookDelivery struct {
+	ID             string          `json:"id" db:"id"`
+	WebhookID      string          `json:"webhookId" db:"webhook_id"`
+	Event          string          `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastStatusCode *int            `json:"lastStatusCode" db:"last_status_code"`
+	LastError      *string         `json:"lastError" db:"last_error"`
+	DeliveredAt    *time.Time      `json:"deliveredAt" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// GiftSentWebhookData - Data for gift.sent
+type GiftSentWebhookData struct {
+	TransactionID      string    `json:"transactionId"`
+	SenderID           string    `json:"senderId"`
+	RecipientID        string    `json:"recipientId"`
+	VideoID            *string   `json:"videoId"`
+	GiftID             string    `json:"giftId"`
+	GiftName           string    `json:"giftName"`
+	GiftPrice          int       `json:"giftPrice"`
+	RecipientAmount    int       `json:"recipientAmount"`
+	PlatformCommission int       `json:"platformCommission"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+// PurchaseApprovedWebhookData - Data for purchase.approved
+type PurchaseApprovedWebhookData struct {
+	RequestID        string    `json:"requestId"`
+	UserID           string    `json:"userId"`
+	PackageID        string    `json:"packageId"`
+	CoinAmount       int       `json:"coinAmount"`
+	PaidAmount       float64   `json:"paidAmount"`
+	PaymentMethod    string    `json:"paymentMethod"`
+	PaymentReference string    `json:"paymentReference"`
+	ApprovedAt       time.Time `json:"approvedAt"`
+}
+
+// VideoPurchasedWebhookData - Data for video.purchased
+type VideoPurchasedWebhookData struct {
+	PurchaseID    string    `json:"purchaseId"`
+	BuyerID       string    `json:"buyerId"`
+	CreatorID     string    `json:"creatorId"`
+	VideoID       string    `json:"videoId"`
+	CoinsPaid     int       `json:"coinsPaid"`
+	CreatorAmount int       `json:"creatorAmount"`
+	PurchasedAt   time.Time `json:"purchasedAt"`
+}
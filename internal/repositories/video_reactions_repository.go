@@ -271,6 +271,78 @@ func (r *VideoReactionsRepository) GetMessageByID(ctx context.Context, messageID
 	return &message, err
 }
 
+// ===============================
+// MESSAGE REPORTS
+// ===============================
+
+// CreateMessageReport stores an open report and snapshots the message content. It returns
+// an empty ID when the reporter already has an open report on the message.
+func (r *VideoReactionsRepository) CreateMessageReport(ctx context.Context, message *models.VideoReactionMessage, reporterID, reason, description string) (string, error) {
+	var reportIDs []string
+	err := r.db.SelectContext(ctx, &reportIDs, `
+		INSERT INTO message_reports (message_id, chat_id, sender_id, reporter_id, reason, description, content)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (message_id, reporter_id) WHERE status = 'open' DO NOTHING
+		RETURNING id`,
+		message.MessageID, message.ChatID, message.SenderID, reporterID, reason, description, message.Content)
+	if err != nil || len(reportIDs) == 0 {
+		return "", err
+	}
+	return reportIDs[0], nil
+}
+
+// FlagRepeatedlyReportedSender marks the sender once at least threshold different users
+// hold open reports against their messages. It reports true only when this call set the
+// flag.
+func (r *VideoReactionsRepository) FlagRepeatedlyReportedSender(ctx context.Context, senderID string, threshold int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET messaging_flagged_at = NOW()
+		WHERE uid = $1 AND messaging_flagged_at IS NULL
+		  AND (SELECT COUNT(DISTINCT reporter_id) FROM message_reports
+		       WHERE sender_id = $1 AND status = 'open') >= $2`,
+		senderID, threshold)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// GetReportedMessages lists messages with open reports, most-reported first
+func (r *VideoReactionsRepository) GetReportedMessages(ctx context.Context, limit, offset int) ([]models.ReportedMessage, int, error) {
+	var rows []struct {
+		models.ReportedMessage
+		TotalCount int `db:"total_count"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT mr.message_id, mr.chat_id, mr.sender_id,
+		       COALESCE(u.name, '') AS sender_name,
+		       u.messaging_flagged_at IS NOT NULL AS sender_flagged,
+		       (array_agg(mr.content ORDER BY mr.created_at DESC))[1] AS content,
+		       COUNT(*) AS report_count,
+		       array_agg(DISTINCT mr.reason) AS report_reasons,
+		       MIN(mr.created_at) AS first_reported_at,
+		       MAX(mr.created_at) AS last_reported_at,
+		       COUNT(*) OVER() AS total_count
+		FROM message_reports mr
+		LEFT JOIN users u ON u.uid = mr.sender_id
+		WHERE mr.status = 'open'
+		GROUP BY mr.message_id, mr.chat_id, mr.sender_id, u.name, u.messaging_flagged_at
+		ORDER BY report_count DESC, last_reported_at DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	messages := make([]models.ReportedMessage, len(rows))
+	total := 0
+	for i, row := range rows {
+		messages[i] = row.ReportedMessage
+		total = row.TotalCount
+	}
+	return messages, total, nil
+}
+
 // GetChatMessages retrieves messages from a chat
 func (r *VideoReactionsRepository) GetChatMessages(ctx context.Context, chatID string, limit, offset int) ([]models.VideoReactionMessage, error) {
 	var messages []models.VideoReactionMessage
@@ -288,6 +360,20 @@ func (r *VideoReactionsRepository) GetChatMessages(ctx context.Context, chatID s
 	return messages, err
 }
 
+// GetChatMessagesForExport retrieves a chat transcript in chronological order
+func (r *VideoReactionsRepository) GetChatMessagesForExport(ctx context.Context, chatID string, limit int) ([]models.ChatExportMessage, error) {
+	messages := []models.ChatExportMessage{}
+	query := `
+		SELECT message_id, sender_id, content, type, media_url, file_name, is_edited, timestamp
+		FROM video_reaction_messages
+		WHERE chat_id = $1
+		ORDER BY timestamp ASC
+		LIMIT $2`
+
+	err := r.db.SelectContext(ctx, &messages, query, chatID, limit)
+	return messages, err
+}
+
 // GetPinnedMessages retrieves all pinned messages in a chat
 func (r *VideoReactionsRepository) GetPinnedMessages(ctx context.Context, chatID string) ([]models.VideoReactionMessage, error) {
 	var messages []models.VideoReactionMessage
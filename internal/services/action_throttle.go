@@ -0,0 +1,104 @@
+// ===============================
+// internal/services/action_throttle.go - Per-User Engagement Throttles
+// ===============================
+
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"weibaobe/internal/config"
+)
+
+// Throttled engagement actions
+const (
+	actionLike    = "like"
+	actionFollow  = "follow"
+	actionComment = "comment"
+)
+
+// ThrottledError is returned when a user has used up an action's allowance for the
+// current window. It reads as "rate_limited" like the other service sentinels.
+type ThrottledError struct {
+	Action     string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return "rate_limited"
+}
+
+type throttleBucket struct {
+	count       int
+	windowStart time.Time
+}
+
+// ActionThrottle caps how many likes, follows and comments one user can make per window,
+// so a single account can't farm engagement or flood someone with notifications. The IP
+// limiter doesn't catch this because the requests are spread over normal traffic. Counts
+// are per process, which is enough to make spam expensive.
+type ActionThrottle struct {
+	window time.Duration
+	limits map[string]int
+
+	mu        sync.Mutex
+	buckets   map[string]*throttleBucket
+	lastSweep time.Time
+}
+
+// NewActionThrottle builds the throttle from config. A limit of 0 leaves that action
+// unthrottled.
+func NewActionThrottle(cfg config.ActionThrottleConfig) *ActionThrottle {
+	return &ActionThrottle{
+		window: cfg.Window,
+		limits: map[string]int{
+			actionLike:    cfg.Likes,
+			actionFollow:  cfg.Follows,
+			actionComment: cfg.Comments,
+		},
+		buckets:   make(map[string]*throttleBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// check counts one action by userID, returning a *ThrottledError once the window's
+// allowance is used up. A nil throttle allows everything.
+func (t *ActionThrottle) check(action, userID string) error {
+	if t == nil || t.window <= 0 {
+		return nil
+	}
+	limit := t.limits[action]
+	if limit <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	key := fmt.Sprintf("%s:%s", action, userID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Drop finished windows now and then so idle users don't pile up
+	if now.Sub(t.lastSweep) > 10*t.window {
+		for k, bucket := range t.buckets {
+			if now.Sub(bucket.windowStart) >= t.window {
+				delete(t.buckets, k)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	bucket, ok := t.buckets[key]
+	if !ok || now.Sub(bucket.windowStart) >= t.window {
+		t.buckets[key] = &throttleBucket{count: 1, windowStart: now}
+		return nil
+	}
+
+	if bucket.count >= limit {
+		return &ThrottledError{Action: action, RetryAfter: bucket.windowStart.Add(t.window).Sub(now)}
+	}
+	bucket.count++
+	return nil
+}
@@ -0,0 +1,119 @@
+// ===============================
+// internal/services/admin.go - Admin Permission Service
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AdminService struct {
+	db *sqlx.DB
+}
+
+func NewAdminService(db *sqlx.DB) *AdminService {
+	return &AdminService{db: db}
+}
+
+// isAdminAccount checks the account-level admin flag that gates every admin route
+func (s *AdminService) isAdminAccount(ctx context.Context, userID string) (bool, error) {
+	var isAdmin bool
+	err := s.db.GetContext(ctx, &isAdmin, `
+		SELECT EXISTS(
+			SELECT 1 FROM users
+			WHERE uid = $1 AND (user_type = 'admin' OR role = 'admin')
+		)`, userID)
+	return isAdmin, err
+}
+
+// ListAdmins returns all admin accounts with their granted permissions
+func (s *AdminService) ListAdmins(ctx context.Context) ([]models.AdminWithPermissions, error) {
+	query := `
+		SELECT u.uid, u.name, u.profile_image,
+		       COALESCE(array_agg(p.permission ORDER BY p.permission)
+		                FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions
+		FROM users u
+		LEFT JOIN admin_permissions p ON p.user_id = u.uid
+		WHERE u.user_type = 'admin' OR u.role = 'admin'
+		GROUP BY u.uid, u.name, u.profile_image
+		ORDER BY u.name`
+
+	var admins []models.AdminWithPermissions
+	if err := s.db.SelectContext(ctx, &admins, query); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// GetPermissions returns the permissions granted to an admin
+func (s *AdminService) GetPermissions(ctx context.Context, userID string) ([]models.AdminPermissionGrant, error) {
+	var grants []models.AdminPermissionGrant
+	err := s.db.SelectContext(ctx, &grants, `
+		SELECT user_id, permission, granted_by, granted_at
+		FROM admin_permissions
+		WHERE user_id = $1
+		ORDER BY permission`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// GrantPermission gives an admin account a permission
+func (s *AdminService) GrantPermission(ctx context.Context, userID string, permission models.AdminPermission, grantedBy string) error {
+	if !permission.IsValid() {
+		return errors.New("invalid_permission")
+	}
+
+	isAdmin, err := s.isAdminAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return errors.New("user_not_admin")
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_permissions (user_id, permission, granted_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, permission) DO NOTHING`,
+		userID, permission, grantedBy)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("permission_already_granted")
+	}
+	return nil
+}
+
+// RevokePermission removes a permission from an admin. Admins cannot drop their own
+// super_admin permission, so there is always someone left to manage permissions.
+func (s *AdminService) RevokePermission(ctx context.Context, userID string, permission models.AdminPermission, revokedBy string) error {
+	if !permission.IsValid() {
+		return errors.New("invalid_permission")
+	}
+
+	if userID == revokedBy && permission == models.PermissionSuperAdmin {
+		return errors.New("cannot_revoke_own_super_admin")
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM admin_permissions WHERE user_id = $1 AND permission = $2`,
+		userID, permission)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("permission_not_found")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"weibaobe/internal/models"
+)
+
+func TestSuppressedBucketsHideSmallCounts(t *testing.T) {
+	const minSize = models.AudienceMinBucketSize
+
+	tests := []struct {
+		name   string
+		counts map[string]int
+		want   []models.AudienceBucket
+	}{
+		{
+			name:   "nothing suppressed",
+			counts: map[string]int{"male": 3 * minSize, "female": 2 * minSize},
+			want:   []models.AudienceBucket{{Label: "male", Count: 3 * minSize}, {Label: "female", Count: 2 * minSize}},
+		},
+		{
+			name:   "small buckets large enough together",
+			counts: map[string]int{"nairobi": 3 * minSize, "kisumu": minSize - 1, "nakuru": minSize - 1},
+			want:   []models.AudienceBucket{{Label: "nairobi", Count: 3 * minSize}, {Label: "other", Count: 2*minSize - 2}},
+		},
+		{
+			name:   "single small bucket pulls in the smallest shown one",
+			counts: map[string]int{"male": 3 * minSize, "female": 2 * minSize, "unspecified": 1},
+			want:   []models.AudienceBucket{{Label: "male", Count: 3 * minSize}, {Label: "other", Count: 2*minSize + 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 0
+			for _, count := range tt.counts {
+				total += count
+			}
+
+			got := suppressedBuckets(tt.counts, total)
+			if len(got) != len(tt.want) {
+				t.Fatalf("suppressedBuckets = %+v, want %+v", got, tt.want)
+			}
+			shown := 0
+			for i := range tt.want {
+				if got[i].Label != tt.want[i].Label || got[i].Count != tt.want[i].Count {
+					t.Fatalf("suppressedBuckets = %+v, want %+v", got, tt.want)
+				}
+				if got[i].Count < minSize {
+					t.Errorf("bucket %q has %d followers, below the minimum %d", got[i].Label, got[i].Count, minSize)
+				}
+				shown += got[i].Count
+			}
+			// Nothing is left for total minus the shown buckets to reveal
+			if shown != total {
+				t.Errorf("shown buckets add up to %d, want the total %d", shown, total)
+			}
+		})
+	}
+}
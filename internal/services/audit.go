@@ -0,0 +1,106 @@
+// ===============================
+// internal/services/audit.go - Admin Audit Log Service
+// ===============================
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditService struct {
+	db *sqlx.DB
+}
+
+func NewAuditService(db *sqlx.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Log records an admin action. Auditing never blocks the action itself, so failures are
+// logged rather than returned.
+func (s *AuditService) Log(ctx context.Context, adminID, action, targetType, targetID string, details models.MetadataMap) {
+	if details == nil {
+		details = models.MetadataMap{}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_audit_log (admin_id, action, target_type, target_id, details)
+		VALUES ($1, $2, $3, $4, $5)`,
+		adminID, action, targetType, targetID, details)
+	if err != nil {
+		log.Printf("⚠️ Failed to write audit log entry %s on %s/%s by %s: %v",
+			action, targetType, targetID, adminID, err)
+	}
+}
+
+// GetAuditLog returns audit entries matching the filter, newest first, with the total count
+func (s *AuditService) GetAuditLog(ctx context.Context, filter models.AuditLogFilter, limit, offset int) ([]models.AdminAuditEntry, int, error) {
+	query := `
+		SELECT a.id, a.admin_id, u.name AS admin_name, a.action, a.target_type, a.target_id,
+		       a.details, a.created_at,
+		       COUNT(*) OVER() AS total_count
+		FROM admin_audit_log a
+		LEFT JOIN users u ON u.uid = a.admin_id
+		WHERE 1=1`
+
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.AdminID != "" {
+		query += fmt.Sprintf(" AND a.admin_id = $%d", argIndex)
+		args = append(args, filter.AdminID)
+		argIndex++
+	}
+
+	if filter.Action != "" {
+		query += fmt.Sprintf(" AND a.action = $%d", argIndex)
+		args = append(args, filter.Action)
+		argIndex++
+	}
+
+	if filter.From != nil {
+		query += fmt.Sprintf(" AND a.created_at >= $%d", argIndex)
+		args = append(args, *filter.From)
+		argIndex++
+	}
+
+	if filter.To != nil {
+		query += fmt.Sprintf(" AND a.created_at < $%d", argIndex)
+		args = append(args, *filter.To)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY a.created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.AdminAuditEntry{}
+	total := 0
+	for rows.Next() {
+		var row struct {
+			models.AdminAuditEntry
+			TotalCount int `db:"total_count"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, 0, err
+		}
+		total = row.TotalCount
+		entries = append(entries, row.AdminAuditEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,65 @@
+// ===============================
+// internal/services/best_times.go - Creator Best Posting Times
+// ===============================
+
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"weibaobe/internal/models"
+)
+
+// bestTimesTopSlots is how many of the best slots are called out separately
+const bestTimesTopSlots = 3
+
+// GetBestPostingTimes groups the creator's active videos from the last days days by the
+// weekday and hour they were posted in timezone, and averages the engagement each slot
+// went on to earn, scored with the same weights as trending
+func (s *UserService) GetBestPostingTimes(ctx context.Context, creatorID, timezone string, days int) (*models.BestPostingTimes, error) {
+	var slots []models.PostingTimeSlot
+	err := s.db.SelectContext(ctx, &slots, `
+		SELECT EXTRACT(DOW FROM v.created_at AT TIME ZONE $2)::int AS day_of_week,
+		       EXTRACT(HOUR FROM v.created_at AT TIME ZONE $2)::int AS hour,
+		       COUNT(*) AS videos,
+		       ROUND(AVG(v.views_count)::numeric, 1)::float8 AS avg_views,
+		       ROUND(AVG(v.likes_count * 2.5 + v.comments_count * 3.5
+		                 + v.shares_count * 5.0 + v.views_count * 0.1)::numeric, 1)::float8 AS avg_engagement
+		FROM videos v
+		WHERE v.user_id = $1 AND v.is_active = true AND v.created_at >= $3
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		creatorID, timezone, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BestPostingTimes{
+		Timezone: timezone,
+		Days:     days,
+		Slots:    slots,
+		TopSlots: []models.PostingTimeSlot{},
+	}
+	if result.Slots == nil {
+		result.Slots = []models.PostingTimeSlot{}
+	}
+
+	for _, slot := range slots {
+		result.VideosAnalyzed += slot.Videos
+		result.Heatmap[slot.DayOfWeek][slot.Hour] = math.Round(slot.AvgEngagement*10) / 10
+	}
+
+	top := append([]models.PostingTimeSlot(nil), slots...)
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].AvgEngagement > top[j].AvgEngagement
+	})
+	if len(top) > bestTimesTopSlots {
+		top = top[:bestTimesTopSlots]
+	}
+	result.TopSlots = append(result.TopSlots, top...)
+
+	return result, nil
+}
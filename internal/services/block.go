@@ -0,0 +1,155 @@
+// ===============================
+// internal/services/block.go - User Blocking Service
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type BlockService struct {
+	db           *sqlx.DB
+	profileCache *ProfileCache
+}
+
+func NewBlockService(db *sqlx.DB, profileCache *ProfileCache) *BlockService {
+	return &BlockService{db: db, profileCache: profileCache}
+}
+
+// blockedPairExists returns an EXISTS expression that is true when the author column and the
+// viewer (bound at argument viewerArg) have blocked each other in either direction
+func blockedPairExists(authorColumn string, viewerArg int) string {
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM blocked_contacts bc
+			WHERE (bc.blocker_id = $%[2]d AND bc.blocked_id = %[1]s)
+			   OR (bc.blocker_id = %[1]s AND bc.blocked_id = $%[2]d))`, authorColumn, viewerArg)
+}
+
+// ===============================
+// BLOCK / UNBLOCK
+// ===============================
+
+// BlockUser blocks a user and removes any follow relationship between the pair
+func (s *BlockService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	if blockerID == blockedID {
+		return errors.New("cannot_block_self")
+	}
+
+	var exists bool
+	err := s.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM users WHERE uid = $1)", blockedID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("user_not_found")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO blocked_contacts (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING`,
+		blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("already_blocked")
+	}
+
+	// Follow counts are kept in sync by the user_follows trigger
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM user_follows
+		WHERE (follower_id = $1 AND following_id = $2)
+		   OR (follower_id = $2 AND following_id = $1)`,
+		blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM follow_requests
+		WHERE status = 'pending'
+		  AND ((requester_id = $1 AND target_id = $2) OR (requester_id = $2 AND target_id = $1))`,
+		blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.profileCache.Invalidate(blockerID, blockedID)
+	return nil
+}
+
+// GetBlockedUsers returns the profiles the caller has blocked, newest block first
+func (s *BlockService) GetBlockedUsers(ctx context.Context, blockerID string, limit, offset int) ([]models.BlockedUser, int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM blocked_contacts WHERE blocker_id = $1", blockerID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.uid, u.name, u.profile_image, u.bio, u.is_verified, bc.created_at as blocked_at
+		FROM blocked_contacts bc
+		JOIN users u ON u.uid = bc.blocked_id
+		WHERE bc.blocker_id = $1
+		ORDER BY bc.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	users := []models.BlockedUser{}
+	if err := s.db.SelectContext(ctx, &users, query, blockerID, limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// AreUsersBlocked reports whether either user has blocked the other
+func (s *BlockService) AreUsersBlocked(ctx context.Context, userA, userB string) (bool, error) {
+	var blocked bool
+	err := s.db.GetContext(ctx, &blocked, `
+		SELECT EXISTS(
+			SELECT 1 FROM blocked_contacts
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+			   OR (blocker_id = $2 AND blocked_id = $1)
+		)`, userA, userB)
+	return blocked, err
+}
+
+func (s *BlockService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM blocked_contacts WHERE blocker_id = $1 AND blocked_id = $2", blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("not_blocked")
+	}
+
+	return nil
+}
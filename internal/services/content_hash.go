@@ -0,0 +1,128 @@
+// ===============================
+// internal/services/content_hash.go - Duplicate Upload Detection
+// ===============================
+
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/logging"
+	"weibaobe/internal/models"
+)
+
+// duplicateUploadPolicy is applied when a new post's media matches an active video:
+// ModerationFlag queues it for review, ModerationReject refuses it and ModerationAllow
+// skips the check. With sameUserOnly, only the uploader's own videos are matched.
+type duplicateUploadPolicy struct {
+	action       models.ModerationAction
+	sameUserOnly bool
+}
+
+func newDuplicateUploadPolicy(cfg config.DuplicateUploadConfig) duplicateUploadPolicy {
+	policy := duplicateUploadPolicy{action: models.ModerationFlag, sameUserOnly: cfg.Scope == "user"}
+	switch cfg.Action {
+	case "reject":
+		policy.action = models.ModerationReject
+	case "off":
+		policy.action = models.ModerationAllow
+	}
+	return policy
+}
+
+// contentHash identifies a post's media by the storage ETag of its objects, which covers
+// proxied and presigned uploads alike without re-reading the file. Media outside our
+// bucket has no hash and is never treated as a duplicate.
+func (s *VideoService) contentHash(ctx context.Context, video *models.Video) (string, error) {
+	urls := []string{video.VideoURL}
+	if video.IsMultipleImages || video.VideoURL == "" {
+		urls = video.ImageUrls
+	}
+
+	etags := make([]string, 0, len(urls))
+	for _, url := range urls {
+		key, ok := s.r2Client.KeyFromURL(url)
+		if !ok {
+			return "", nil
+		}
+		info, err := s.r2Client.StatObject(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		if info.ETag == "" {
+			return "", nil
+		}
+		etags = append(etags, info.ETag)
+	}
+
+	switch len(etags) {
+	case 0:
+		return "", nil
+	case 1:
+		return etags[0], nil
+	default:
+		// Image posts match only when every image matches, in order
+		sum := sha256.Sum256([]byte(strings.Join(etags, "|")))
+		return "images:" + hex.EncodeToString(sum[:]), nil
+	}
+}
+
+// checkDuplicateContent records the media hash on video and applies the duplicate upload
+// policy when an active video in its scope already has it. It returns "duplicate_content"
+// when the upload must be rejected. Storage errors skip the check rather than block the
+// post.
+func (s *VideoService) checkDuplicateContent(ctx context.Context, video *models.Video) error {
+	if s.duplicates.action == models.ModerationAllow || s.r2Client == nil {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx).With("user_id", video.UserID)
+
+	hash, err := s.contentHash(ctx, video)
+	if err != nil {
+		logger.Warn("content hash unavailable, skipping duplicate check", "error", err)
+		return nil
+	}
+	if hash == "" {
+		return nil
+	}
+	video.ContentHash = &hash
+
+	var original struct {
+		ID     string `db:"id"`
+		UserID string `db:"user_id"`
+	}
+	err = s.db.GetContext(ctx, &original, `
+		SELECT id, user_id FROM videos
+		WHERE video_content_hash = $1 AND is_active = true
+		  AND (NOT $2 OR user_id = $3)
+		ORDER BY created_at
+		LIMIT 1`, hash, s.duplicates.sameUserOnly, video.UserID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("duplicate upload detected",
+		"original_video_id", original.ID, "original_user_id", original.UserID, "action", s.duplicates.action)
+
+	if s.duplicates.action == models.ModerationReject {
+		return errors.New("duplicate_content")
+	}
+
+	reason := "duplicate of video " + original.ID
+	if video.FlagReason != "" {
+		reason = video.FlagReason + "; " + reason
+	}
+	video.IsFlagged = true
+	video.FlagReason = reason
+	return nil
+}
@@ -0,0 +1,95 @@
+// ===============================
+// internal/services/content_moderator.go - Caption and Comment Moderation Hook
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/models"
+)
+
+// ContentModerator screens user-written text before it is stored. Implementations
+// decide whether the text is allowed, stored flagged for admin review, or rejected,
+// so an external moderation API can replace the word list without touching callers.
+type ContentModerator interface {
+	Moderate(ctx context.Context, text string) (models.ModerationDecision, error)
+}
+
+// moderateText runs text through the configured moderator. Rejected text returns the
+// content_rejected error; flagged text is reported so the caller stores it for review.
+func (s *VideoService) moderateText(ctx context.Context, text string) (bool, string, error) {
+	if s.moderator == nil {
+		return false, "", nil
+	}
+
+	decision, err := s.moderator.Moderate(ctx, text)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch decision.Action {
+	case models.ModerationReject:
+		return false, "", errors.New("content_rejected")
+	case models.ModerationFlag:
+		return true, decision.Reason, nil
+	default:
+		return false, "", nil
+	}
+}
+
+// NewContentModerator builds the default word-list moderator from configuration
+func NewContentModerator(cfg config.ModerationConfig) ContentModerator {
+	action := models.ModerationFlag
+	if strings.EqualFold(cfg.Action, string(models.ModerationReject)) {
+		action = models.ModerationReject
+	}
+	return NewWordListModerator(cfg.BlockedTerms, action)
+}
+
+// WordListModerator matches whole words and phrases against a blocked-term list,
+// ignoring case and punctuation, and applies one configured action to any match.
+type WordListModerator struct {
+	terms  []string
+	action models.ModerationAction
+}
+
+func NewWordListModerator(terms []string, action models.ModerationAction) *WordListModerator {
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = normalizeModerationText(term); term != "" {
+			normalized = append(normalized, term)
+		}
+	}
+	return &WordListModerator{terms: normalized, action: action}
+}
+
+func (m *WordListModerator) Moderate(ctx context.Context, text string) (models.ModerationDecision, error) {
+	// Pad with spaces so terms only match on word boundaries
+	padded := " " + normalizeModerationText(text) + " "
+
+	for _, term := range m.terms {
+		if strings.Contains(padded, " "+term+" ") {
+			return models.ModerationDecision{
+				Action: m.action,
+				Reason: "blocked term: " + term,
+			}, nil
+		}
+	}
+
+	return models.ModerationDecision{Action: models.ModerationAllow}, nil
+}
+
+// normalizeModerationText lowercases text and collapses anything that is not a letter
+// or digit into single spaces
+func normalizeModerationText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,131 @@
+// ===============================
+// internal/services/counts.go - Denormalized Count Reconciliation
+// ===============================
+
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"weibaobe/internal/database"
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Only rows whose stored count differs from the source table are written, so a clean
+// run touches nothing and the RETURNING lists are exactly the corrections made
+const reconcileVideoCountsQuery = `
+	WITH actual AS (
+		SELECT v.id,
+		       (SELECT COUNT(*) FROM video_likes vl WHERE vl.video_id = v.id) AS likes,
+		       (SELECT COUNT(*) FROM comments cm WHERE cm.video_id = v.id) AS comments
+		FROM videos v
+		WHERE v.is_active = true
+	)
+	UPDATE videos v
+	SET likes_count = a.likes, comments_count = a.comments
+	FROM actual a
+	WHERE v.id = a.id AND (v.likes_count <> a.likes OR v.comments_count <> a.comments)
+	RETURNING v.id::text`
+
+// videos_count follows the insert/delete trigger, so it counts inactive videos too. Follow
+// counts use the same rule as deactivation (see excludeInactiveFollows), so the two agree.
+func reconcileUserCountsQuery() string {
+	return `
+	WITH actual AS (
+		SELECT u.uid,
+		       ` + followersCountSQL("u.uid") + ` AS followers,
+		       ` + followingCountSQL("u.uid") + ` AS following,
+		       (SELECT COUNT(*) FROM videos v WHERE v.user_id = u.uid) AS videos
+		FROM users u
+	)
+	UPDATE users u
+	SET followers_count = a.followers, following_count = a.following, videos_count = a.videos
+	FROM actual a
+	WHERE u.uid = a.uid
+	  AND (u.followers_count <> a.followers OR u.following_count <> a.following OR u.videos_count <> a.videos)
+	RETURNING u.uid`
+}
+
+// ReconcileCounts recomputes videos.likes_count/comments_count and users.followers_count/
+// following_count/videos_count from their source tables, fixing any drift left by
+// missed triggers, and logs what it corrected
+func (s *VideoService) ReconcileCounts(ctx context.Context) (*models.CountReconciliation, error) {
+	result := &models.CountReconciliation{}
+
+	err := database.WithAdminStatementTimeout(ctx, s.db, func(tx *sqlx.Tx) error {
+		var videoIDs, userIDs []string
+		if err := tx.SelectContext(ctx, &videoIDs, reconcileVideoCountsQuery); err != nil {
+			return err
+		}
+		if err := tx.SelectContext(ctx, &userIDs, reconcileUserCountsQuery()); err != nil {
+			return err
+		}
+		result.VideosCorrected = len(videoIDs)
+		result.UsersCorrected = len(userIDs)
+
+		if len(videoIDs) > 0 {
+			log.Printf("🔢 Corrected like/comment counts on %d videos: %v", len(videoIDs), sampleIDs(videoIDs))
+		}
+		if len(userIDs) > 0 {
+			log.Printf("🔢 Corrected follower/following/video counts on %d users: %v", len(userIDs), sampleIDs(userIDs))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// sampleIDs keeps correction logs bounded when a large drift is repaired
+func sampleIDs(ids []string) []string {
+	const maxLogged = 20
+	if len(ids) > maxLogged {
+		return ids[:maxLogged]
+	}
+	return ids
+}
+
+// StartCountReconciler runs ReconcileCounts every interval. interval <= 0 disables the
+// job; the admin endpoint still works. The returned function stops the job.
+func (s *VideoService) StartCountReconciler(interval time.Duration) func() {
+	if interval <= 0 {
+		log.Println("🔢 Scheduled count reconciliation disabled")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func() {
+		if _, err := s.ReconcileCounts(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️ Count reconciliation failed: %v", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// No run at startup: the first pass scans every video and user
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("🔢 Count reconciliation scheduled every %s", interval)
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
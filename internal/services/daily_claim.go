@@ -0,0 +1,180 @@
+// ===============================
+// internal/services/daily_claim.go - Daily Coin Claims & Streaks
+// ===============================
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const claimDateLayout = "2006-01-02"
+
+// dailyClaimCoins is what a claim on day streak of a run pays: the base amount plus the
+// streak bonus for each consecutive day after the first, capped at DailyStreakCap days
+func (s *RewardService) dailyClaimCoins(streak int) int {
+	bonusDays := streak - 1
+	if s.daily.DailyStreakCap > 0 && bonusDays > s.daily.DailyStreakCap-1 {
+		bonusDays = s.daily.DailyStreakCap - 1
+	}
+	if bonusDays < 0 || s.daily.DailyStreakBonus <= 0 {
+		bonusDays = 0
+	}
+	return s.daily.DailyCoins + bonusDays*s.daily.DailyStreakBonus
+}
+
+// claimDay returns today's date in the daily claim timezone and when tomorrow starts
+func (s *RewardService) claimDay(now time.Time) (time.Time, time.Time) {
+	local := now.In(s.dailyLocation)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.dailyLocation)
+	return today, today.AddDate(0, 0, 1)
+}
+
+// lastDailyClaim returns the user's most recent claim, or nil when they have never claimed
+func (s *RewardService) lastDailyClaim(ctx context.Context, q sqlx.QueryerContext, userID string) (*models.DailyClaim, error) {
+	var claim models.DailyClaim
+	err := sqlx.GetContext(ctx, q, &claim, `
+		SELECT id, user_id, claim_date::text AS claim_date, streak, coins, transaction_id, created_at
+		FROM daily_claims
+		WHERE user_id = $1
+		ORDER BY claim_date DESC
+		LIMIT 1`, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// dailyClaimStatus works out the streak and next claim from the user's last claim
+func (s *RewardService) dailyClaimStatus(last *models.DailyClaim, now time.Time) models.DailyClaimStatus {
+	today, tomorrow := s.claimDay(now)
+	todayKey := today.Format(claimDateLayout)
+	yesterdayKey := today.AddDate(0, 0, -1).Format(claimDateLayout)
+
+	status := models.DailyClaimStatus{
+		CanClaim:       true,
+		NextClaimAt:    now,
+		NextClaimCoins: s.dailyClaimCoins(1),
+	}
+	if last == nil {
+		return status
+	}
+
+	lastDate := last.ClaimDate
+	status.LastClaimDate = &lastDate
+
+	switch last.ClaimDate {
+	case todayKey:
+		status.Streak = last.Streak
+		status.ClaimedToday = true
+		status.CanClaim = false
+		status.NextClaimAt = tomorrow
+		status.NextClaimCoins = s.dailyClaimCoins(last.Streak + 1)
+	case yesterdayKey:
+		status.Streak = last.Streak
+		status.NextClaimCoins = s.dailyClaimCoins(last.Streak + 1)
+	}
+	return status
+}
+
+// GetDailyClaimStatus reports the user's current streak and when they can next claim
+func (s *RewardService) GetDailyClaimStatus(ctx context.Context, userID string) (*models.DailyClaimStatus, error) {
+	if s.daily.DailyCoins <= 0 {
+		return nil, errors.New("daily_reward_disabled")
+	}
+
+	last, err := s.lastDailyClaim(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := s.dailyClaimStatus(last, time.Now())
+	return &status, nil
+}
+
+// ClaimDaily credits the day's coins through the wallet ledger and extends the streak
+// when yesterday was claimed too. It returns "already_claimed" for a second claim on
+// the same calendar day; the unique (user_id, claim_date) row makes this race-safe.
+func (s *RewardService) ClaimDaily(ctx context.Context, userID string) (*models.DailyClaimResult, error) {
+	if s.daily.DailyCoins <= 0 {
+		return nil, errors.New("daily_reward_disabled")
+	}
+
+	// Make sure the wallet exists before crediting it
+	if _, err := s.walletService.GetWallet(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	last, err := s.lastDailyClaim(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := s.dailyClaimStatus(last, now)
+	if before.ClaimedToday {
+		return nil, errors.New("already_claimed")
+	}
+
+	today, _ := s.claimDay(now)
+	streak := before.Streak + 1
+	coins := s.dailyClaimCoins(streak)
+
+	var claim models.DailyClaim
+	err = tx.GetContext(ctx, &claim, `
+		INSERT INTO daily_claims (user_id, claim_date, streak, coins)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, claim_date) DO NOTHING
+		RETURNING id, user_id, claim_date::text AS claim_date, streak, coins, transaction_id, created_at`,
+		userID, today.Format(claimDateLayout), streak, coins)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("already_claimed")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	walletTx, err := s.walletService.Credit(ctx, tx, userID, coins, "daily_reward", models.WalletLedgerMeta{
+		Description: fmt.Sprintf("Daily reward (day %d streak)", streak),
+		ReferenceID: &claim.ID,
+		Metadata:    models.MetadataMap{"claimDate": claim.ClaimDate, "streak": streak},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE daily_claims SET transaction_id = $1 WHERE id = $2`, walletTx.TransactionID, claim.ID)
+	if err != nil {
+		return nil, err
+	}
+	claim.TransactionID = &walletTx.TransactionID
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📅 Granted %d coins to user %s for daily claim (streak %d)", coins, userID, streak)
+	return &models.DailyClaimResult{
+		Claim:        claim,
+		BalanceAfter: walletTx.BalanceAfter,
+		Status:       s.dailyClaimStatus(&claim, now),
+	}, nil
+}
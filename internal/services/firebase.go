@@ -51,6 +51,16 @@ func (fs *FirebaseService) VerifyIDToken(ctx context.Context, idToken string) (*
 	return fs.authClient.VerifyIDToken(ctx, idToken)
 }
 
+// Ping makes a lightweight Admin SDK call to confirm Firebase Auth is reachable with our
+// credentials. A user-not-found answer still proves the round trip worked.
+func (fs *FirebaseService) Ping(ctx context.Context) error {
+	_, err := fs.authClient.GetUser(ctx, "health-check-probe")
+	if err == nil || auth.IsUserNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 // GetUser gets a Firebase user by UID
 func (fs *FirebaseService) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
 	return fs.authClient.GetUser(ctx, uid)
@@ -0,0 +1,71 @@
+// ===============================
+// internal/services/follow_counts.go - Follow Counts Across Deactivation
+// ===============================
+
+package services
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// excludeInactiveFollows makes followers_count/following_count count only active
+// accounts, so deactivating a user drops them from everyone's counts and reactivating
+// restores them. Follower and following lists hide inactive users either way.
+var excludeInactiveFollows = true
+
+// SetExcludeInactiveFollows chooses whether follow counts skip deactivated accounts
+func SetExcludeInactiveFollows(enabled bool) {
+	excludeInactiveFollows = enabled
+}
+
+// countedFollowsSQL returns a COUNT(*) over user_follows rows where column equals
+// userExpr, restricted to active counterparts (the other side of the follow) when
+// excludeInactiveFollows is set
+func countedFollowsSQL(column, counterpart, userExpr string) string {
+	query := `(SELECT COUNT(*) FROM user_follows f`
+	if excludeInactiveFollows {
+		query += ` JOIN users cu ON cu.uid = f.` + counterpart + ` AND cu.is_active = true`
+	}
+	return query + ` WHERE f.` + column + ` = ` + userExpr + `)`
+}
+
+// followersCountSQL and followingCountSQL compute the stored counts for the user in userExpr
+func followersCountSQL(userExpr string) string {
+	return countedFollowsSQL("following_id", "follower_id", userExpr)
+}
+
+func followingCountSQL(userExpr string) string {
+	return countedFollowsSQL("follower_id", "following_id", userExpr)
+}
+
+// syncNeighborFollowCounts recomputes the follower count of everyone userID follows and
+// the following count of everyone who follows userID. Call it in the transaction that
+// changes userID's is_active. It does nothing when inactive accounts are still counted.
+func syncNeighborFollowCounts(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	if !excludeInactiveFollows {
+		return nil, nil
+	}
+
+	var changed []string
+	err := tx.SelectContext(ctx, &changed, `
+		WITH neighbors AS (
+			SELECT following_id AS uid FROM user_follows WHERE follower_id = $1
+			UNION
+			SELECT follower_id FROM user_follows WHERE following_id = $1
+		),
+		actual AS (
+			SELECT n.uid,
+			       `+followersCountSQL("n.uid")+` AS followers,
+			       `+followingCountSQL("n.uid")+` AS following
+			FROM neighbors n
+		)
+		UPDATE users u
+		SET followers_count = a.followers, following_count = a.following
+		FROM actual a
+		WHERE u.uid = a.uid
+		  AND (u.followers_count <> a.followers OR u.following_count <> a.following)
+		RETURNING u.uid`, userID)
+	return changed, err
+}
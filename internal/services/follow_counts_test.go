@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// openTestDB connects to TEST_DATABASE_URL and brings its schema up to date, skipping
+// the test when no database is configured:
+//
+//	TEST_DATABASE_URL=postgres://... go test ./internal/services/
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := database.Connect(databaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createTestUser inserts an active user and removes it, with everything that references
+// it, when the test ends
+func createTestUser(t *testing.T, db *sqlx.DB, name string) string {
+	t.Helper()
+
+	uid := "test-" + uuid.New().String()
+	_, err := db.Exec(`
+		INSERT INTO users (uid, name, phone_number, role)
+		VALUES ($1, $2, $3, 'host')`,
+		uid, name, "+2547"+uid[len(uid)-8:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM user_follows WHERE follower_id = $1 OR following_id = $1`, uid)
+		db.Exec(`DELETE FROM video_purchases WHERE user_id = $1`, uid)
+		db.Exec(`DELETE FROM video_likes WHERE user_id = $1`, uid)
+		db.Exec(`DELETE FROM videos WHERE user_id = $1`, uid)
+		db.Exec(`DELETE FROM users WHERE uid = $1`, uid)
+	})
+	return uid
+}
+
+func createTestVideo(t *testing.T, db *sqlx.DB, userID string, price float64) string {
+	t.Helper()
+
+	videoID := uuid.New().String()
+	_, err := db.Exec(`
+		INSERT INTO videos (id, user_id, user_name, video_url, price)
+		VALUES ($1, $2, 'Test', 'https://example.com/video.mp4', $3)`,
+		videoID, userID, price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return videoID
+}
+
+func TestFollowCountsAndFeedAcrossDeactivation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	defer SetExcludeInactiveFollows(excludeInactiveFollows)
+	SetExcludeInactiveFollows(true)
+
+	users := NewUserService(db, nil)
+	videos := NewVideoService(db, nil, nil, nil, config.DuplicateUploadConfig{}, nil, nil)
+
+	creator := createTestUser(t, db, "Creator")
+	follower := createTestUser(t, db, "Follower")
+	otherFollower := createTestUser(t, db, "Other Follower")
+	videoID := createTestVideo(t, db, creator, 0)
+
+	for _, uid := range []string{follower, otherFollower} {
+		if _, err := videos.FollowUser(ctx, uid, creator); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts := func(uid string) (followers, following int) {
+		t.Helper()
+		err := db.QueryRow(`SELECT followers_count, following_count FROM users WHERE uid = $1`, uid).
+			Scan(&followers, &following)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return followers, following
+	}
+	expectCounts := func(step, uid string, wantFollowers, wantFollowing int) {
+		t.Helper()
+		if followers, following := counts(uid); followers != wantFollowers || following != wantFollowing {
+			t.Errorf("%s: counts of %s = %d followers, %d following; want %d, %d",
+				step, uid, followers, following, wantFollowers, wantFollowing)
+		}
+	}
+	expectInFeed := func(step string, want bool) {
+		t.Helper()
+		feed, _, err := videos.GetFollowingVideoFeed(ctx, follower, 50, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, video := range feed {
+			if video.ID == videoID {
+				found = true
+			}
+		}
+		if found != want {
+			t.Errorf("%s: creator's video in follower's feed = %v, want %v", step, found, want)
+		}
+	}
+
+	expectCounts("followed", creator, 2, 0)
+	expectCounts("followed", follower, 0, 1)
+	expectInFeed("followed", true)
+
+	// The creator switching off drops out of their followers' following counts and feeds
+	if err := users.DeactivateAccount(ctx, creator); err != nil {
+		t.Fatal(err)
+	}
+	expectCounts("creator deactivated", follower, 0, 0)
+	expectCounts("creator deactivated", otherFollower, 0, 0)
+	expectInFeed("creator deactivated", false)
+
+	if _, err := users.ReactivateAccount(ctx, creator); err != nil {
+		t.Fatal(err)
+	}
+	expectCounts("creator reactivated", creator, 2, 0)
+	expectCounts("creator reactivated", follower, 0, 1)
+	expectCounts("creator reactivated", otherFollower, 0, 1)
+	expectInFeed("creator reactivated", true)
+
+	// A follower switching off drops out of the creator's follower count only
+	if err := users.DeactivateAccount(ctx, follower); err != nil {
+		t.Fatal(err)
+	}
+	expectCounts("follower deactivated", creator, 1, 0)
+
+	if _, err := users.ReactivateAccount(ctx, follower); err != nil {
+		t.Fatal(err)
+	}
+	expectCounts("follower reactivated", creator, 2, 0)
+	expectCounts("follower reactivated", follower, 0, 1)
+}
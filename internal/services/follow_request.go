@@ -0,0 +1,194 @@
+// ===============================
+// internal/services/follow_request.go - Private Accounts & Follow Requests
+// ===============================
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"weibaobe/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// privateAuthorHidden returns an EXISTS expression that is true when the author column is a
+// private account the viewer (bound at argument viewerArg) may not see: the viewer is not
+// the author and does not follow them. With viewerArg 0 (signed out) every private
+// account is hidden.
+func privateAuthorHidden(authorColumn string, viewerArg int) string {
+	if viewerArg == 0 {
+		return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM users pu
+			WHERE pu.uid = %s AND pu.is_private = true)`, authorColumn)
+	}
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM users pu
+			WHERE pu.uid = %[1]s AND pu.is_private = true AND pu.uid <> $%[2]d
+			  AND NOT EXISTS (
+				SELECT 1 FROM user_follows pf
+				WHERE pf.follower_id = $%[2]d AND pf.following_id = pu.uid))`, authorColumn, viewerArg)
+}
+
+// requestFollow records a pending request to follow a private account. It returns
+// "already_requested" when one is already open.
+func (s *VideoService) requestFollow(ctx context.Context, requesterID, targetID string) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO follow_requests (requester_id, target_id)
+		VALUES ($1, $2)
+		ON CONFLICT (requester_id, target_id) WHERE status = 'pending' DO NOTHING`,
+		requesterID, targetID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("already_requested")
+	}
+	return nil
+}
+
+// cancelFollowRequest withdraws requesterID's pending request to targetID, reporting
+// whether there was one
+func (s *VideoService) cancelFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM follow_requests
+		WHERE requester_id = $1 AND target_id = $2 AND status = 'pending'`,
+		requesterID, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// GetFollowRequests returns the pending requests to follow targetID, newest first, and
+// the total number pending
+func (s *VideoService) GetFollowRequests(ctx context.Context, targetID string, limit, offset int) ([]models.UserFollowRequest, int, error) {
+	var rows []struct {
+		models.UserFollowRequest
+		TotalCount int `db:"total_count"`
+	}
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT fr.id, fr.requester_id, fr.target_id, fr.status, fr.created_at, fr.responded_at,
+		       u.name AS requester_name, u.profile_image AS requester_image,
+		       u.is_verified AS requester_is_verified,
+		       COUNT(*) OVER() as total_count
+		FROM follow_requests fr
+		JOIN users u ON u.uid = fr.requester_id AND u.is_active = true
+		WHERE fr.target_id = $1 AND fr.status = 'pending'
+		ORDER BY fr.created_at DESC
+		LIMIT $2 OFFSET $3`, targetID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	requests := make([]models.UserFollowRequest, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		requests = append(requests, row.UserFollowRequest)
+		total = row.TotalCount
+	}
+	return requests, total, nil
+}
+
+// ApproveFollowRequest accepts a pending request addressed to targetID and creates the
+// follow. It returns "follow_request_not_found" when there is no such pending request.
+func (s *VideoService) ApproveFollowRequest(ctx context.Context, targetID, requestID string) (*models.UserFollowRequest, error) {
+	return s.respondToFollowRequest(ctx, targetID, requestID, models.FollowRequestApproved)
+}
+
+// RejectFollowRequest declines a pending request addressed to targetID. The requester
+// may ask again later.
+func (s *VideoService) RejectFollowRequest(ctx context.Context, targetID, requestID string) (*models.UserFollowRequest, error) {
+	return s.respondToFollowRequest(ctx, targetID, requestID, models.FollowRequestRejected)
+}
+
+func (s *VideoService) respondToFollowRequest(ctx context.Context, targetID, requestID, status string) (*models.UserFollowRequest, error) {
+	if _, err := uuid.Parse(requestID); err != nil {
+		return nil, errors.New("follow_request_not_found")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var request models.UserFollowRequest
+	err = tx.GetContext(ctx, &request, `
+		UPDATE follow_requests
+		SET status = $3, responded_at = NOW()
+		WHERE id = $1 AND target_id = $2 AND status = 'pending'
+		RETURNING id, requester_id, target_id, status, created_at, responded_at`,
+		requestID, targetID, status)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("follow_request_not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.FollowRequestApproved {
+		// Follow counts are kept in sync by the user_follows trigger
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_follows (follower_id, following_id)
+			VALUES ($1, $2)
+			ON CONFLICT (follower_id, following_id) DO NOTHING`,
+			request.RequesterID, targetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ApprovePendingFollowRequests turns every pending request to userID into a follow, for
+// when the account stops being private. It returns the requesters that now follow userID.
+func (s *UserService) ApprovePendingFollowRequests(ctx context.Context, userID string) ([]string, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var requesterIDs []string
+	err = tx.SelectContext(ctx, &requesterIDs, `
+		UPDATE follow_requests
+		SET status = 'approved', responded_at = NOW()
+		WHERE target_id = $1 AND status = 'pending'
+		RETURNING requester_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requesterIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_follows (follower_id, following_id)
+		SELECT unnest($1::text[]), $2
+		ON CONFLICT (follower_id, following_id) DO NOTHING`,
+		models.StringSlice(requesterIDs), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.profileCache.Invalidate(append(requesterIDs, userID)...)
+	return requesterIDs, nil
+}
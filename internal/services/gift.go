@@ -8,6 +8,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -19,14 +20,16 @@ import (
 )
 
 type GiftService struct {
-	db            *sqlx.DB
-	walletService *WalletService
+	db             *sqlx.DB
+	walletService  *WalletService
+	webhookService *WebhookService
 }
 
-func NewGiftService(db *sqlx.DB, walletService *WalletService) *GiftService {
+func NewGiftService(db *sqlx.DB, walletService *WalletService, webhookService *WebhookService) *GiftService {
 	return &GiftService{
-		db:            db,
-		walletService: walletService,
+		db:             db,
+		walletService:  walletService,
+		webhookService: webhookService,
 	}
 }
 
@@ -53,7 +56,7 @@ func (s *GiftService) SendGift(
 
 	// 1. Validate sender and recipient exist and are different
 	if senderID == request.RecipientID {
-		return nil, fmt.Errorf("cannot send gift to yourself")
+		return nil, errors.New("cannot_gift_self")
 	}
 
 	// 2. Get sender information
@@ -67,7 +70,7 @@ func (s *GiftService) SendGift(
 		senderID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("sender not found or inactive")
+			return nil, errors.New("sender_not_found")
 		}
 		return nil, fmt.Errorf("failed to get sender: %w", err)
 	}
@@ -83,81 +86,76 @@ func (s *GiftService) SendGift(
 		request.RecipientID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("recipient not found or inactive")
+			return nil, errors.New("recipient_not_found")
 		}
 		return nil, fmt.Errorf("failed to get recipient: %w", err)
 	}
 
-	// 4. Calculate commission
-	recipientAmount, platformCommission := models.CalculateCommission(giftPrice, models.DefaultCommissionRate)
-
-	// 5. Get sender's wallet
-	var senderWallet struct {
-		WalletID     string `db:"wallet_id"`
-		CoinsBalance int    `db:"coins_balance"`
-	}
-	err = tx.GetContext(ctx, &senderWallet,
-		"SELECT wallet_id, coins_balance FROM wallets WHERE user_id = $1",
-		senderID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("sender wallet not found")
+	// Gifts sent on a video must go to that video's creator
+	if request.VideoID != nil {
+		var videoOwnedByRecipient bool
+		err = tx.GetContext(ctx, &videoOwnedByRecipient,
+			"SELECT EXISTS(SELECT 1 FROM videos WHERE id::text = $1 AND user_id = $2 AND is_active = true)",
+			*request.VideoID, recipient.UID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check gifted video: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get sender wallet: %w", err)
-	}
-
-	// 6. Check if sender has sufficient balance
-	if senderWallet.CoinsBalance < giftPrice {
-		return nil, fmt.Errorf("insufficient balance: have %d coins, need %d coins",
-			senderWallet.CoinsBalance, giftPrice)
-	}
-
-	// 7. Get recipient's wallet
-	var recipientWallet struct {
-		WalletID     string `db:"wallet_id"`
-		CoinsBalance int    `db:"coins_balance"`
-	}
-	err = tx.GetContext(ctx, &recipientWallet,
-		"SELECT wallet_id, coins_balance FROM wallets WHERE user_id = $1",
-		request.RecipientID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("recipient wallet not found")
+		if !videoOwnedByRecipient {
+			return nil, errors.New("video_not_found")
 		}
-		return nil, fmt.Errorf("failed to get recipient wallet: %w", err)
 	}
 
-	// 8. Deduct coins from sender's wallet
-	senderBalanceBefore := senderWallet.CoinsBalance
-	senderBalanceAfter := senderBalanceBefore - giftPrice
+	// 4. Calculate commission
+	recipientAmount, platformCommission := models.CalculateCommission(giftPrice, models.DefaultCommissionRate)
+
+	// 5. Debit sender and credit recipient (after commission). Debit refuses to
+	// overdraw, so concurrent gifts can't push the sender's balance negative.
+	transactionID := uuid.New().String()
 
-	_, err = tx.ExecContext(ctx, `
-		UPDATE wallets 
-		SET coins_balance = $1, updated_at = CURRENT_TIMESTAMP 
-		WHERE user_id = $2
-	`, senderBalanceAfter, senderID)
+	senderTx, err := s.walletService.Debit(ctx, tx, senderID, giftPrice, "gift_sent", models.WalletLedgerMeta{
+		Description: fmt.Sprintf("Sent %s to %s", giftName, recipient.Name),
+		ReferenceID: &transactionID,
+		Metadata: models.MetadataMap{
+			"gift_id":        request.GiftID,
+			"gift_name":      giftName,
+			"gift_emoji":     giftEmoji,
+			"recipient_id":   recipient.UID,
+			"recipient_name": recipient.Name,
+		},
+	})
 	if err != nil {
+		switch err.Error() {
+		case "insufficient_balance":
+			return nil, err
+		case "wallet_not_found":
+			return nil, errors.New("sender_wallet_not_found")
+		}
 		return nil, fmt.Errorf("failed to update sender wallet: %w", err)
 	}
 
-	// 9. Add coins to recipient's wallet (after commission)
-	recipientBalanceBefore := recipientWallet.CoinsBalance
-	recipientBalanceAfter := recipientBalanceBefore + recipientAmount
-
-	_, err = tx.ExecContext(ctx, `
-		UPDATE wallets 
-		SET coins_balance = $1, updated_at = CURRENT_TIMESTAMP 
-		WHERE user_id = $2
-	`, recipientBalanceAfter, request.RecipientID)
+	recipientTx, err := s.walletService.Credit(ctx, tx, request.RecipientID, recipientAmount, "gift_received", models.WalletLedgerMeta{
+		Description: fmt.Sprintf("Received %s from %s", giftName, sender.Name),
+		ReferenceID: &transactionID,
+		Metadata: models.MetadataMap{
+			"gift_id":     request.GiftID,
+			"gift_name":   giftName,
+			"gift_emoji":  giftEmoji,
+			"sender_id":   sender.UID,
+			"sender_name": sender.Name,
+			"commission":  platformCommission,
+		},
+	})
 	if err != nil {
+		if err.Error() == "wallet_not_found" {
+			return nil, errors.New("recipient_wallet_not_found")
+		}
 		return nil, fmt.Errorf("failed to update recipient wallet: %w", err)
 	}
 
-	// 10. Create gift transaction record
-	transactionID := uuid.New().String()
-	senderTxID := uuid.New().String()
-	recipientTxID := uuid.New().String()
+	senderBalanceBefore, senderBalanceAfter := senderTx.BalanceBefore, senderTx.BalanceAfter
+	recipientBalanceBefore, recipientBalanceAfter := recipientTx.BalanceBefore, recipientTx.BalanceAfter
 
+	// 6. Create gift transaction record
 	metadata := models.GiftMetadataMap{
 		"gift_rarity": string(giftRarity),
 	}
@@ -177,8 +175,8 @@ func (s *GiftService) SendGift(
 			gift_price, sender_paid, recipient_received, platform_commission,
 			sender_balance_before, sender_balance_after,
 			recipient_balance_before, recipient_balance_after,
-			status, message, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+			status, message, metadata, video_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 		RETURNING created_at
 	`, transactionID, sender.UID, sender.Name, sender.PhoneNumber,
 		recipient.UID, recipient.Name, recipient.PhoneNumber,
@@ -186,13 +184,13 @@ func (s *GiftService) SendGift(
 		giftPrice, giftPrice, recipientAmount, platformCommission,
 		senderBalanceBefore, senderBalanceAfter,
 		recipientBalanceBefore, recipientBalanceAfter,
-		"completed", request.Message, metadata,
+		"completed", request.Message, metadata, request.VideoID,
 	).Scan(&createdAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gift transaction: %w", err)
 	}
 
-	// 11. Create platform commission record
+	// 7. Create platform commission record
 	commissionID := uuid.New().String()
 	commissionMetadata := models.GiftMetadataMap{
 		"gift_rarity":    string(giftRarity),
@@ -211,52 +209,7 @@ func (s *GiftService) SendGift(
 		return nil, fmt.Errorf("failed to create commission record: %w", err)
 	}
 
-	// 12. Create wallet transaction for sender (debit)
-	walletMetadata := models.GiftMetadataMap{
-		"gift_name":      giftName,
-		"gift_emoji":     giftEmoji,
-		"recipient_name": recipient.Name,
-	}
-	walletMetadataJSON, _ := json.Marshal(walletMetadata)
-
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO wallet_transactions (
-			transaction_id, wallet_id, user_id, user_phone_number, user_name,
-			type, coin_amount, balance_before, balance_after,
-			description, reference_id, gift_id, recipient_id, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-	`, senderTxID, senderWallet.WalletID, sender.UID, sender.PhoneNumber, sender.Name,
-		"gift_sent", -giftPrice, senderBalanceBefore, senderBalanceAfter,
-		fmt.Sprintf("Sent %s to %s", giftName, recipient.Name),
-		transactionID, request.GiftID, recipient.UID, walletMetadataJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create sender wallet transaction: %w", err)
-	}
-
-	// 13. Create wallet transaction for recipient (credit)
-	recipientWalletMetadata := models.GiftMetadataMap{
-		"gift_name":   giftName,
-		"gift_emoji":  giftEmoji,
-		"sender_name": sender.Name,
-		"commission":  platformCommission,
-	}
-	recipientWalletMetadataJSON, _ := json.Marshal(recipientWalletMetadata)
-
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO wallet_transactions (
-			transaction_id, wallet_id, user_id, user_phone_number, user_name,
-			type, coin_amount, balance_before, balance_after,
-			description, reference_id, gift_id, sender_id, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-	`, recipientTxID, recipientWallet.WalletID, recipient.UID, recipient.PhoneNumber, recipient.Name,
-		"gift_received", recipientAmount, recipientBalanceBefore, recipientBalanceAfter,
-		fmt.Sprintf("Received %s from %s", giftName, sender.Name),
-		transactionID, request.GiftID, sender.UID, recipientWalletMetadataJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create recipient wallet transaction: %w", err)
-	}
-
-	// 14. Update user gift statistics for sender
+	// 8. Update user gift statistics for sender
 	_, err = tx.ExecContext(ctx, `
 		UPDATE users 
 		SET 
@@ -268,7 +221,7 @@ func (s *GiftService) SendGift(
 		return nil, fmt.Errorf("failed to update sender statistics: %w", err)
 	}
 
-	// 15. Update user gift statistics for recipient
+	// 9. Update user gift statistics for recipient
 	_, err = tx.ExecContext(ctx, `
 		UPDATE users 
 		SET 
@@ -280,7 +233,24 @@ func (s *GiftService) SendGift(
 		return nil, fmt.Errorf("failed to update recipient statistics: %w", err)
 	}
 
-	// 16. Commit the transaction
+	// 10. Queue the gift.sent webhook with the gift itself
+	err = s.webhookService.Enqueue(ctx, tx, models.WebhookEventGiftSent, models.GiftSentWebhookData{
+		TransactionID:      transactionID,
+		SenderID:           sender.UID,
+		RecipientID:        recipient.UID,
+		VideoID:            request.VideoID,
+		GiftID:             request.GiftID,
+		GiftName:           giftName,
+		GiftPrice:          giftPrice,
+		RecipientAmount:    recipientAmount,
+		PlatformCommission: platformCommission,
+		CreatedAt:          createdAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 11. Commit the transaction
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -288,7 +258,7 @@ func (s *GiftService) SendGift(
 	log.Printf("✅ Gift sent: %s -> %s | %s (%d coins) | Recipient: %d, Commission: %d",
 		sender.Name, recipient.Name, giftName, giftPrice, recipientAmount, platformCommission)
 
-	// 17. Build the gift transaction object for response
+	// 12. Build the gift transaction object for response
 	giftTransaction := &models.GiftTransaction{
 		ID:                     transactionID,
 		SenderID:               sender.UID,
@@ -305,15 +275,15 @@ func (s *GiftService) SendGift(
 		RecipientAmount:        recipientAmount,
 		PlatformCommission:     platformCommission,
 		CommissionRate:         models.DefaultCommissionRate,
-		SenderTransactionID:    &senderTxID,
-		RecipientTransactionID: &recipientTxID,
+		SenderTransactionID:    &senderTx.TransactionID,
+		RecipientTransactionID: &recipientTx.TransactionID,
 		Message:                request.Message,
 		Context:                request.Context,
 		Metadata:               metadata,
 		CreatedAt:              createdAt,
 	}
 
-	// 18. Build response
+	// 13. Build response
 	response := &models.SendGiftResponse{
 		Success:             true,
 		GiftTransaction:     giftTransaction,
@@ -342,7 +312,7 @@ func (s *GiftService) GetUserGiftHistory(ctx context.Context, userID string, lim
 			gift_price, recipient_received as recipient_amount, 
 			platform_commission, commission_rate,
 			sender_transaction_id, recipient_transaction_id,
-			message, context, metadata, created_at
+			message, context, video_id, metadata, created_at
 		FROM gift_transactions
 		WHERE sender_id = $1 OR recipient_id = $1
 		ORDER BY created_at DESC
@@ -462,7 +432,7 @@ func (s *GiftService) GetGiftTransaction(ctx context.Context, transactionID stri
 			gift_price, recipient_received as recipient_amount, 
 			platform_commission, commission_rate,
 			sender_transaction_id, recipient_transaction_id,
-			message, context, metadata, created_at
+			message, context, video_id, metadata, created_at
 		FROM gift_transactions
 		WHERE id = $1
 	`
@@ -470,7 +440,7 @@ func (s *GiftService) GetGiftTransaction(ctx context.Context, transactionID stri
 	err := s.db.GetContext(ctx, &transaction, query, transactionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("transaction not found")
+			return nil, errors.New("transaction_not_found")
 		}
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -478,6 +448,46 @@ func (s *GiftService) GetGiftTransaction(ctx context.Context, transactionID stri
 	return &transaction, nil
 }
 
+// GetSenderBalance returns the user's coin balance for affordability hints before sending
+func (s *GiftService) GetSenderBalance(ctx context.Context, userID string) (int, error) {
+	return s.walletService.GetBalance(ctx, userID)
+}
+
+// GetTopGiftedVideos ranks a creator's videos by gift coins received since the given time,
+// with totals across all of the creator's video gifts in the window
+func (s *GiftService) GetTopGiftedVideos(ctx context.Context, creatorID string, since time.Time, limit int) (*models.TopGiftedVideosReport, error) {
+	report := &models.TopGiftedVideosReport{Since: since, Videos: []models.TopGiftedVideo{}}
+
+	err := s.db.GetContext(ctx, report, `
+		SELECT COUNT(*) AS total_gifts,
+		       COALESCE(SUM(recipient_received), 0) AS total_coins_received,
+		       COALESCE(SUM(gift_price), 0) AS total_gift_value
+		FROM gift_transactions
+		WHERE recipient_id = $1 AND video_id IS NOT NULL AND created_at >= $2`,
+		creatorID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift totals: %w", err)
+	}
+
+	err = s.db.SelectContext(ctx, &report.Videos, `
+		SELECT v.id AS video_id, v.caption, v.thumbnail_url, v.created_at,
+		       COUNT(*) AS gift_count,
+		       SUM(gt.recipient_received) AS coins_received,
+		       SUM(gt.gift_price) AS gift_value
+		FROM gift_transactions gt
+		JOIN videos v ON v.id = gt.video_id
+		WHERE gt.recipient_id = $1 AND gt.created_at >= $2
+		GROUP BY v.id, v.caption, v.thumbnail_url, v.created_at
+		ORDER BY coins_received DESC, gift_count DESC
+		LIMIT $3`,
+		creatorID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top gifted videos: %w", err)
+	}
+
+	return report, nil
+}
+
 // ===============================
 // Leaderboards
 // ===============================
@@ -552,6 +562,44 @@ func (s *GiftService) GetTopGiftReceivers(ctx context.Context, limit int) ([]mod
 // Platform Analytics (Admin)
 // ===============================
 
+// GetPlatformRevenue returns commission totals and a zero-filled daily series for
+// gifts processed in [from, to)
+func (s *GiftService) GetPlatformRevenue(ctx context.Context, from, to time.Time) (*models.PlatformRevenueReport, error) {
+	report := &models.PlatformRevenueReport{From: from, To: to}
+
+	err := s.db.GetContext(ctx, report, `
+		SELECT 
+			COALESCE(SUM(commission_amount), 0) as total_commissions,
+			COUNT(*) as total_gifts_facilitated,
+			COALESCE(SUM(original_gift_price), 0) as total_gift_volume,
+			COALESCE(AVG(commission_amount), 0) as average_commission
+		FROM platform_commissions
+		WHERE created_at >= $1 AND created_at < $2
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get platform revenue: %w", err)
+	}
+
+	report.Daily = []models.DailyPlatformRevenue{}
+	err = s.db.SelectContext(ctx, &report.Daily, `
+		SELECT 
+			TO_CHAR(d.day, 'YYYY-MM-DD') as day,
+			COALESCE(SUM(pc.commission_amount), 0) as commissions,
+			COUNT(pc.created_at) as gifts
+		FROM generate_series(DATE_TRUNC('day', $1::timestamptz), $2::timestamptz - INTERVAL '1 microsecond', INTERVAL '1 day') AS d(day)
+		LEFT JOIN platform_commissions pc
+			ON pc.created_at >= d.day AND pc.created_at < d.day + INTERVAL '1 day'
+			AND pc.created_at >= $1 AND pc.created_at < $2
+		GROUP BY d.day
+		ORDER BY d.day
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily platform revenue: %w", err)
+	}
+
+	return report, nil
+}
+
 // GetPlatformCommissionSummary retrieves platform commission statistics
 func (s *GiftService) GetPlatformCommissionSummary(ctx context.Context) (*models.PlatformCommissionSummary, error) {
 	summary := &models.PlatformCommissionSummary{}
@@ -0,0 +1,127 @@
+// ===============================
+// internal/services/hidden_videos.go - "Not Interested" Feedback
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// hiddenVideoExists returns an EXISTS expression that is true when the viewer bound to
+// $viewerArg has marked the video column as not interested.
+func hiddenVideoExists(videoColumn string, viewerArg int) string {
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM hidden_videos hv
+			WHERE hv.user_id = $%[2]d AND hv.video_id = %[1]s)`, videoColumn, viewerArg)
+}
+
+// HideVideo records that the user is not interested in a video. Hidden videos are dropped
+// from the user's feeds and their tags count against similar recommendations.
+func (s *VideoService) HideVideo(ctx context.Context, videoID, userID string) error {
+	var videoExists bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM videos WHERE id = $1 AND is_active = true)",
+		videoID).Scan(&videoExists)
+	if err != nil {
+		return err
+	}
+	if !videoExists {
+		return errors.New("video_not_found")
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO hidden_videos (id, video_id, user_id, created_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (video_id, user_id) DO NOTHING`,
+		uuid.New().String(), videoID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("already_hidden")
+	}
+
+	return nil
+}
+
+// UnhideVideo undoes a "not interested" signal
+func (s *VideoService) UnhideVideo(ctx context.Context, videoID, userID string) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM hidden_videos WHERE video_id = $1 AND user_id = $2",
+		videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("not_hidden")
+	}
+
+	return nil
+}
+
+// GetHiddenVideos lists the videos a user marked as not interested, most recent first,
+// so they can be reviewed and restored.
+func (s *VideoService) GetHiddenVideos(ctx context.Context, userID string, limit, offset int) ([]models.VideoResponse, error) {
+	query := `
+		SELECT v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+		       v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+		       v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+		       v.created_at, v.updated_at
+		FROM videos v
+		JOIN hidden_videos hv ON v.id = hv.video_id
+		WHERE hv.user_id = $1 AND v.is_active = true
+		ORDER BY hv.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.VideoResponse
+	for rows.Next() {
+		var video models.VideoResponse
+
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
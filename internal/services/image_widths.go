@@ -0,0 +1,69 @@
+// ===============================
+// internal/services/image_widths.go - Requested CDN Image Widths
+// ===============================
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// maxImageWidth bounds configured widths; the CDN won't upscale past the source anyway
+const maxImageWidth = 4096
+
+var (
+	allowedImageWidths = map[int]bool{160: true, 320: true, 640: true, 1080: true}
+	defaultImageWidth  = 640
+)
+
+// SetImageWidths configures which widths clients may request for CDN image transforms and
+// the width used when they don't ask. The default must be one of the allowed widths.
+func SetImageWidths(widths []int, defaultWidth int) error {
+	allowed := make(map[int]bool, len(widths))
+	for _, width := range widths {
+		if width < 1 || width > maxImageWidth {
+			return fmt.Errorf("image widths must be between 1 and %d, got %d", maxImageWidth, width)
+		}
+		allowed[width] = true
+	}
+	if !allowed[defaultWidth] {
+		return fmt.Errorf("default image width %d is not in the allowed widths %v", defaultWidth, widths)
+	}
+
+	allowedImageWidths = allowed
+	defaultImageWidth = defaultWidth
+	return nil
+}
+
+// AllowedImageWidths returns the configured widths in ascending order
+func AllowedImageWidths() []int {
+	widths := make([]int, 0, len(allowedImageWidths))
+	for width := range allowedImageWidths {
+		widths = append(widths, width)
+	}
+	sort.Ints(widths)
+	return widths
+}
+
+// IsAllowedImageWidth reports whether clients may request width
+func IsAllowedImageWidth(width int) bool {
+	return allowedImageWidths[width]
+}
+
+type imageWidthKey struct{}
+
+// WithImageWidth records the image width the client asked for on ctx
+func WithImageWidth(ctx context.Context, width int) context.Context {
+	return context.WithValue(ctx, imageWidthKey{}, width)
+}
+
+// imageWidthParam returns the requested width from ctx, or the default
+func imageWidthParam(ctx context.Context) string {
+	if width, ok := ctx.Value(imageWidthKey{}).(int); ok && width > 0 {
+		return strconv.Itoa(width)
+	}
+	return strconv.Itoa(defaultImageWidth)
+}
@@ -0,0 +1,281 @@
+// ===============================
+// internal/services/moderation.go - Video Reports and Moderation Queue
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ReportVideo records an open report against a video. A user can hold only one open
+// report per video.
+func (s *VideoService) ReportVideo(ctx context.Context, videoID, reporterID, reason, description string) (string, error) {
+	if !models.IsValidReportReason(reason) {
+		return "", errors.New("invalid_reason")
+	}
+
+	var exists bool
+	err := s.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM videos WHERE id = $1)", videoID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errors.New("video_not_found")
+	}
+
+	var reportIDs []string
+	err = s.db.SelectContext(ctx, &reportIDs, `
+		INSERT INTO video_reports (video_id, reporter_id, reason, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (video_id, reporter_id) WHERE status = 'open' DO NOTHING
+		RETURNING id`,
+		videoID, reporterID, reason, description)
+	if err != nil {
+		return "", err
+	}
+	if len(reportIDs) == 0 {
+		return "", errors.New("already_reported")
+	}
+
+	return reportIDs[0], nil
+}
+
+// GetPendingModeration returns the moderation queue: active unverified videos, videos
+// flagged by the content moderator and any video with open reports, most-reported first,
+// then flagged, then newest
+func (s *VideoService) GetPendingModeration(ctx context.Context, limit, offset int) ([]models.ModerationQueueItem, int, error) {
+	query := `
+		WITH open_reports AS (
+			SELECT video_id,
+			       COUNT(*) AS report_count,
+			       array_agg(DISTINCT reason) AS report_reasons,
+			       MAX(created_at) AS last_reported_at
+			FROM video_reports
+			WHERE status = 'open'
+			GROUP BY video_id
+		)
+		SELECT v.id, v.user_id, v.user_name, v.caption, v.video_url, v.thumbnail_url,
+		       v.is_active, v.is_verified, v.is_flagged, v.flag_reason, v.created_at,
+		       COALESCE(r.report_count, 0) AS report_count,
+		       COALESCE(r.report_reasons, '{}') AS report_reasons,
+		       r.last_reported_at,
+		       COUNT(*) OVER() AS total_count
+		FROM videos v
+		LEFT JOIN open_reports r ON r.video_id = v.id
+		WHERE (v.is_active = true AND v.is_verified = false)
+		   OR v.is_flagged = true
+		   OR r.video_id IS NOT NULL
+		ORDER BY report_count DESC, v.is_flagged DESC, v.created_at DESC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := s.db.QueryxContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []models.ModerationQueueItem{}
+	total := 0
+	for rows.Next() {
+		var row struct {
+			models.ModerationQueueItem
+			TotalCount int `db:"total_count"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, 0, err
+		}
+		total = row.TotalCount
+		items = append(items, row.ModerationQueueItem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// moderationUpdate is a SET clause, an extra WHERE condition and the status open reports on
+// the affected videos are closed with, if any
+type moderationUpdate struct{ set, where, reportStatus string }
+
+// bulkModerationUpdates maps each bulk action to its update. The single-video toggles use
+// the same entries so both paths have the same side effects.
+var bulkModerationUpdates = map[string]moderationUpdate{
+	models.BulkActionDeactivate: {set: "is_active = false", reportStatus: models.ReportStatusResolved},
+	models.BulkActionActivate:   {set: "is_active = true"},
+	models.BulkActionFeature:    {set: "is_featured = true", where: " AND is_active = true"},
+	// Verifying is an admin review, so it also clears any moderator flag and dismisses reports
+	models.BulkActionVerify: {
+		set:          "is_verified = true, is_flagged = false, flag_reason = ''",
+		reportStatus: models.ReportStatusDismissed,
+	},
+}
+
+// BulkModerateVideos applies one moderation action to many videos in a single transaction
+// and reports a result per ID. IDs that are malformed or match no eligible video fail
+// individually without affecting the rest. Deactivating or verifying also closes the
+// videos' open reports in the same transaction, recording moderatorID.
+func (s *VideoService) BulkModerateVideos(ctx context.Context, videoIDs []string, action, moderatorID string) ([]models.BulkModerationResult, error) {
+	update, ok := bulkModerationUpdates[action]
+	if !ok {
+		return nil, errors.New("invalid_action")
+	}
+
+	results := make([]models.BulkModerationResult, len(videoIDs))
+	validIDs := make(models.StringSlice, 0, len(videoIDs))
+	// Results are matched on the canonical form, which is what RETURNING id::text gives
+	// back, so uppercase or braced input still lines up
+	canonicalIDs := make([]string, len(videoIDs))
+	for i, id := range videoIDs {
+		results[i].VideoID = id
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			results[i].Error = "invalid_video_id"
+			continue
+		}
+		canonicalIDs[i] = parsed.String()
+		validIDs = append(validIDs, canonicalIDs[i])
+	}
+
+	updated := make(map[string]bool, len(validIDs))
+	if len(validIDs) > 0 {
+		updatedIDs, err := s.applyModerationUpdate(ctx, validIDs, update, moderatorID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range updatedIDs {
+			updated[id] = true
+		}
+	}
+
+	for i := range results {
+		if results[i].Error != "" {
+			continue
+		}
+		if updated[canonicalIDs[i]] {
+			results[i].Success = true
+		} else {
+			results[i].Error = "video_not_found"
+		}
+	}
+
+	return results, nil
+}
+
+// applyModerationUpdate runs update on the given canonical video IDs and closes their open
+// reports in the same transaction, returning the IDs that were updated
+func (s *VideoService) applyModerationUpdate(ctx context.Context, videoIDs models.StringSlice, update moderationUpdate, moderatorID string) ([]string, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var updatedIDs []string
+	err = tx.SelectContext(ctx, &updatedIDs, `
+		UPDATE videos SET `+update.set+`, updated_at = $2
+		WHERE id = ANY($1::uuid[])`+update.where+`
+		RETURNING id::text`,
+		videoIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if update.reportStatus != "" && len(updatedIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE video_reports
+			SET status = $2, resolved_by = $3, resolved_at = NOW()
+			WHERE video_id = ANY($1::uuid[]) AND status = $4`,
+			models.StringSlice(updatedIDs), update.reportStatus, moderatorID, models.ReportStatusOpen)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updatedIDs, nil
+}
+
+// moderateVideo applies update to one video, returning "video_not_found" when the ID is
+// malformed or matches no eligible video
+func (s *VideoService) moderateVideo(ctx context.Context, videoID string, update moderationUpdate, moderatorID string) error {
+	parsed, err := uuid.Parse(videoID)
+	if err != nil {
+		return errors.New("video_not_found")
+	}
+
+	updatedIDs, err := s.applyModerationUpdate(ctx, models.StringSlice{parsed.String()}, update, moderatorID)
+	if err != nil {
+		return err
+	}
+	if len(updatedIDs) == 0 {
+		return errors.New("video_not_found")
+	}
+	return nil
+}
+
+// ToggleActive activates or deactivates one video. Deactivating resolves its open reports,
+// as the bulk deactivate action does.
+func (s *VideoService) ToggleActive(ctx context.Context, videoID string, isActive bool, moderatorID string) error {
+	action := models.BulkActionDeactivate
+	if isActive {
+		action = models.BulkActionActivate
+	}
+	return s.moderateVideo(ctx, videoID, bulkModerationUpdates[action], moderatorID)
+}
+
+// ToggleVerified verifies or unverifies one active video. Verifying clears any moderator
+// flag and dismisses open reports, as the bulk verify action does.
+func (s *VideoService) ToggleVerified(ctx context.Context, videoID string, isVerified bool, moderatorID string) error {
+	update := moderationUpdate{set: "is_verified = false"}
+	if isVerified {
+		update = bulkModerationUpdates[models.BulkActionVerify]
+	}
+	update.where += " AND is_active = true"
+	return s.moderateVideo(ctx, videoID, update, moderatorID)
+}
+
+// GetFlaggedComments returns comments the content moderator held for review, newest first
+func (s *VideoService) GetFlaggedComments(ctx context.Context, limit, offset int) ([]models.FlaggedComment, int, error) {
+	query := `
+		SELECT c.id, c.video_id, c.author_id, c.author_name, c.content, c.flag_reason, c.created_at,
+		       COUNT(*) OVER() AS total_count
+		FROM comments c
+		WHERE c.is_flagged = true
+		ORDER BY c.created_at DESC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := s.db.QueryxContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	comments := []models.FlaggedComment{}
+	total := 0
+	for rows.Next() {
+		var row struct {
+			models.FlaggedComment
+			TotalCount int `db:"total_count"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, 0, err
+		}
+		total = row.TotalCount
+		comments = append(comments, row.FlaggedComment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
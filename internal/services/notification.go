@@ -0,0 +1,140 @@
+// ===============================
+// internal/services/notification.go - In-app Notifications
+// ===============================
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationService struct {
+	db *sqlx.DB
+}
+
+func NewNotificationService(db *sqlx.DB) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+// ===============================
+// CREATE
+// ===============================
+
+// CreateNotification stores a single notification
+func (s *NotificationService) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	if notification.Data == nil {
+		notification.Data = models.MetadataMap{}
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, actor_id, type, entity_type, entity_id, message, data)
+		VALUES (:user_id, :actor_id, :type, :entity_type, :entity_id, :message, :data)`
+
+	_, err := s.db.NamedExecContext(ctx, query, notification)
+	return err
+}
+
+// NotifyMentions notifies every user mentioned in a freshly created comment
+func (s *NotificationService) NotifyMentions(ctx context.Context, comment *models.Comment) error {
+	entityType := "comment"
+	for _, uid := range comment.Mentions {
+		err := s.CreateNotification(ctx, &models.Notification{
+			UserID:     uid,
+			ActorID:    &comment.AuthorID,
+			Type:       models.NotificationTypeMention,
+			EntityType: &entityType,
+			EntityID:   &comment.ID,
+			Message:    fmt.Sprintf("%s mentioned you in a comment", comment.AuthorName),
+			Data: models.MetadataMap{
+				"videoId":   comment.VideoID,
+				"commentId": comment.ID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyMentionsAsync runs NotifyMentions in the background so comment creation is not delayed
+func (s *NotificationService) NotifyMentionsAsync(comment models.Comment) {
+	if len(comment.Mentions) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := s.NotifyMentions(ctx, &comment); err != nil {
+			log.Printf("⚠️ Failed to create mention notifications for comment %s: %v", comment.ID, err)
+		}
+	}()
+}
+
+// ===============================
+// READ
+// ===============================
+
+func (s *NotificationService) GetUserNotifications(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, actor_id, type, entity_type, entity_id, message, data, is_read, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1`
+
+	if unreadOnly {
+		query += " AND is_read = false"
+	}
+
+	query += `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	notifications := []models.Notification{}
+	err := s.db.SelectContext(ctx, &notifications, query, userID, limit, offset)
+	return notifications, err
+}
+
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := s.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = false`, userID)
+	return count, err
+}
+
+// ===============================
+// UPDATE
+// ===============================
+
+// MarkNotificationsRead marks the user's unread notifications read in a single UPDATE and
+// returns how many changed. An unknown beforeId matches nothing.
+func (s *NotificationService) MarkNotificationsRead(ctx context.Context, userID string, req models.MarkNotificationsReadRequest) (int64, error) {
+	query := `
+		UPDATE notifications n
+		SET is_read = true, read_at = NOW()
+		WHERE n.user_id = $1 AND n.is_read = false`
+
+	args := []interface{}{userID}
+	if req.Type != "" {
+		args = append(args, req.Type)
+		query += fmt.Sprintf(" AND n.type = $%d", len(args))
+	}
+	if req.BeforeID != "" {
+		args = append(args, req.BeforeID)
+		query += fmt.Sprintf(` AND n.created_at <= (
+			SELECT created_at FROM notifications WHERE id = $%d AND user_id = $1)`, len(args))
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
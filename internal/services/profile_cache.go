@@ -0,0 +1,126 @@
+// ===============================
+// internal/services/profile_cache.go - Public Profile Response Cache
+// ===============================
+
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"weibaobe/internal/models"
+)
+
+// ProfileCache keeps recently served public profiles in memory for a short TTL. Anything
+// that changes a profile or its follow counts calls Invalidate; other drift (last seen,
+// reconciled counts) is bounded by the TTL. A nil cache, or one with ttl <= 0, never
+// stores anything, so callers don't need to check whether caching is enabled.
+type ProfileCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]profileCacheEntry
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+type profileCacheEntry struct {
+	profile   models.UserResponse
+	expiresAt time.Time
+}
+
+func NewProfileCache(ttl time.Duration) *ProfileCache {
+	cache := &ProfileCache{
+		ttl:     ttl,
+		entries: make(map[string]profileCacheEntry),
+	}
+	if ttl > 0 {
+		go cache.cleanupRoutine()
+	}
+	return cache
+}
+
+func (pc *ProfileCache) enabled() bool {
+	return pc != nil && pc.ttl > 0
+}
+
+// Get returns a copy of the cached profile for uid, if it hasn't expired
+func (pc *ProfileCache) Get(uid string) (models.UserResponse, bool) {
+	if !pc.enabled() {
+		return models.UserResponse{}, false
+	}
+
+	pc.mu.RLock()
+	entry, ok := pc.entries[uid]
+	pc.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		pc.misses.Add(1)
+		return models.UserResponse{}, false
+	}
+	pc.hits.Add(1)
+	return entry.profile, true
+}
+
+func (pc *ProfileCache) Set(uid string, profile models.UserResponse) {
+	if !pc.enabled() {
+		return
+	}
+
+	pc.mu.Lock()
+	pc.entries[uid] = profileCacheEntry{profile: profile, expiresAt: time.Now().Add(pc.ttl)}
+	pc.mu.Unlock()
+}
+
+// Invalidate drops the cached profiles for the given users
+func (pc *ProfileCache) Invalidate(uids ...string) {
+	if !pc.enabled() {
+		return
+	}
+
+	pc.mu.Lock()
+	for _, uid := range uids {
+		delete(pc.entries, uid)
+	}
+	pc.mu.Unlock()
+}
+
+// Stats reports hit/miss counters since startup and the current entry count
+func (pc *ProfileCache) Stats() models.ProfileCacheStats {
+	if !pc.enabled() {
+		return models.ProfileCacheStats{Enabled: false}
+	}
+
+	pc.mu.RLock()
+	entries := len(pc.entries)
+	pc.mu.RUnlock()
+
+	hits, misses := pc.hits.Load(), pc.misses.Load()
+	stats := models.ProfileCacheStats{
+		Enabled:    true,
+		TTLSeconds: int(pc.ttl.Seconds()),
+		Entries:    entries,
+		Hits:       hits,
+		Misses:     misses,
+	}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+func (pc *ProfileCache) cleanupRoutine() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		pc.mu.Lock()
+		for uid, entry := range pc.entries {
+			if now.After(entry.expiresAt) {
+				delete(pc.entries, uid)
+			}
+		}
+		pc.mu.Unlock()
+	}
+}
@@ -0,0 +1,120 @@
+// ===============================
+// internal/services/recommendations.go - Personalized Video Recommendations
+// ===============================
+
+package services
+
+import (
+	"context"
+
+	"weibaobe/internal/models"
+)
+
+const (
+	// recommendationLikeHistory is how many of the user's most recent likes feed tag affinity
+	recommendationLikeHistory = 50
+	// recommendationFollowWeight boosts videos posted by creators the user follows
+	recommendationFollowWeight = 3.0
+	// recommendationTagWeight is added for every tag shared with a recently liked video
+	recommendationTagWeight = 1.5
+	// recommendationHiddenTagWeight is subtracted for every tag shared with a hidden video
+	recommendationHiddenTagWeight = 2.0
+)
+
+// GetPersonalizedRecommendations ranks active videos for a user. Recent videos (last 30
+// days) from followed creators and videos sharing tags with the user's recently liked
+// videos are scored first; everything else is ordered by the trending score, so users
+// with no follows or likes get plain trending. Tags of videos the user marked as not
+// interested count against a video. The user's own videos, videos they already liked or
+// hid and videos from blocked pairs are excluded.
+func (s *VideoService) GetPersonalizedRecommendations(ctx context.Context, userID string, limit int) ([]models.VideoResponse, error) {
+	query := `
+		WITH recent_likes AS (
+			SELECT video_id FROM video_likes
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $3
+		),
+		liked_tags AS (
+			SELECT DISTINCT LOWER(t) AS tag
+			FROM videos lv
+			JOIN recent_likes rl ON rl.video_id = lv.id
+			CROSS JOIN LATERAL unnest(lv.tags) AS t
+		),
+		hidden_tags AS (
+			SELECT DISTINCT LOWER(t) AS tag
+			FROM videos hvv
+			JOIN hidden_videos hv ON hv.video_id = hvv.id
+			CROSS JOIN LATERAL unnest(hvv.tags) AS t
+			WHERE hv.user_id = $1
+		)
+		SELECT 
+			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+			v.created_at, v.updated_at,
+			CASE 
+				WHEN v.created_at < NOW() - INTERVAL '30 days' THEN 0
+				ELSE
+					CASE WHEN EXISTS (
+						SELECT 1 FROM user_follows uf
+						WHERE uf.follower_id = $1 AND uf.following_id = v.user_id
+					) THEN $4::float8 ELSE 0 END
+					+ $5::float8 * (
+						SELECT COUNT(*) FROM unnest(v.tags) AS vt
+						WHERE LOWER(vt) IN (SELECT tag FROM liked_tags)
+					)
+			END - $6::float8 * (
+				SELECT COUNT(*) FROM unnest(v.tags) AS vt
+				WHERE LOWER(vt) IN (SELECT tag FROM hidden_tags)
+			) as relevance_score,
+			` + trendingScoreSQL + ` as trending_score
+		FROM videos v
+		WHERE v.is_active = true
+		  AND v.user_id != $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM video_likes vl
+			WHERE vl.video_id = v.id AND vl.user_id = $1
+		  )
+		  AND NOT ` + blockedPairExists("v.user_id", 1) + `
+		  AND NOT ` + hiddenVideoExists("v.id", 1) + `
+		  AND NOT ` + privateAuthorHidden("v.user_id", 1) + `
+		ORDER BY relevance_score DESC, trending_score DESC, v.created_at DESC
+		LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, limit, recommendationLikeHistory,
+		recommendationFollowWeight, recommendationTagWeight, recommendationHiddenTagWeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.VideoResponse
+	for rows.Next() {
+		var video models.VideoResponse
+		var relevanceScore, trendingScore float64
+
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+			&relevanceScore, &trendingScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
@@ -0,0 +1,211 @@
+// ===============================
+// internal/services/reward.go - Engagement Milestone Rewards
+// ===============================
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const rewardCheckTimeout = 10 * time.Second
+
+type RewardService struct {
+	db            *sqlx.DB
+	walletService *WalletService
+	milestones    map[string]models.RewardMilestone
+
+	// Daily claim amounts and the timezone whose calendar days it follows
+	daily         config.RewardsConfig
+	dailyLocation *time.Location
+}
+
+func NewRewardService(db *sqlx.DB, walletService *WalletService, cfg config.RewardsConfig) *RewardService {
+	location, err := time.LoadLocation(cfg.DailyTimezone)
+	if err != nil {
+		log.Printf("⚠️ Unknown REWARD_DAILY_TIMEZONE %q, daily claims use UTC: %v", cfg.DailyTimezone, err)
+		location = time.UTC
+	}
+
+	return &RewardService{
+		db:            db,
+		walletService: walletService,
+		milestones: map[string]models.RewardMilestone{
+			models.MilestoneFirstPost:  {Kind: models.MilestoneFirstPost, Threshold: 1, Coins: cfg.FirstPostCoins},
+			models.MilestoneFollowers:  {Kind: models.MilestoneFollowers, Threshold: cfg.FollowersThreshold, Coins: cfg.FollowersCoins},
+			models.MilestoneVideoViews: {Kind: models.MilestoneVideoViews, Threshold: cfg.VideoViewsThreshold, Coins: cfg.VideoViewsCoins},
+		},
+		daily:         cfg,
+		dailyLocation: location,
+	}
+}
+
+// ===============================
+// MILESTONE CHECKS
+// ===============================
+
+// CheckFirstPost rewards a user's first published video
+func (s *RewardService) CheckFirstPost(ctx context.Context, userID string) error {
+	milestone, ok := s.enabled(models.MilestoneFirstPost)
+	if !ok {
+		return nil
+	}
+
+	var videosCount int
+	err := s.db.GetContext(ctx, &videosCount, `SELECT videos_count FROM users WHERE uid = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if videosCount < milestone.Threshold {
+		return nil
+	}
+
+	return s.grant(ctx, userID, milestone, nil)
+}
+
+// CheckFollowers rewards a user once their follower count reaches the threshold
+func (s *RewardService) CheckFollowers(ctx context.Context, userID string) error {
+	milestone, ok := s.enabled(models.MilestoneFollowers)
+	if !ok {
+		return nil
+	}
+
+	var followersCount int
+	err := s.db.GetContext(ctx, &followersCount, `SELECT followers_count FROM users WHERE uid = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if followersCount < milestone.Threshold {
+		return nil
+	}
+
+	return s.grant(ctx, userID, milestone, nil)
+}
+
+// CheckVideoViews rewards the owner the first time one of their videos reaches the view threshold
+func (s *RewardService) CheckVideoViews(ctx context.Context, videoID string) error {
+	milestone, ok := s.enabled(models.MilestoneVideoViews)
+	if !ok {
+		return nil
+	}
+
+	var video struct {
+		UserID     string `db:"user_id"`
+		ViewsCount int    `db:"views_count"`
+	}
+	err := s.db.GetContext(ctx, &video, `SELECT user_id, views_count FROM videos WHERE id = $1`, videoID)
+	if err != nil {
+		return err
+	}
+	if video.ViewsCount < milestone.Threshold {
+		return nil
+	}
+
+	return s.grant(ctx, video.UserID, milestone, &videoID)
+}
+
+// ===============================
+// ASYNC HOOKS (called after the triggering action succeeds)
+// ===============================
+
+func (s *RewardService) OnVideoPosted(userID string) {
+	s.runAsync("first post", func(ctx context.Context) error { return s.CheckFirstPost(ctx, userID) })
+}
+
+func (s *RewardService) OnUserFollowed(userID string) {
+	s.runAsync("followers", func(ctx context.Context) error { return s.CheckFollowers(ctx, userID) })
+}
+
+func (s *RewardService) OnVideoViewed(videoID string) {
+	s.runAsync("video views", func(ctx context.Context) error { return s.CheckVideoViews(ctx, videoID) })
+}
+
+func (s *RewardService) runAsync(name string, check func(ctx context.Context) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rewardCheckTimeout)
+		defer cancel()
+
+		if err := check(ctx); err != nil {
+			log.Printf("⚠️ Reward check (%s) failed: %v", name, err)
+		}
+	}()
+}
+
+// ===============================
+// GRANTS
+// ===============================
+
+func (s *RewardService) enabled(kind string) (models.RewardMilestone, bool) {
+	milestone, ok := s.milestones[kind]
+	if !ok || milestone.Coins <= 0 || milestone.Threshold <= 0 {
+		return milestone, false
+	}
+	return milestone, true
+}
+
+// milestoneKey includes the threshold so raising a threshold creates a new, separately granted milestone
+func milestoneKey(milestone models.RewardMilestone) string {
+	return fmt.Sprintf("%s_%d", milestone.Kind, milestone.Threshold)
+}
+
+// grant records the milestone and credits the wallet in one transaction.
+// The unique (user_id, milestone_key) row guarantees each milestone pays out once.
+func (s *RewardService) grant(ctx context.Context, userID string, milestone models.RewardMilestone, referenceID *string) error {
+	// Make sure the wallet exists before crediting it
+	if _, err := s.walletService.GetWallet(ctx, userID); err != nil {
+		return fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	key := milestoneKey(milestone)
+
+	var grantID string
+	err = tx.GetContext(ctx, &grantID, `
+		INSERT INTO reward_grants (user_id, milestone_key, reference_id, coins)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, milestone_key) DO NOTHING
+		RETURNING id`,
+		userID, key, referenceID, milestone.Coins)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Already granted
+			return nil
+		}
+		return err
+	}
+
+	walletTx, err := s.walletService.Credit(ctx, tx, userID, milestone.Coins, "reward", models.WalletLedgerMeta{
+		Description: fmt.Sprintf("Milestone reward: %s", key),
+		ReferenceID: &grantID,
+		Metadata:    models.MetadataMap{"milestone": key},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE reward_grants SET transaction_id = $1 WHERE id = $2`, walletTx.TransactionID, grantID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("🎁 Granted %d coins to user %s for milestone %s", milestone.Coins, userID, key)
+	return nil
+}
@@ -0,0 +1,47 @@
+// ===============================
+// internal/services/search_query.go - Search Query Length Guard
+// ===============================
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMinSearchQueryLength keeps the configured minimum from locking search out entirely
+const maxMinSearchQueryLength = 10
+
+// minSearchQueryLength is the fewest characters a search query may have after trimming.
+// Shorter queries match almost everything and turn into full trigram scans.
+var minSearchQueryLength = 2
+
+// SetMinSearchQueryLength configures the minimum search query length in characters
+func SetMinSearchQueryLength(length int) error {
+	if length < 1 || length > maxMinSearchQueryLength {
+		return fmt.Errorf("minimum search query length must be between 1 and %d, got %d", maxMinSearchQueryLength, length)
+	}
+	minSearchQueryLength = length
+	return nil
+}
+
+// MinSearchQueryLength returns the configured minimum search query length
+func MinSearchQueryLength() int {
+	return minSearchQueryLength
+}
+
+// NormalizeSearchQuery trims query and checks it against the minimum length, counting
+// characters rather than bytes. It returns "search_query_required" for blank queries and
+// "search_query_too_short" for short ones.
+func NormalizeSearchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", errors.New("search_query_required")
+	}
+	if utf8.RuneCountInString(query) < minSearchQueryLength {
+		return "", errors.New("search_query_too_short")
+	}
+	return query, nil
+}
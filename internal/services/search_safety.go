@@ -0,0 +1,72 @@
+// ===============================
+// internal/services/search_safety.go - Sensitive Search Query Policies
+// ===============================
+
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/models"
+)
+
+type searchSafetyRule struct {
+	pattern *regexp.Regexp
+	notice  models.SearchSafetyNotice
+}
+
+// SearchSafetyPolicy matches search queries against configured sensitive patterns.
+// Rules are checked in order and the first match wins.
+type SearchSafetyPolicy struct {
+	rules []searchSafetyRule
+}
+
+// NewSearchSafetyPolicy compiles the configured rules, failing on a bad pattern or action
+// so a typo can't silently disable a safety rule
+func NewSearchSafetyPolicy(rules []config.SearchSafetyRule) (*SearchSafetyPolicy, error) {
+	policy := &SearchSafetyPolicy{rules: make([]searchSafetyRule, 0, len(rules))}
+
+	for i, rule := range rules {
+		action := models.SearchSafetyAction(strings.ToLower(strings.TrimSpace(rule.Action)))
+		switch action {
+		case models.SearchSafetyBlock, models.SearchSafetyWarn, models.SearchSafetyRedirect:
+		default:
+			return nil, fmt.Errorf("search safety rule %d: unknown action %q", i, rule.Action)
+		}
+
+		pattern, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("search safety rule %d: invalid pattern: %w", i, err)
+		}
+
+		policy.rules = append(policy.rules, searchSafetyRule{
+			pattern: pattern,
+			notice: models.SearchSafetyNotice{
+				Action:      action,
+				Message:     rule.Message,
+				ResourceURL: rule.ResourceURL,
+			},
+		})
+	}
+
+	return policy, nil
+}
+
+// Check returns the safe response for a sensitive query, or nil for a normal one
+func (p *SearchSafetyPolicy) Check(query string) *models.SearchSafetyNotice {
+	if p == nil {
+		return nil
+	}
+
+	normalized := strings.Join(strings.Fields(query), " ")
+	for _, rule := range p.rules {
+		if rule.pattern.MatchString(normalized) {
+			notice := rule.notice
+			return &notice
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// ===============================
+// internal/services/thumbnail.go - Server-side video thumbnail generation
+// ===============================
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	thumbnailFrameOffset = "1"              // seconds into the video
+	thumbnailTimeout     = 30 * time.Second // upper bound for a single ffmpeg run
+)
+
+// thumbnailKeyForVideo derives the thumbnail object key from the video key so the
+// generated thumbnail can be found again from the video URL alone
+func thumbnailKeyForVideo(videoKey string) string {
+	base := strings.TrimSuffix(path.Base(videoKey), path.Ext(videoKey))
+	return "thumbnail/auto/" + base + ".jpg"
+}
+
+// ffmpegAvailable reports whether an ffmpeg binary can be found on PATH
+func ffmpegAvailable() bool {
+	_, err := exec.LookPath("ffmpeg")
+	return err == nil
+}
+
+// UploadVideo stores a video in R2 and, when ffmpeg is installed, extracts a frame at
+// ~1s and uploads it as the thumbnail. Thumbnail failures never fail the upload; the
+// returned thumbnail URL is simply empty.
+func (s *UploadService) UploadVideo(ctx context.Context, file multipart.File, filename, contentType string) (string, string, error) {
+	videoURL, err := s.UploadFile(ctx, file, filename, "video", contentType)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !ffmpegAvailable() {
+		return videoURL, "", nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		log.Printf("⚠️ Thumbnail skipped for %s: %v", filename, err)
+		return videoURL, "", nil
+	}
+
+	thumbnailURL, err := s.generateThumbnail(ctx, file, videoURL, getFileExtension(filename))
+	if err != nil {
+		log.Printf("⚠️ Thumbnail generation failed for %s: %v", videoURL, err)
+		return videoURL, "", nil
+	}
+
+	return videoURL, thumbnailURL, nil
+}
+
+// generateThumbnail writes the video to a temp file, extracts one frame with ffmpeg
+// and uploads it next to the video under a key derived from the video URL
+func (s *UploadService) generateThumbnail(ctx context.Context, file io.Reader, videoURL, ext string) (string, error) {
+	videoKey, ok := s.r2Client.KeyFromURL(videoURL)
+	if !ok {
+		return "", fmt.Errorf("video URL is not served from the bucket")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "thumb-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inputPath := filepath.Join(tmpDir, "input"+ext)
+	outputPath := filepath.Join(tmpDir, "thumb.jpg")
+
+	input, err := os.Create(inputPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(input, file); err != nil {
+		input.Close()
+		return "", err
+	}
+	if err := input.Close(); err != nil {
+		return "", err
+	}
+
+	if err := extractFrame(ctx, inputPath, outputPath, thumbnailFrameOffset); err != nil {
+		// Clips shorter than the offset have no frame there; fall back to the first one
+		if err := extractFrame(ctx, inputPath, outputPath, "0"); err != nil {
+			return "", err
+		}
+	}
+
+	thumb, err := os.Open(outputPath)
+	if err != nil {
+		return "", err
+	}
+	defer thumb.Close()
+
+	thumbnailKey := thumbnailKeyForVideo(videoKey)
+	if err := s.r2Client.UploadFile(ctx, thumbnailKey, thumb, "image/jpeg"); err != nil {
+		return "", err
+	}
+
+	return s.r2Client.GetPublicURL(thumbnailKey), nil
+}
+
+func extractFrame(ctx context.Context, inputPath, outputPath, offset string) error {
+	ctx, cancel := context.WithTimeout(ctx, thumbnailTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-ss", offset,
+		"-i", inputPath,
+		"-frames:v", "1",
+		"-vf", "scale='min(720,iw)':-2",
+		"-q:v", "3",
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	if info, err := os.Stat(outputPath); err != nil || info.Size() == 0 {
+		return fmt.Errorf("ffmpeg produced no frame at %ss", offset)
+	}
+	return nil
+}
+
+// GeneratedThumbnailURL returns the server-generated thumbnail for a video uploaded
+// through UploadVideo, or an empty string if none exists
+func (s *UploadService) GeneratedThumbnailURL(ctx context.Context, videoURL string) string {
+	videoKey, ok := s.r2Client.KeyFromURL(videoURL)
+	if !ok {
+		return ""
+	}
+
+	thumbnailKey := thumbnailKeyForVideo(videoKey)
+	exists, err := s.r2Client.FileExists(ctx, thumbnailKey)
+	if err != nil || !exists {
+		return ""
+	}
+	return s.r2Client.GetPublicURL(thumbnailKey)
+}
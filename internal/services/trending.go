@@ -0,0 +1,249 @@
+// ===============================
+// internal/services/trending.go - Precomputed Trending Ranking
+// ===============================
+
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"weibaobe/internal/database"
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RefreshTrendingRanking scores every active video once and keeps the top size IDs in
+// rank order, so trending requests only have to load those rows
+func (s *VideoService) RefreshTrendingRanking(ctx context.Context, size int, maxAge time.Duration) error {
+	// Scores every active video, so it gets the admin statement budget
+	var videoIDs []string
+	err := database.WithAdminStatementTimeout(ctx, s.db, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &videoIDs, `
+			SELECT v.id::text
+			FROM videos v
+			WHERE v.is_active = true
+			ORDER BY `+trendingScoreSQL+` DESC, v.created_at DESC
+			LIMIT $1`, size)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.trendingMu.Lock()
+	s.trendingRanking = trendingRanking{
+		videoIDs:   videoIDs,
+		computedAt: time.Now(),
+		maxAge:     maxAge,
+	}
+	s.trendingMu.Unlock()
+	return nil
+}
+
+// cachedTrendingIDs returns the precomputed ranking, or nil when it is cold, stale or
+// too short to fill limit (viewer filters may drop some rows, so a full ranking is
+// required rather than exactly limit IDs)
+func (s *VideoService) cachedTrendingIDs(limit int) []string {
+	s.trendingMu.RLock()
+	defer s.trendingMu.RUnlock()
+
+	ranking := s.trendingRanking
+	if ranking.computedAt.IsZero() || time.Since(ranking.computedAt) > ranking.maxAge {
+		return nil
+	}
+	if len(ranking.videoIDs) < limit {
+		return nil
+	}
+	return ranking.videoIDs
+}
+
+// getTrendingVideosFromRanking loads the ranked videos in rank order. Videos deactivated
+// since the last refresh and the viewer's blocked or hidden videos are skipped.
+func (s *VideoService) getTrendingVideosFromRanking(ctx context.Context, videoIDs []string, viewerID string, limit int) ([]models.VideoResponse, error) {
+	query := `
+		SELECT 
+			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+			v.created_at, v.updated_at
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS ranked(id, rank)
+		JOIN videos v ON v.id = ranked.id
+		WHERE v.is_active = true`
+
+	args := []interface{}{limit, models.StringSlice(videoIDs)}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", 3)
+		query += " AND NOT " + hiddenVideoExists("v.id", 3)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 3)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
+		ORDER BY ranked.rank
+		LIMIT $1`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.VideoResponse
+	for rows.Next() {
+		var video models.VideoResponse
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+// StartTrendingRefresher re-ranks trending videos every interval. A ranking older than
+// three intervals (e.g. the database was unreachable) is treated as cold and requests fall
+// back to the live query. interval <= 0 disables precomputation. The returned function
+// stops the job.
+func (s *VideoService) StartTrendingRefresher(interval time.Duration, size int) func() {
+	if interval <= 0 {
+		log.Println("🔥 Trending precomputation disabled, serving live rankings")
+		return func() {}
+	}
+	if size <= 0 {
+		size = 500
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func() {
+		if err := s.RefreshTrendingRanking(ctx, size, 3*interval); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️ Trending ranking refresh failed: %v", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("🔥 Trending precomputation started (top %d, every %s)", size, interval)
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
+
+// trendingPeriods maps the trending tabs to how far back engagement is counted
+var trendingPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// GetTrendingInPeriod ranks active videos by engagement that happened within period
+// ("day", "week" or "month") only, using the same weights as the all-time score. Likes and
+// comments are counted by created_at, views and shares from their daily buckets.
+func (s *VideoService) GetTrendingInPeriod(ctx context.Context, period, viewerID string, limit int) ([]models.VideoResponse, error) {
+	window, ok := trendingPeriods[period]
+	if !ok {
+		return nil, errors.New("invalid_period")
+	}
+	since := time.Now().Add(-window)
+
+	query := `
+		WITH engagement AS (
+			SELECT video_id, SUM(likes) AS likes, SUM(comments) AS comments,
+			       SUM(shares) AS shares, SUM(views) AS views
+			FROM (
+				SELECT video_id, COUNT(*) AS likes, 0 AS comments, 0 AS shares, 0 AS views
+				FROM video_likes WHERE created_at >= $2 GROUP BY video_id
+				UNION ALL
+				SELECT video_id, 0, COUNT(*), 0, 0
+				FROM comments WHERE created_at >= $2 GROUP BY video_id
+				UNION ALL
+				SELECT video_id, 0, 0, SUM(shares), SUM(views)
+				FROM video_daily_engagement WHERE day >= $2::date GROUP BY video_id
+			) counts
+			GROUP BY video_id
+		)
+		SELECT 
+			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+			v.created_at, v.updated_at
+		FROM engagement e
+		JOIN videos v ON v.id = e.video_id
+		WHERE v.is_active = true`
+
+	args := []interface{}{limit, since}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", 3)
+		query += " AND NOT " + hiddenVideoExists("v.id", 3)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 3)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
+		ORDER BY (e.likes * 2.5 + e.comments * 3.5 + e.shares * 5.0 + e.views * 0.1) DESC, v.created_at DESC
+		LIMIT $1`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := []models.VideoResponse{}
+	for rows.Next() {
+		var video models.VideoResponse
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+
+		videos = append(videos, video)
+	}
+
+	return videos, rows.Err()
+}
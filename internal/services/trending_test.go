@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/database"
+)
+
+// BenchmarkTrendingVideos compares the live trending query, which scores every active
+// video, with serving from the precomputed ranking. It needs a PostgreSQL database with
+// a realistic videos table (e.g. a restored staging snapshot):
+//
+//	TEST_DATABASE_URL=postgres://... go test -run '^$' -bench Trending ./internal/services/
+func BenchmarkTrendingVideos(b *testing.B) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		b.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := database.Connect(databaseURL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	const limit = 50
+	ctx := context.Background()
+	s := NewVideoService(db, nil, nil, nil, config.DuplicateUploadConfig{}, nil, nil)
+
+	b.Run("live", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.getTrendingVideosLive(ctx, "", limit); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	if err := s.RefreshTrendingRanking(ctx, 500, time.Hour); err != nil {
+		b.Fatal(err)
+	}
+	videoIDs := s.cachedTrendingIDs(limit)
+	if videoIDs == nil {
+		b.Skipf("fewer than %d active videos to rank", limit)
+	}
+
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.getTrendingVideosFromRanking(ctx, videoIDs, "", limit); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("refresh", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := s.RefreshTrendingRanking(ctx, 500, time.Hour); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -5,11 +5,16 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
+	"strings"
 	"time"
 
+	"weibaobe/internal/config"
 	"weibaobe/internal/storage"
 
 	"github.com/google/uuid"
@@ -17,19 +22,129 @@ import (
 
 type UploadService struct {
 	r2Client *storage.R2Client
+	limits   config.UploadConfig
 }
 
-func NewUploadService(r2Client *storage.R2Client) *UploadService {
-	return &UploadService{r2Client: r2Client}
+func NewUploadService(r2Client *storage.R2Client, limits config.UploadConfig) *UploadService {
+	return &UploadService{r2Client: r2Client, limits: limits}
 }
 
-func (s *UploadService) UploadFile(ctx context.Context, file multipart.File, filename, fileType string) (string, error) {
+// Content types accepted per upload category, matched against the sniffed file content
+var allowedUploadContentTypes = map[string][]string{
+	"banner":    {"image/jpeg", "image/png", "image/webp", "image/gif"},
+	"thumbnail": {"image/jpeg", "image/png", "image/webp"},
+	"profile":   {"image/jpeg", "image/png", "image/webp"},
+	"video": {
+		"video/mp4", "video/quicktime", "video/avi", "video/webm", "video/mp2t",
+		"application/vnd.apple.mpegurl",
+	},
+}
+
+// UploadError describes why an upload was rejected
+type UploadError struct {
+	Code         string // FILE_TOO_LARGE, UNSUPPORTED_CONTENT_TYPE, UNKNOWN_FILE_CATEGORY, UNREADABLE_FILE, INVALID_FILE_SIZE
+	Message      string
+	DetectedType string
+	Size         int64
+	MaxSize      int64
+}
+
+func (e *UploadError) Error() string {
+	return e.Message
+}
+
+// MaxSizeFor returns the configured size limit for an upload category
+func (s *UploadService) MaxSizeFor(fileType string) int64 {
+	switch fileType {
+	case "video":
+		return s.limits.MaxVideoSize
+	case "profile", "thumbnail":
+		return s.limits.MaxThumbnailSize
+	}
+	return s.limits.MaxImageSize
+}
+
+// AllowedContentTypes returns the accepted content types for an upload category
+func (s *UploadService) AllowedContentTypes(fileType string) []string {
+	return allowedUploadContentTypes[fileType]
+}
+
+// ValidateFile enforces the size limit and sniffs the content type from the file's
+// leading bytes instead of trusting the client-provided extension. On success the
+// detected content type is returned and the file is rewound to the start.
+func (s *UploadService) ValidateFile(file multipart.File, size int64, fileType string) (string, error) {
+	allowed, exists := allowedUploadContentTypes[fileType]
+	if !exists {
+		return "", &UploadError{Code: "UNKNOWN_FILE_CATEGORY", Message: "Invalid file type category"}
+	}
+
+	maxSize := s.MaxSizeFor(fileType)
+	if size > maxSize {
+		return "", &UploadError{
+			Code:    "FILE_TOO_LARGE",
+			Message: "File too large",
+			Size:    size,
+			MaxSize: maxSize,
+		}
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", &UploadError{Code: "UNREADABLE_FILE", Message: "Failed to read file"}
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", &UploadError{Code: "UNREADABLE_FILE", Message: "Failed to read file"}
+	}
+
+	detected := detectContentType(head[:n])
+	for _, contentType := range allowed {
+		if detected == contentType {
+			return detected, nil
+		}
+	}
+
+	return "", &UploadError{
+		Code:         "UNSUPPORTED_CONTENT_TYPE",
+		Message:      fmt.Sprintf("File content is not an allowed %s type", fileType),
+		DetectedType: detected,
+	}
+}
+
+// detectContentType extends http.DetectContentType with the video containers it does not
+// recognise (QuickTime, other ISO-BMFF brands, MPEG-TS and HLS playlists)
+func detectContentType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		if string(head[8:10]) == "qt" {
+			return "video/quicktime"
+		}
+		return "video/mp4"
+	}
+	if len(head) >= 189 && head[0] == 0x47 && head[188] == 0x47 {
+		return "video/mp2t"
+	}
+	if bytes.HasPrefix(bytes.TrimLeft(head, "\xef\xbb\xbf \t\r\n"), []byte("#EXTM3U")) {
+		return "application/vnd.apple.mpegurl"
+	}
+
+	detected := http.DetectContentType(head)
+	if i := strings.Index(detected, ";"); i >= 0 {
+		detected = detected[:i]
+	}
+	return detected
+}
+
+// UploadFile stores the file in R2. contentType should be the value returned by
+// ValidateFile; when empty it is derived from the extension.
+func (s *UploadService) UploadFile(ctx context.Context, file multipart.File, filename, fileType, contentType string) (string, error) {
 	// Generate unique filename
 	ext := getFileExtension(filename)
-	uniqueFilename := fmt.Sprintf("%s/%d_%s%s", fileType, time.Now().Unix(), uuid.New().String()[:8], ext)
+	uniqueFilename := newObjectKey(fileType, ext)
 
 	// Determine content type
-	contentType := getContentType(fileType, ext)
+	if contentType == "" {
+		contentType = getContentType(fileType, ext)
+	}
 
 	// Upload to R2
 	err := s.r2Client.UploadFile(ctx, uniqueFilename, file, contentType)
@@ -41,6 +156,78 @@ func (s *UploadService) UploadFile(ctx context.Context, file multipart.File, fil
 	return s.r2Client.GetPublicURL(uniqueFilename), nil
 }
 
+// PresignedUpload describes a direct-to-R2 upload the client performs itself
+type PresignedUpload struct {
+	UploadURL string            `json:"uploadUrl"`
+	PublicURL string            `json:"publicUrl"`
+	Key       string            `json:"key"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// PresignedUploadTTL is how long a presigned upload URL stays valid
+const PresignedUploadTTL = 15 * time.Minute
+
+// PresignUpload validates the declared content type and size against the same limits
+// as proxied uploads and returns a presigned PUT URL plus the object's final public URL
+func (s *UploadService) PresignUpload(fileType, filename, contentType string, size int64) (*PresignedUpload, error) {
+	allowed, exists := allowedUploadContentTypes[fileType]
+	if !exists {
+		return nil, &UploadError{Code: "UNKNOWN_FILE_CATEGORY", Message: "Invalid file type category"}
+	}
+
+	if size <= 0 {
+		return nil, &UploadError{Code: "INVALID_FILE_SIZE", Message: "File size must be positive"}
+	}
+
+	maxSize := s.MaxSizeFor(fileType)
+	if size > maxSize {
+		return nil, &UploadError{
+			Code:    "FILE_TOO_LARGE",
+			Message: "File too large",
+			Size:    size,
+			MaxSize: maxSize,
+		}
+	}
+
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	supported := false
+	for _, allowedType := range allowed {
+		if contentType == allowedType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, &UploadError{
+			Code:         "UNSUPPORTED_CONTENT_TYPE",
+			Message:      fmt.Sprintf("Content type is not an allowed %s type", fileType),
+			DetectedType: contentType,
+		}
+	}
+
+	key := newObjectKey(fileType, strings.ToLower(getFileExtension(filename)))
+	uploadURL, err := s.r2Client.PresignPutURL(key, contentType, size, PresignedUploadTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresignedUpload{
+		UploadURL: uploadURL,
+		PublicURL: s.r2Client.GetPublicURL(key),
+		Key:       key,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: time.Now().Add(PresignedUploadTTL),
+	}, nil
+}
+
+// newObjectKey builds a unique object key under the category prefix
+func newObjectKey(fileType, ext string) string {
+	return fmt.Sprintf("%s/%d_%s%s", fileType, time.Now().Unix(), uuid.New().String()[:8], ext)
+}
+
 func getFileExtension(filename string) string {
 	for i := len(filename) - 1; i >= 0; i-- {
 		if filename[i] == '.' {
@@ -6,7 +6,12 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"weibaobe/internal/models"
@@ -15,11 +20,22 @@ import (
 )
 
 type UserService struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	profileCache *ProfileCache
 }
 
-func NewUserService(db *sqlx.DB) *UserService {
-	return &UserService{db: db}
+func NewUserService(db *sqlx.DB, profileCache *ProfileCache) *UserService {
+	return &UserService{db: db, profileCache: profileCache}
+}
+
+// ProfileCache returns the cache public profile responses are served from
+func (s *UserService) ProfileCache() *ProfileCache {
+	return s.profileCache
+}
+
+// InvalidateProfile drops cached profiles after a profile or follow count changes
+func (s *UserService) InvalidateProfile(userIDs ...string) {
+	s.profileCache.Invalidate(userIDs...)
 }
 
 // GetUserBasicInfo retrieves username, profile image, and role for video creation
@@ -38,7 +54,7 @@ func (s *UserService) GetUser(ctx context.Context, userID string) (*models.User,
 	query := `
 		SELECT uid, name, phone_number, whatsapp_number, profile_image, cover_image, bio, 
 		       user_type, role, followers_count, following_count, videos_count, likes_count,
-		       is_verified, is_active, is_featured, tags,
+		       is_verified, is_active, is_featured, is_private, tags,
 		       created_at, updated_at, last_seen, last_post_at
 		FROM users 
 		WHERE uid = $1 AND is_active = true`
@@ -138,9 +154,304 @@ func (s *UserService) UpdateUserRole(ctx context.Context, userID string, newRole
 		return fmt.Errorf("user not found or inactive: %s", userID)
 	}
 
+	s.profileCache.Invalidate(userID)
 	return nil
 }
 
+// ChangeRole sets a user's role and returns their previous role. role is the source of
+// truth for admin access; the legacy user_type column is kept in step so older readers
+// agree, and a demoted admin loses their admin_permissions grants. Demoting the last
+// active admin is refused with "last_admin"; admin rows are locked first so two
+// concurrent demotions can't both pass the check.
+func (s *UserService) ChangeRole(ctx context.Context, userID string, newRole models.UserRole) (models.UserRole, error) {
+	if !newRole.IsValid() {
+		return "", errors.New("invalid_role")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var activeAdmins []string
+	// user_type is counted too so an admin only flagged there by an older deploy
+	// still blocks the last real admin from being demoted
+	err = tx.SelectContext(ctx, &activeAdmins, `
+		SELECT uid FROM users
+		WHERE (role = $1 OR user_type = $2) AND is_active = true
+		FOR UPDATE`, models.UserRoleAdmin, models.UserTypeAdmin)
+	if err != nil {
+		return "", err
+	}
+
+	var previous models.User
+	err = tx.GetContext(ctx, &previous,
+		"SELECT role, user_type FROM users WHERE uid = $1 FOR UPDATE", userID)
+	if err == sql.ErrNoRows {
+		return "", errors.New("user_not_found")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	previousRole := previous.Role
+
+	if newRole != models.UserRoleAdmin && len(activeAdmins) == 1 && activeAdmins[0] == userID {
+		return "", errors.New("last_admin")
+	}
+
+	userType := previous.UserType
+	switch {
+	case newRole == models.UserRoleAdmin:
+		userType = models.UserTypeAdmin
+	case userType == models.UserTypeAdmin:
+		userType = models.UserTypeUser
+	}
+
+	if previousRole != newRole || userType != previous.UserType {
+		_, err = tx.ExecContext(ctx,
+			"UPDATE users SET role = $2, user_type = $3, updated_at = NOW() WHERE uid = $1",
+			userID, newRole, userType)
+		if err != nil {
+			return "", fmt.Errorf("failed to update user role: %w", err)
+		}
+	}
+
+	if newRole != models.UserRoleAdmin {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM admin_permissions WHERE user_id = $1", userID); err != nil {
+			return "", fmt.Errorf("failed to revoke admin permissions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	s.profileCache.Invalidate(userID)
+	return previousRole, nil
+}
+
+// DeactivateAccount lets a user switch their own account off without deleting anything.
+// The profile and their active videos are hidden from feeds and search until they
+// reactivate.
+func (s *UserService) DeactivateAccount(ctx context.Context, userID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE users SET is_active = false, self_deactivated_at = NOW(), updated_at = NOW()
+		WHERE uid = $1 AND is_active = true`, userID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("user_not_found")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE videos SET is_active = false, owner_deactivated = true, updated_at = NOW()
+		WHERE user_id = $1 AND is_active = true`, userID)
+	if err != nil {
+		return err
+	}
+
+	neighbors, err := syncNeighborFollowCounts(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.profileCache.Invalidate(append(neighbors, userID)...)
+	return nil
+}
+
+// ReactivateAccount restores an account the owner deactivated, along with the videos hidden
+// with it. It reports false when the account is already active and returns
+// "account_suspended" when an admin deactivated it.
+func (s *UserService) ReactivateAccount(ctx context.Context, userID string) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var account struct {
+		IsActive          bool       `db:"is_active"`
+		SelfDeactivatedAt *time.Time `db:"self_deactivated_at"`
+	}
+	err = tx.GetContext(ctx, &account,
+		"SELECT is_active, self_deactivated_at FROM users WHERE uid = $1 FOR UPDATE", userID)
+	if err == sql.ErrNoRows {
+		return false, errors.New("user_not_found")
+	}
+	if err != nil {
+		return false, err
+	}
+	if account.IsActive {
+		return false, nil
+	}
+	if account.SelfDeactivatedAt == nil {
+		return false, errors.New("account_suspended")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users SET is_active = true, self_deactivated_at = NULL, updated_at = NOW()
+		WHERE uid = $1`, userID)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE videos SET is_active = true, owner_deactivated = false, updated_at = NOW()
+		WHERE user_id = $1 AND owner_deactivated = true`, userID)
+	if err != nil {
+		return false, err
+	}
+
+	neighbors, err := syncNeighborFollowCounts(ctx, tx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	s.profileCache.Invalidate(append(neighbors, userID)...)
+	return true, nil
+}
+
+// SyncFollowCounts brings the follow counts of userID's followers and followees in line
+// when an admin changes userID's is_active. Call it in the transaction that makes the
+// change, then invalidate the returned profiles once it commits.
+func (s *UserService) SyncFollowCounts(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	return syncNeighborFollowCounts(ctx, tx, userID)
+}
+
+// GetDemographicsSummary returns the output of get_user_demographics_summary(). The
+// arrays are read as JSON because locations contain commas.
+func (s *UserService) GetDemographicsSummary(ctx context.Context) (*models.UserDemographicsSummary, error) {
+	var summary models.UserDemographicsSummary
+	var locations, languages []byte
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT total_users, male_count, female_count, unspecified_gender_count,
+		       array_to_json(top_locations), array_to_json(top_languages)
+		FROM get_user_demographics_summary()`).Scan(
+		&summary.TotalUsers, &summary.MaleCount, &summary.FemaleCount, &summary.UnspecifiedGenderCount,
+		&locations, &languages,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(locations, &summary.TopLocations); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(languages, &summary.TopLanguages); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetAudienceDemographics aggregates the gender, county and language of a creator's
+// active followers. Counts below models.AudienceMinBucketSize never leave this function,
+// directly or by subtraction from the total: small buckets are folded into "other", and an
+// audience smaller than the minimum is reported as insufficient data.
+func (s *UserService) GetAudienceDemographics(ctx context.Context, creatorID string) (*models.AudienceDemographics, error) {
+	var rows []struct {
+		Dimension string `db:"dimension"`
+		Label     string `db:"label"`
+		Count     int    `db:"count"`
+	}
+
+	// Location is "Ward, Constituency, County"; only the county is reported
+	err := s.db.SelectContext(ctx, &rows, `
+		WITH audience AS (
+			SELECT LOWER(u.gender) AS gender,
+			       NULLIF(TRIM(regexp_replace(u.location, '^.*,', '')), '') AS county,
+			       NULLIF(TRIM(u.language), '') AS language
+			FROM user_follows f
+			JOIN users u ON u.uid = f.follower_id
+			WHERE f.following_id = $1 AND u.is_active = true
+		)
+		SELECT 'gender' AS dimension, COALESCE(gender, 'unspecified') AS label, COUNT(*) AS count
+		FROM audience GROUP BY 2
+		UNION ALL
+		SELECT 'location', COALESCE(county, 'unspecified'), COUNT(*) FROM audience GROUP BY 2
+		UNION ALL
+		SELECT 'language', COALESCE(language, 'unspecified'), COUNT(*) FROM audience GROUP BY 2`,
+		creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]map[string]int{"gender": {}, "location": {}, "language": {}}
+	total := 0
+	for _, row := range rows {
+		counts[row.Dimension][row.Label] = row.Count
+		if row.Dimension == "gender" {
+			total += row.Count
+		}
+	}
+
+	audience := &models.AudienceDemographics{
+		TotalFollowers: total,
+		MinBucketSize:  models.AudienceMinBucketSize,
+		Gender:         []models.AudienceBucket{},
+		Location:       []models.AudienceBucket{},
+		Language:       []models.AudienceBucket{},
+	}
+	if total < models.AudienceMinBucketSize {
+		audience.InsufficientData = true
+		return audience, nil
+	}
+
+	audience.Gender = suppressedBuckets(counts["gender"], total)
+	audience.Location = suppressedBuckets(counts["location"], total)
+	audience.Language = suppressedBuckets(counts["language"], total)
+	return audience, nil
+}
+
+// suppressedBuckets orders buckets by size, folding any below AudienceMinBucketSize into
+// "other". If "other" is then non-empty but still below the minimum, the smallest shown
+// buckets are folded in until it isn't: otherwise subtracting the shown buckets from the
+// total would give the suppressed count back.
+func suppressedBuckets(counts map[string]int, total int) []models.AudienceBucket {
+	buckets := []models.AudienceBucket{}
+	other := 0
+	for label, count := range counts {
+		if count < models.AudienceMinBucketSize {
+			other += count
+			continue
+		}
+		buckets = append(buckets, models.AudienceBucket{Label: label, Count: count})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Label < buckets[j].Label
+	})
+	for other > 0 && other < models.AudienceMinBucketSize && len(buckets) > 0 {
+		other += buckets[len(buckets)-1].Count
+		buckets = buckets[:len(buckets)-1]
+	}
+	if other > 0 {
+		buckets = append(buckets, models.AudienceBucket{Label: "other", Count: other})
+	}
+
+	for i := range buckets {
+		buckets[i].Percent = math.Round(float64(buckets[i].Count)/float64(total)*1000) / 10
+	}
+	return buckets
+}
+
 // NEW: GetUsersByRole retrieves users by role with pagination
 func (s *UserService) GetUsersByRole(ctx context.Context, role models.UserRole, limit, offset int) ([]models.User, error) {
 	if !role.IsValid() {
@@ -193,6 +504,7 @@ func (s *UserService) UpdateUserWhatsApp(ctx context.Context, userID string, wha
 		return fmt.Errorf("user not found or inactive: %s", userID)
 	}
 
+	s.profileCache.Invalidate(userID)
 	return nil
 }
 
@@ -235,6 +547,10 @@ func (s *UserService) CountUsersByRole(ctx context.Context) (map[models.UserRole
 		counts[role] = count
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate role counts: %w", err)
+	}
+
 	return counts, nil
 }
 
@@ -305,6 +621,9 @@ func (s *UserService) BulkUpdateUserRoles(ctx context.Context, userRoleMap map[s
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	for userID := range userRoleMap {
+		s.profileCache.Invalidate(userID)
+	}
 	return nil
 }
 
@@ -342,6 +661,36 @@ func (s *UserService) SearchUsersByRoleAndName(ctx context.Context, role *models
 	return users, err
 }
 
+// SearchUsers matches active users by name or bio for the public universal search,
+// hiding users blocked by or blocking viewerID. Phone numbers are neither matched nor
+// returned, so the search can't be used to look up who owns a number.
+func (s *UserService) SearchUsers(ctx context.Context, query, viewerID string, limit int) ([]models.PublicUser, error) {
+	users := []models.PublicUser{}
+	args := []interface{}{"%" + query + "%", limit}
+
+	blockFilter := ""
+	if viewerID != "" {
+		blockFilter = " AND NOT " + blockedPairExists("uid", 3)
+		args = append(args, viewerID)
+	}
+
+	err := s.db.SelectContext(ctx, &users, `
+		SELECT uid, name, profile_image, cover_image, bio, role,
+		       followers_count, following_count, videos_count, likes_count,
+		       is_verified, is_featured, is_private, tags, created_at, last_post_at
+		FROM users 
+		WHERE is_active = true AND (
+			name ILIKE $1 OR 
+			bio ILIKE $1
+		)`+blockFilter+`
+		ORDER BY 
+			CASE WHEN name ILIKE $1 THEN 1 ELSE 2 END,
+			followers_count DESC,
+			created_at DESC 
+		LIMIT $2`, args...)
+	return users, err
+}
+
 // Enhanced GetUserStats with role and WhatsApp information
 func (s *UserService) GetUserStats(ctx context.Context, userID string) (*models.UserStats, error) {
 	var stats models.UserStats
@@ -6,12 +6,18 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	neturl "net/url"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"weibaobe/internal/config"
+	"weibaobe/internal/logging"
 	"weibaobe/internal/models"
 	"weibaobe/internal/storage"
 
@@ -20,14 +26,70 @@ import (
 )
 
 type VideoService struct {
-	db       *sqlx.DB
-	r2Client *storage.R2Client
+	db        *sqlx.DB
+	r2Client  *storage.R2Client
+	cdnPurger storage.CDNPurger
+	moderator ContentModerator
+
+	// What to do when a new post's media matches an existing video
+	duplicates duplicateUploadPolicy
+
+	searchSafety *SearchSafetyPolicy
+	throttle     *ActionThrottle
+
+	// In-memory cache for trending tags (expensive unnest aggregate)
+	trendingTagsMu    sync.RWMutex
+	trendingTagsCache map[string]trendingTagsCacheEntry
+
+	// Precomputed trending ranking, refreshed by StartTrendingRefresher
+	trendingMu      sync.RWMutex
+	trendingRanking trendingRanking
+
+	// Landing page totals, recomputed at most every publicStatsCacheTTL
+	publicStatsMu sync.Mutex
+	publicStats   *models.PublicStats
+
+	// Videos with an ffprobe duration probe in flight, keyed by video ID
+	durationProbes sync.Map
+}
+
+type trendingRanking struct {
+	videoIDs   []string
+	computedAt time.Time
+	maxAge     time.Duration
 }
 
-func NewVideoService(db *sqlx.DB, r2Client *storage.R2Client) *VideoService {
+type trendingTagsCacheEntry struct {
+	tags      []models.TrendingTag
+	expiresAt time.Time
+}
+
+// Trending tags are recomputed at most this often
+const trendingTagsCacheTTL = 10 * time.Minute
+
+// trendingScoreSQL is the time-decayed engagement score used by every trending ordering.
+// Once a video has enough watch sessions, its average completion rate scales the score
+// between 0.5x (nobody finishes it) and 1.5x (everyone does).
+const trendingScoreSQL = `(
+			CASE 
+				WHEN EXTRACT(EPOCH FROM (NOW() - v.created_at)) > 0 THEN
+					(v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 + v.views_count * 0.1) 
+					/ POWER(EXTRACT(EPOCH FROM (NOW() - v.created_at))/3600 + 1, 1.8)
+				ELSE v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 
+			END
+			* CASE WHEN v.watch_sessions_count >= 10 THEN 0.5 + v.avg_completion_rate ELSE 1.0 END
+		)`
+
+func NewVideoService(db *sqlx.DB, r2Client *storage.R2Client, cdnPurger storage.CDNPurger, moderator ContentModerator, duplicates config.DuplicateUploadConfig, searchSafety *SearchSafetyPolicy, throttle *ActionThrottle) *VideoService {
 	return &VideoService{
-		db:       db,
-		r2Client: r2Client,
+		db:                db,
+		r2Client:          r2Client,
+		cdnPurger:         cdnPurger,
+		moderator:         moderator,
+		duplicates:        newDuplicateUploadPolicy(duplicates),
+		searchSafety:      searchSafety,
+		throttle:          throttle,
+		trendingTagsCache: make(map[string]trendingTagsCacheEntry),
 	}
 }
 
@@ -35,47 +97,168 @@ func NewVideoService(db *sqlx.DB, r2Client *storage.R2Client) *VideoService {
 // URL OPTIMIZATION HELPERS
 // ===============================
 
+// Optimization params are only added when serving a video, never stored. withQueryParams
+// skips params the URL already carries, so rows written with them by older builds don't
+// accumulate duplicates.
 func (s *VideoService) optimizeVideoURL(url string) string {
 	if url == "" {
 		return url
 	}
 
 	if strings.Contains(url, "cloudflare.com") || strings.Contains(url, "r2.cloudflarestorage.com") {
-		if !strings.Contains(url, "?") {
-			return url + "?cf_optimize=true"
-		}
-		return url + "&cf_optimize=true"
+		return withQueryParams(url, "cf_optimize", "true")
 	}
 
-	if !strings.Contains(url, "?") {
-		return url + "?stream=true"
-	}
-	return url + "&stream=true"
+	return withQueryParams(url, "stream", "true")
 }
 
-func (s *VideoService) optimizeThumbnailURL(url string) string {
+// optimizeThumbnailURL resizes CDN images to the width the client asked for (see
+// WithImageWidth). width replaces any stored value, since older rows were saved with 640.
+func (s *VideoService) optimizeThumbnailURL(ctx context.Context, url string) string {
 	if url == "" {
 		return url
 	}
 
 	if strings.Contains(url, "cloudflare.com") {
-		if !strings.Contains(url, "?") {
-			return url + "?format=webp&quality=85&width=640"
-		}
-		return url + "&format=webp&quality=85&width=640"
+		url = withQueryParams(url, "format", "webp", "quality", "85")
+		return setQueryParam(url, "width", imageWidthParam(ctx))
 	}
 
 	return url
 }
 
-func (s *VideoService) applyURLOptimizations(video *models.VideoResponse) {
-	video.VideoURL = s.optimizeVideoURL(video.VideoURL)
-	video.ThumbnailURL = s.optimizeThumbnailURL(video.ThumbnailURL)
-	video.UserImage = s.optimizeThumbnailURL(video.UserImage)
-	video.UserProfileImage = s.optimizeThumbnailURL(video.UserProfileImage)
+// withQueryParams appends key/value pairs to rawURL's query string, skipping keys that
+// are already present. Existing params are left byte-for-byte as they were, so signed
+// URLs stay valid.
+func withQueryParams(rawURL string, keyValues ...string) string {
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	_, query, _ := strings.Cut(base, "?")
+
+	existing, err := neturl.ParseQuery(query)
+	if err != nil {
+		existing = neturl.Values{}
+	}
+
+	var missing []string
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		if !existing.Has(keyValues[i]) {
+			missing = append(missing, neturl.QueryEscape(keyValues[i])+"="+neturl.QueryEscape(keyValues[i+1]))
+		}
+	}
+	if len(missing) == 0 {
+		return rawURL
+	}
+
+	separator := "&"
+	if !strings.Contains(base, "?") {
+		separator = "?"
+	} else if strings.HasSuffix(base, "?") || strings.HasSuffix(base, "&") {
+		separator = ""
+	}
 
-	for i, imageURL := range video.ImageUrls {
-		video.ImageUrls[i] = s.optimizeThumbnailURL(imageURL)
+	result := base + separator + strings.Join(missing, "&")
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
+}
+
+// setQueryParam sets key to value in rawURL's query string, replacing an existing value
+// in place and leaving every other param untouched
+func setQueryParam(rawURL, key, value string) string {
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	path, query, hasQuery := strings.Cut(base, "?")
+	if !hasQuery {
+		return withQueryParams(rawURL, key, value)
+	}
+
+	pair := neturl.QueryEscape(key) + "=" + neturl.QueryEscape(value)
+	params := strings.Split(query, "&")
+	found := false
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := neturl.QueryUnescape(name); err == nil && unescaped == key {
+			params[i] = pair
+			found = true
+		}
+	}
+	if !found {
+		return withQueryParams(rawURL, key, value)
+	}
+
+	result := path + "?" + strings.Join(params, "&")
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
+}
+
+// ===============================
+// TAG NORMALIZATION
+// ===============================
+
+const (
+	maxVideoTags     = 30
+	maxVideoTagRunes = 50
+
+	// Same limit Video.ValidateForCreation enforces
+	maxCaptionLength = 2200
+)
+
+// normalizeCaption trims surrounding whitespace and rejects captions that are empty
+// afterwards ("caption_required") or longer than maxCaptionLength characters
+// ("caption_too_long")
+func normalizeCaption(caption string) (string, error) {
+	caption = strings.TrimSpace(caption)
+	if caption == "" {
+		return "", errors.New("caption_required")
+	}
+	if utf8.RuneCountInString(caption) > maxCaptionLength {
+		return "", errors.New("caption_too_long")
+	}
+	return caption, nil
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags, strips leading '#' and caps
+// each tag's length, so "Dress", " dress " and "#dress" are stored as one tag.
+// More than maxVideoTags distinct tags is rejected.
+func normalizeTags(tags models.StringSlice) (models.StringSlice, error) {
+	normalized := make(models.StringSlice, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		tag = strings.TrimSpace(strings.TrimLeft(tag, "#"))
+		if runes := []rune(tag); len(runes) > maxVideoTagRunes {
+			tag = strings.TrimSpace(string(runes[:maxVideoTagRunes]))
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxVideoTags {
+		return nil, errors.New("too_many_tags")
+	}
+
+	return normalized, nil
+}
+
+func (s *VideoService) applyURLOptimizations(ctx context.Context, video *models.VideoResponse) {
+	video.VideoURL = s.optimizeVideoURL(video.VideoURL)
+	video.ThumbnailURL = s.optimizeThumbnailURL(ctx, video.ThumbnailURL)
+	video.UserImage = s.optimizeThumbnailURL(ctx, video.UserImage)
+	video.UserProfileImage = s.optimizeThumbnailURL(ctx, video.UserProfileImage)
+
+	// Copied rather than rewritten in place so a slice shared with the caller isn't changed
+	if len(video.ImageUrls) > 0 {
+		imageURLs := make(models.StringSlice, len(video.ImageUrls))
+		for i, imageURL := range video.ImageUrls {
+			imageURLs[i] = s.optimizeThumbnailURL(ctx, imageURL)
+		}
+		video.ImageUrls = imageURLs
 	}
 }
 
@@ -111,8 +294,15 @@ func (s *VideoService) ValidateUserCanCreateVideo(ctx context.Context, userID st
 // SIMPLIFIED FUZZY SEARCH
 // ===============================
 
-// FuzzySearch - Simple fuzzy search across username, caption, and tags
-func (s *VideoService) FuzzySearch(ctx context.Context, query string, usernameOnly bool, limit, offset int) ([]models.VideoResponse, int, error) {
+// CheckSearchSafety returns the configured safe response when query matches a sensitive
+// search rule, or nil for a normal query
+func (s *VideoService) CheckSearchSafety(query string) *models.SearchSafetyNotice {
+	return s.searchSafety.Check(query)
+}
+
+// FuzzySearch - Simple fuzzy search across username, caption, and tags. Filters only
+// narrow the matches; ordering is still by relevance.
+func (s *VideoService) FuzzySearch(ctx context.Context, query, viewerID string, usernameOnly bool, filters models.VideoSearchFilters, limit, offset int) ([]models.VideoResponse, int, error) {
 	startTime := time.Now()
 
 	// Sanitize query
@@ -121,13 +311,41 @@ func (s *VideoService) FuzzySearch(ctx context.Context, query string, usernameOn
 		return []models.VideoResponse{}, 0, nil
 	}
 
-	log.Printf("Fuzzy search: query='%s', usernameOnly=%v", cleanQuery, usernameOnly)
-
 	// Build search pattern for fuzzy matching
 	searchPattern := "%" + strings.ToLower(cleanQuery) + "%"
 
 	var searchQuery string
-	var args []interface{}
+	args := []interface{}{cleanQuery, searchPattern, limit, offset}
+
+	// Hide creators the viewer has blocked or been blocked by
+	filterSQL := ""
+	if viewerID != "" {
+		filterSQL = " AND NOT " + blockedPairExists("v.user_id", 5)
+		filterSQL += " AND NOT " + privateAuthorHidden("v.user_id", 5)
+		args = append(args, viewerID)
+	} else {
+		filterSQL = " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	// Price, verification and media type filters; these columns are covered by the
+	// migration 011 indexes
+	if filters.MinPrice != nil {
+		args = append(args, *filters.MinPrice)
+		filterSQL += fmt.Sprintf(" AND v.price >= $%d", len(args))
+	}
+	if filters.MaxPrice != nil {
+		args = append(args, *filters.MaxPrice)
+		filterSQL += fmt.Sprintf(" AND v.price <= $%d", len(args))
+	}
+	if filters.VerifiedOnly {
+		filterSQL += " AND v.is_verified = true"
+	}
+	switch filters.MediaType {
+	case "image":
+		filterSQL += " AND v.is_multiple_images = true"
+	case "video":
+		filterSQL += " AND v.is_multiple_images = false"
+	}
 
 	if usernameOnly {
 		// Search ONLY in username
@@ -139,11 +357,9 @@ func (s *VideoService) FuzzySearch(ctx context.Context, query string, usernameOn
 			       similarity(v.user_name, $1) as relevance
 			FROM videos v
 			WHERE v.is_active = true
-			  AND (LOWER(v.user_name) LIKE $2 OR v.user_name % $1)
+			  AND (LOWER(v.user_name) LIKE $2 OR v.user_name % $1)` + filterSQL + `
 			ORDER BY relevance DESC, v.created_at DESC
 			LIMIT $3 OFFSET $4`
-
-		args = []interface{}{cleanQuery, searchPattern, limit, offset}
 	} else {
 		// Search in username, caption, AND tags (fuzzy matching)
 		searchQuery = `
@@ -165,18 +381,16 @@ func (s *VideoService) FuzzySearch(ctx context.Context, query string, usernameOn
 			    LOWER(v.user_name) LIKE $2 OR v.user_name % $1 OR
 			    LOWER(v.caption) LIKE $2 OR v.caption % $1 OR
 			    LOWER(array_to_string(v.tags, ' ')) LIKE $2
-			  )
+			  )` + filterSQL + `
 			ORDER BY relevance DESC, v.created_at DESC
 			LIMIT $3 OFFSET $4`
-
-		args = []interface{}{cleanQuery, searchPattern, limit, offset}
 	}
 
-	log.Printf("Executing query with pattern: %s", searchPattern)
+	logger := logging.FromContext(ctx).With("query", cleanQuery, "username_only", usernameOnly)
 
 	rows, err := s.db.QueryContext(ctx, searchQuery, args...)
 	if err != nil {
-		log.Printf("Search query failed: %v", err)
+		logger.Error("fuzzy search query failed", "error", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -195,24 +409,24 @@ func (s *VideoService) FuzzySearch(ctx context.Context, query string, usernameOn
 			&relevance,
 		)
 		if err != nil {
-			log.Printf("Row scan error: %v", err)
+			logger.Warn("fuzzy search row scan failed", "error", err)
 			continue
 		}
 
 		// Apply URL optimizations
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 
 		videos = append(videos, video)
 	}
 
 	if err = rows.Err(); err != nil {
-		log.Printf("Rows iteration error: %v", err)
+		logger.Error("fuzzy search iteration failed", "error", err)
 		return videos, len(videos), err
 	}
 
-	duration := time.Since(startTime).Milliseconds()
-	log.Printf("Fuzzy search completed: %d results in %dms", len(videos), duration)
+	logger.Debug("fuzzy search completed",
+		"results", len(videos), "duration_ms", time.Since(startTime).Milliseconds())
 
 	return videos, len(videos), nil
 }
@@ -232,7 +446,7 @@ func (s *VideoService) GetSearchHistory(ctx context.Context, userID string, limi
 
 	rows, err := s.db.QueryContext(ctx, query, userID, limit)
 	if err != nil {
-		log.Printf("Failed to get search history: %v", err)
+		logging.FromContext(ctx).Error("failed to get search history", "user_id", userID, "error", err)
 		return []string{}, nil // Return empty array instead of error
 	}
 	defer rows.Close()
@@ -246,7 +460,10 @@ func (s *VideoService) GetSearchHistory(ctx context.Context, userID string, limi
 		history = append(history, query)
 	}
 
-	log.Printf("Retrieved %d search history items for user %s", len(history), userID)
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("search history iteration failed", "user_id", userID, "error", err)
+	}
+
 	return history, nil
 }
 
@@ -257,6 +474,8 @@ func (s *VideoService) AddSearchHistory(ctx context.Context, userID, query strin
 		return fmt.Errorf("query cannot be empty")
 	}
 
+	logger := logging.FromContext(ctx).With("user_id", userID)
+
 	// Check if table exists, if not create it
 	_, err := s.db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS search_history (
@@ -267,14 +486,14 @@ func (s *VideoService) AddSearchHistory(ctx context.Context, userID, query strin
 			CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE
 		)`)
 	if err != nil {
-		log.Printf("Failed to create search_history table: %v", err)
+		logger.Warn("failed to create search_history table", "error", err)
 	}
 
 	// Create index if not exists
 	_, err = s.db.ExecContext(ctx, `
 		CREATE INDEX IF NOT EXISTS idx_search_history_user_id ON search_history(user_id, created_at DESC)`)
 	if err != nil {
-		log.Printf("Failed to create search_history index: %v", err)
+		logger.Warn("failed to create search_history index", "error", err)
 	}
 
 	// Remove duplicate if exists
@@ -283,7 +502,7 @@ func (s *VideoService) AddSearchHistory(ctx context.Context, userID, query strin
 		WHERE user_id = $1 AND LOWER(query) = LOWER($2)`,
 		userID, cleanQuery)
 	if err != nil {
-		log.Printf("Warning: Failed to remove duplicate: %v", err)
+		logger.Warn("failed to remove duplicate search history", "error", err)
 	}
 
 	// Insert new search
@@ -293,7 +512,7 @@ func (s *VideoService) AddSearchHistory(ctx context.Context, userID, query strin
 
 	_, err = s.db.ExecContext(ctx, insertQuery, userID, cleanQuery, time.Now())
 	if err != nil {
-		log.Printf("Failed to add search history: %v", err)
+		logger.Error("failed to add search history", "error", err)
 		return err
 	}
 
@@ -307,10 +526,9 @@ func (s *VideoService) AddSearchHistory(ctx context.Context, userID, query strin
 			OFFSET 50
 		)`, userID)
 	if err != nil {
-		log.Printf("Warning: Failed to cleanup old history: %v", err)
+		logger.Warn("failed to trim old search history", "error", err)
 	}
 
-	log.Printf("Added search '%s' to history for user %s", cleanQuery, userID)
 	return nil
 }
 
@@ -320,12 +538,12 @@ func (s *VideoService) ClearSearchHistory(ctx context.Context, userID string) er
 
 	result, err := s.db.ExecContext(ctx, query, userID)
 	if err != nil {
-		log.Printf("Failed to clear search history: %v", err)
+		logging.FromContext(ctx).Error("failed to clear search history", "user_id", userID, "error", err)
 		return err
 	}
 
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("Cleared %d search history items for user %s", rowsAffected, userID)
+	logging.FromContext(ctx).Info("cleared search history", "user_id", userID, "removed", rowsAffected)
 	return nil
 }
 
@@ -335,12 +553,12 @@ func (s *VideoService) RemoveSearchHistory(ctx context.Context, userID, searchQu
 
 	result, err := s.db.ExecContext(ctx, query, userID, searchQuery)
 	if err != nil {
-		log.Printf("Failed to remove search history: %v", err)
+		logging.FromContext(ctx).Error("failed to remove search history", "user_id", userID, "error", err)
 		return err
 	}
 
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("Removed %d search history item(s) for user %s", rowsAffected, userID)
+	logging.FromContext(ctx).Debug("removed search history", "user_id", userID, "removed", rowsAffected)
 	return nil
 }
 
@@ -348,6 +566,65 @@ func (s *VideoService) RemoveSearchHistory(ctx context.Context, userID, searchQu
 // POPULAR SEARCH TERMS
 // ===============================
 
+// GetSearchSuggestions returns captions and usernames starting with prefix, with the same
+// visibility rules as FuzzySearch: flagged videos, private accounts the viewer can't see and
+// blocked users are left out. A suggestion the search would withhold is dropped too, since
+// the safety check on the typed prefix says nothing about the completed caption. LIKE
+// wildcards in the prefix are escaped so "50%" completes literally.
+func (s *VideoService) GetSearchSuggestions(ctx context.Context, prefix, viewerID string, limit int) ([]models.SearchSuggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	suggestions := []models.SearchSuggestion{}
+	if prefix == "" {
+		return suggestions, nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+
+	// Over-fetch so suggestions dropped by the safety check don't leave the list short
+	args := []interface{}{escaped, limit * 2}
+	filterSQL := ""
+	if viewerID != "" {
+		filterSQL = " AND NOT " + blockedPairExists("v.user_id", 3)
+		filterSQL += " AND NOT " + privateAuthorHidden("v.user_id", 3)
+		args = append(args, viewerID)
+	} else {
+		filterSQL = " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	var candidates []models.SearchSuggestion
+	err := s.db.SelectContext(ctx, &candidates, `
+		SELECT DISTINCT
+			CASE
+				WHEN v.caption ILIKE $1 || '%' THEN v.caption
+				ELSE v.user_name
+			END AS suggestion,
+			CASE
+				WHEN v.caption ILIKE $1 || '%' THEN 'caption'
+				ELSE 'username'
+			END AS match_type
+		FROM videos v
+		WHERE v.is_active = true
+		  AND v.is_flagged = false
+		  AND (v.caption ILIKE $1 || '%' OR v.user_name ILIKE $1 || '%')
+		  AND LENGTH(COALESCE(v.caption, '')) > 0`+filterSQL+`
+		ORDER BY suggestion
+		LIMIT $2`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suggestion := range candidates {
+		if notice := s.CheckSearchSafety(suggestion.Suggestion); notice != nil && notice.WithholdsResults() {
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
 func (s *VideoService) GetPopularSearchTerms(ctx context.Context, limit int) ([]string, error) {
 	// Try to get from materialized view first
 	query := `
@@ -358,7 +635,7 @@ func (s *VideoService) GetPopularSearchTerms(ctx context.Context, limit int) ([]
 
 	rows, err := s.db.QueryContext(ctx, query, limit)
 	if err != nil {
-		log.Printf("Materialized view not available, using fallback: %v", err)
+		logging.FromContext(ctx).Warn("popular_search_terms view unavailable, using fallback", "error", err)
 		// Fallback to real-time query
 		return s.getPopularSearchTermsFallback(ctx, limit)
 	}
@@ -373,7 +650,10 @@ func (s *VideoService) GetPopularSearchTerms(ctx context.Context, limit int) ([]
 		terms = append(terms, term)
 	}
 
-	log.Printf("Retrieved %d popular search terms", len(terms))
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("popular search terms iteration failed", "error", err)
+	}
+
 	return terms, nil
 }
 
@@ -390,7 +670,7 @@ func (s *VideoService) getPopularSearchTermsFallback(ctx context.Context, limit
 
 	rows, err := s.db.QueryContext(ctx, query, limit)
 	if err != nil {
-		log.Printf("Fallback popular terms query failed: %v", err)
+		logging.FromContext(ctx).Error("fallback popular terms query failed", "error", err)
 		return []string{}, nil
 	}
 	defer rows.Close()
@@ -405,9 +685,145 @@ func (s *VideoService) getPopularSearchTermsFallback(ctx context.Context, limit
 		terms = append(terms, term)
 	}
 
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("fallback popular terms iteration failed", "error", err)
+	}
+
 	return terms, nil
 }
 
+// ===============================
+// 🏷️ TRENDING TAGS
+// ===============================
+
+// GetTrendingTags returns the most used tags over the last 7 days. When weighted is
+// true, tags are ranked by the engagement (likes/comments/shares) of the videos
+// carrying them instead of by raw frequency. Results are cached in memory.
+func (s *VideoService) GetTrendingTags(ctx context.Context, limit int, weighted bool) ([]models.TrendingTag, error) {
+	cacheKey := fmt.Sprintf("%t:%d", weighted, limit)
+
+	s.trendingTagsMu.RLock()
+	entry, ok := s.trendingTagsCache[cacheKey]
+	s.trendingTagsMu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.tags, nil
+	}
+
+	orderBy := "video_count DESC, engagement_score DESC"
+	if weighted {
+		orderBy = "engagement_score DESC, video_count DESC"
+	}
+
+	query := `
+		SELECT 
+			LOWER(t.tag) as tag,
+			COUNT(DISTINCT v.id) as video_count,
+			COALESCE(SUM(v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0), 0) as engagement_score
+		FROM videos v
+		CROSS JOIN LATERAL unnest(v.tags) AS t(tag)
+		WHERE v.is_active = true
+		  AND v.created_at >= NOW() - INTERVAL '7 days'
+		  AND LENGTH(TRIM(t.tag)) > 0
+		GROUP BY LOWER(t.tag)
+		ORDER BY ` + orderBy + `
+		LIMIT $1`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TrendingTag{}
+	for rows.Next() {
+		var tag models.TrendingTag
+		if err := rows.Scan(&tag.Tag, &tag.VideoCount, &tag.EngagementScore); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	s.trendingTagsMu.Lock()
+	s.trendingTagsCache[cacheKey] = trendingTagsCacheEntry{
+		tags:      tags,
+		expiresAt: time.Now().Add(trendingTagsCacheTTL),
+	}
+	s.trendingTagsMu.Unlock()
+
+	return tags, nil
+}
+
+// GetVideosByTag returns active videos carrying the tag (case-insensitive), ordered by
+// recency or, when sortBy is "trending", by the same time-decayed score as the trending feed.
+// Stored tags are normalized by normalizeTags, so the tag is normalized the same way and
+// matched with @> on the GIN index.
+func (s *VideoService) GetVideosByTag(ctx context.Context, tag, sortBy, viewerID string, limit, offset int) ([]models.VideoResponse, error) {
+	normalized, _ := normalizeTags(models.StringSlice{tag})
+	if len(normalized) == 0 {
+		return []models.VideoResponse{}, nil
+	}
+
+	orderBy := "v.created_at DESC"
+	if sortBy == "trending" {
+		orderBy = trendingScoreSQL + " DESC, v.created_at DESC"
+	}
+
+	query := `
+		SELECT 
+			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+			v.created_at, v.updated_at
+		FROM videos v
+		WHERE v.is_active = true
+		  AND v.tags @> ARRAY[$1::text]`
+
+	args := []interface{}{normalized[0], limit, offset}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", 4)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 4)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := []models.VideoResponse{}
+	for rows.Next() {
+		var video models.VideoResponse
+
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+
+		videos = append(videos, video)
+	}
+
+	return videos, rows.Err()
+}
+
 // ===============================
 // OPTIMIZED VIDEO CRUD OPERATIONS
 // ===============================
@@ -452,19 +868,22 @@ func (s *VideoService) GetVideosOptimized(ctx context.Context, params models.Vid
 		argIndex++
 	}
 
+	if params.ViewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", argIndex)
+		query += " AND NOT " + hiddenVideoExists("v.id", argIndex)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", argIndex)
+		args = append(args, params.ViewerID)
+		argIndex++
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
 	// Sorting
 	switch params.SortBy {
 	case "popular":
 		query += " ORDER BY v.likes_count DESC, v.views_count DESC, v.created_at DESC"
 	case "trending":
-		query += ` ORDER BY (
-			CASE 
-				WHEN EXTRACT(EPOCH FROM (NOW() - v.created_at)) > 0 THEN
-					(v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 + v.views_count * 0.1) 
-					/ POWER(EXTRACT(EPOCH FROM (NOW() - v.created_at))/3600 + 1, 1.8)
-				ELSE v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 
-			END
-		) DESC, v.created_at DESC`
+		query += " ORDER BY " + trendingScoreSQL + " DESC, v.created_at DESC"
 	case "views":
 		query += " ORDER BY v.views_count DESC, v.created_at DESC"
 	case "likes":
@@ -504,7 +923,7 @@ func (s *VideoService) GetVideosOptimized(ctx context.Context, params models.Vid
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 		video.IsLiked = false
 		video.IsFollowing = false
@@ -512,10 +931,16 @@ func (s *VideoService) GetVideosOptimized(ctx context.Context, params models.Vid
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
-func (s *VideoService) GetVideosBulk(ctx context.Context, videoIDs []string, includeInactive bool) ([]models.VideoResponse, error) {
+// GetVideosBulk returns the requested videos, leaving out private accounts' videos the
+// viewer may not see. viewerID may be empty for anonymous viewers.
+func (s *VideoService) GetVideosBulk(ctx context.Context, videoIDs []string, viewerID string, includeInactive bool) ([]models.VideoResponse, error) {
 	if len(videoIDs) == 0 {
 		return []models.VideoResponse{}, nil
 	}
@@ -527,7 +952,15 @@ func (s *VideoService) GetVideosBulk(ctx context.Context, videoIDs []string, inc
 			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
 			v.created_at, v.updated_at
 		FROM videos v
-		WHERE v.id = ANY($1::text[])`
+		WHERE v.id = ANY($1::uuid[])`
+
+	args := []interface{}{models.StringSlice(videoIDs)}
+	if viewerID != "" {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 2)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
 
 	if !includeInactive {
 		query += " AND v.is_active = true"
@@ -535,7 +968,7 @@ func (s *VideoService) GetVideosBulk(ctx context.Context, videoIDs []string, inc
 
 	query += " ORDER BY v.created_at DESC"
 
-	rows, err := s.db.QueryContext(ctx, query, videoIDs)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -556,16 +989,20 @@ func (s *VideoService) GetVideosBulk(ctx context.Context, videoIDs []string, inc
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
-func (s *VideoService) GetFeaturedVideosOptimized(ctx context.Context, limit int) ([]models.VideoResponse, error) {
+func (s *VideoService) GetFeaturedVideosOptimized(ctx context.Context, viewerID string, limit int) ([]models.VideoResponse, error) {
 	query := `
 		SELECT 
 			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
@@ -573,11 +1010,22 @@ func (s *VideoService) GetFeaturedVideosOptimized(ctx context.Context, limit int
 			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
 			v.created_at, v.updated_at
 		FROM videos v
-		WHERE v.is_active = true AND v.is_featured = true
+		WHERE v.is_active = true AND v.is_featured = true`
+
+	args := []interface{}{limit}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", 2)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 2)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
 		ORDER BY v.created_at DESC 
 		LIMIT $1`
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -598,34 +1046,54 @@ func (s *VideoService) GetFeaturedVideosOptimized(ctx context.Context, limit int
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
-func (s *VideoService) GetTrendingVideosOptimized(ctx context.Context, limit int) ([]models.VideoResponse, error) {
+// GetTrendingVideosOptimized serves the trending feed from the precomputed ranking when
+// it is warm and large enough, and falls back to scoring every active video otherwise
+func (s *VideoService) GetTrendingVideosOptimized(ctx context.Context, viewerID string, limit int) ([]models.VideoResponse, error) {
+	if videoIDs := s.cachedTrendingIDs(limit); videoIDs != nil {
+		return s.getTrendingVideosFromRanking(ctx, videoIDs, viewerID, limit)
+	}
+	return s.getTrendingVideosLive(ctx, viewerID, limit)
+}
+
+func (s *VideoService) getTrendingVideosLive(ctx context.Context, viewerID string, limit int) ([]models.VideoResponse, error) {
 	query := `
 		SELECT 
 			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
 			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
 			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
 			v.created_at, v.updated_at,
-			CASE 
-				WHEN EXTRACT(EPOCH FROM (NOW() - v.created_at)) > 0 THEN
-					(v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 + v.views_count * 0.1) 
-					/ POWER(EXTRACT(EPOCH FROM (NOW() - v.created_at))/3600 + 1, 1.8)
-				ELSE v.likes_count * 2.5 + v.comments_count * 3.5 + v.shares_count * 5.0 
-			END as trending_score
+			` + trendingScoreSQL + ` as trending_score
 		FROM videos v
-		WHERE v.is_active = true
+		WHERE v.is_active = true`
+
+	args := []interface{}{limit}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("v.user_id", 2)
+		query += " AND NOT " + hiddenVideoExists("v.id", 2)
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 2)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
 		ORDER BY trending_score DESC, v.created_at DESC 
 		LIMIT $1`
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -648,39 +1116,57 @@ func (s *VideoService) GetTrendingVideosOptimized(ctx context.Context, limit int
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
-func (s *VideoService) GetVideoOptimized(ctx context.Context, videoID string) (*models.VideoResponse, error) {
+// GetVideoOptimized returns an active video and counts a view. A private account's video
+// is only returned to the owner and approved followers; viewerID may be empty for
+// anonymous viewers, otherwise IsSaved reports whether they bookmarked it.
+func (s *VideoService) GetVideoOptimized(ctx context.Context, videoID, viewerID string) (*models.VideoResponse, error) {
+	isSaved := "false"
+	visibility := privateAuthorHidden("v.user_id", 0)
+	args := []interface{}{videoID}
+	if viewerID != "" {
+		isSaved = "EXISTS (SELECT 1 FROM saved_videos sv WHERE sv.video_id = v.id AND sv.user_id = $2)"
+		visibility = privateAuthorHidden("v.user_id", 2)
+		args = append(args, viewerID)
+	}
+
 	query := `
 		SELECT 
 			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
 			v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
 			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
-			v.created_at, v.updated_at
+			v.created_at, v.updated_at, ` + isSaved + ` AS is_saved
 		FROM videos v
-		WHERE v.id = $1 AND v.is_active = true`
+		WHERE v.id = $1 AND v.is_active = true
+		  AND NOT ` + visibility
 
 	var video models.VideoResponse
 
-	err := s.db.QueryRowContext(ctx, query, videoID).Scan(
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
 		&video.ID, &video.UserID, &video.UserName, &video.UserImage,
 		&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
 		&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
 		&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
 		&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		&video.IsSaved,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	s.applyURLOptimizations(&video)
+	s.applyURLOptimizations(ctx, &video)
 	video.UserProfileImage = video.UserImage
 
 	// Async view increment
@@ -693,7 +1179,9 @@ func (s *VideoService) GetVideoOptimized(ctx context.Context, videoID string) (*
 	return &video, nil
 }
 
-func (s *VideoService) GetUserVideosOptimized(ctx context.Context, userID string, limit, offset int) ([]models.VideoResponse, error) {
+// GetUserVideosOptimized returns userID's active videos, newest first. A private account's
+// videos are only listed for the owner and approved followers.
+func (s *VideoService) GetUserVideosOptimized(ctx context.Context, userID, viewerID string, limit, offset int) ([]models.VideoResponse, error) {
 	query := `
 		SELECT 
 			v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
@@ -701,11 +1189,21 @@ func (s *VideoService) GetUserVideosOptimized(ctx context.Context, userID string
 			v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
 			v.created_at, v.updated_at
 		FROM videos v
-		WHERE v.user_id = $1 AND v.is_active = true
+		WHERE v.user_id = $1 AND v.is_active = true`
+
+	args := []interface{}{userID, limit, offset}
+	if viewerID != "" {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 4)
+		args = append(args, viewerID)
+	} else {
+		query += " AND NOT " + privateAuthorHidden("v.user_id", 0)
+	}
+
+	query += `
 		ORDER BY v.created_at DESC 
 		LIMIT $2 OFFSET $3`
 
-	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -726,12 +1224,16 @@ func (s *VideoService) GetUserVideosOptimized(ctx context.Context, userID string
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
@@ -768,25 +1270,100 @@ func (s *VideoService) GetUserLikedVideosOptimized(ctx context.Context, userID s
 			return nil, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 		video.IsLiked = true
 
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return videos, nil
 }
 
-func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.Video) (string, error) {
-	user, err := s.ValidateUserCanCreateVideo(ctx, video.UserID)
-	if err != nil {
-		return "", fmt.Errorf("video creation validation failed: %w", err)
-	}
+func (s *VideoService) GetUserSavedVideosOptimized(ctx context.Context, userID string, limit, offset int) ([]models.VideoResponse, error) {
+	query := `
+		SELECT v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
+		       v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
+		       v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
+		       v.created_at, v.updated_at
+		FROM videos v
+		JOIN saved_videos sv ON v.id = sv.video_id
+		WHERE sv.user_id = $1 AND v.is_active = true
+		ORDER BY sv.created_at DESC
+		LIMIT $2 OFFSET $3`
 
-	if !video.IsValidForCreation() {
-		errors := video.ValidateForCreation()
-		return "", fmt.Errorf("validation failed: %v", errors)
+	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.VideoResponse
+	for rows.Next() {
+		var video models.VideoResponse
+
+		err := rows.Scan(
+			&video.ID, &video.UserID, &video.UserName, &video.UserImage,
+			&video.VideoURL, &video.ThumbnailURL, &video.Caption, &video.Price,
+			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
+			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
+			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.applyURLOptimizations(ctx, &video)
+		video.UserProfileImage = video.UserImage
+		video.IsSaved = true
+
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.Video) (string, error) {
+	user, err := s.ValidateUserCanCreateVideo(ctx, video.UserID)
+	if err != nil {
+		return "", fmt.Errorf("video creation validation failed: %w", err)
+	}
+
+	video.Caption, err = normalizeCaption(video.Caption)
+	if err != nil {
+		return "", err
+	}
+
+	if len(video.ImageUrls) > models.GetMaxImagesPerPost() {
+		return "", errors.New("too_many_images")
+	}
+
+	if !video.IsValidForCreation() {
+		errors := video.ValidateForCreation()
+		return "", fmt.Errorf("validation failed: %v", errors)
+	}
+
+	tags, err := normalizeTags(video.Tags)
+	if err != nil {
+		return "", err
+	}
+	video.Tags = tags
+
+	video.IsFlagged, video.FlagReason, err = s.moderateText(ctx, video.Caption)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkDuplicateContent(ctx, video); err != nil {
+		return "", err
 	}
 
 	video.ID = uuid.New().String()
@@ -805,9 +1382,6 @@ func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.V
 	video.UserName = user.Name
 	video.UserImage = user.ProfileImage
 
-	video.VideoURL = s.optimizeVideoURL(video.VideoURL)
-	video.ThumbnailURL = s.optimizeThumbnailURL(video.ThumbnailURL)
-
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to start transaction: %w", err)
@@ -820,25 +1394,17 @@ func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.V
 			id, user_id, user_name, user_image, video_url, thumbnail_url,
 			caption, price, likes_count, comments_count, views_count, shares_count,
 			tags, is_active, is_featured, is_verified, is_multiple_images, image_urls,
-			created_at, updated_at
+			created_at, updated_at, is_flagged, flag_reason, video_content_hash
 		) VALUES (
 			$1, $2, $3, $4, $5, $6,
 			$7, $8, $9, $10, $11, $12,
 			$13, $14, $15, $16, $17, $18,
-			$19, $20
+			$19, $20, $21, $22, $23
 		)`
 
-	log.Printf("🔍 ATTEMPTING VIDEO INSERT:")
-	log.Printf("   Video ID: %s", video.ID)
-	log.Printf("   User ID: %s", video.UserID)
-	log.Printf("   User Name: %s", video.UserName)
-	log.Printf("   Caption: %s", video.Caption)
-	log.Printf("   Price: %f", video.Price)
-	log.Printf("   Tags: %v (type: %T)", video.Tags, video.Tags)
-	log.Printf("   ImageUrls: %v (type: %T)", video.ImageUrls, video.ImageUrls)
-	log.Printf("   IsMultipleImages: %v", video.IsMultipleImages)
-	log.Printf("   VideoURL: %s", video.VideoURL)
-	log.Printf("   ThumbnailURL: %s", video.ThumbnailURL)
+	logger := logging.FromContext(ctx).With("video_id", video.ID, "user_id", video.UserID)
+	logger.Debug("inserting video",
+		"tags", len(video.Tags), "images", len(video.ImageUrls), "multiple_images", video.IsMultipleImages)
 
 	_, err = tx.ExecContext(ctx, query,
 		video.ID,
@@ -861,22 +1427,15 @@ func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.V
 		video.ImageUrls,
 		video.CreatedAt,
 		video.UpdatedAt,
+		video.IsFlagged,
+		video.FlagReason,
+		video.ContentHash,
 	)
 	if err != nil {
-		log.Printf("❌ DATABASE INSERT ERROR: %v", err)
-		log.Printf("❌ Error Type: %T", err)
-		log.Printf("❌ Full Error Details: %+v", err)
-		log.Printf("📄 FAILED Video ID: %s", video.ID)
-		log.Printf("📄 FAILED User ID: %s", video.UserID)
-		log.Printf("📄 FAILED Tags: %v (type: %T)", video.Tags, video.Tags)
-		log.Printf("📄 FAILED ImageUrls: %v (type: %T)", video.ImageUrls, video.ImageUrls)
-		log.Printf("📄 Full Video Data: %+v", video)
+		logger.Error("video insert failed", "error", err)
 		return "", fmt.Errorf("failed to insert video: %w", err)
 	}
 
-	log.Printf("✅ VIDEO INSERTED SUCCESSFULLY: %s", video.ID)
-
-	log.Printf("🔄 UPDATING USER LAST_POST_AT for user: %s", video.UserID)
 	updateTime := time.Now()
 	_, err = tx.ExecContext(ctx, `
 		UPDATE users 
@@ -884,20 +1443,21 @@ func (s *VideoService) CreateVideoOptimized(ctx context.Context, video *models.V
 		WHERE uid = $3`,
 		updateTime, updateTime, video.UserID)
 	if err != nil {
-		log.Printf("❌ USER UPDATE ERROR: %v", err)
-		log.Printf("❌ Failed to update last_post_at for user: %s", video.UserID)
+		logger.Error("failed to update user last_post_at", "error", err)
 		return "", fmt.Errorf("failed to update user last post: %w", err)
 	}
-	log.Printf("✅ USER LAST_POST_AT UPDATED SUCCESSFULLY")
 
-	log.Printf("🔄 COMMITTING TRANSACTION...")
 	if err = tx.Commit(); err != nil {
-		log.Printf("❌ TRANSACTION COMMIT ERROR: %v", err)
+		logger.Error("video create commit failed", "error", err)
 		return "", fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	log.Printf("✅ TRANSACTION COMMITTED SUCCESSFULLY")
 
-	log.Printf("🎉 VIDEO CREATION COMPLETED: %s", video.ID)
+	logger.Info("video created")
+
+	// Watch sessions are measured against the stored duration
+	if !video.IsMultipleImages {
+		go s.probeVideoDuration(video.ID, video.VideoURL)
+	}
 	return video.ID, nil
 }
 
@@ -910,14 +1470,19 @@ func (s *VideoService) incrementViewCountOptimized(videoID string) {
 	for i := 0; i < maxRetries; i++ {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 
+		// The daily bucket feeds period trending; it only counts if the video was updated
 		query := `
-			UPDATE videos 
-			SET views_count = views_count + 1, updated_at = $1 
-			WHERE id = $2 AND is_active = true 
-			RETURNING views_count`
-
-		var newCount int
-		err := s.db.QueryRowContext(ctx, query, time.Now(), videoID).Scan(&newCount)
+			WITH viewed AS (
+				UPDATE videos 
+				SET views_count = views_count + 1, updated_at = $1 
+				WHERE id = $2 AND is_active = true 
+				RETURNING id
+			)
+			INSERT INTO video_daily_engagement (video_id, day, views)
+			SELECT id, CURRENT_DATE, 1 FROM viewed
+			ON CONFLICT (video_id, day) DO UPDATE SET views = video_daily_engagement.views + 1`
+
+		_, err := s.db.ExecContext(ctx, query, time.Now(), videoID)
 		cancel()
 
 		if err == nil {
@@ -927,7 +1492,7 @@ func (s *VideoService) incrementViewCountOptimized(videoID string) {
 		time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
 	}
 
-	log.Printf("Failed to increment view count for video %s after %d retries", videoID, maxRetries)
+	slog.Warn("failed to increment view count", "video_id", videoID, "retries", maxRetries)
 }
 
 func (s *VideoService) IncrementVideoViews(ctx context.Context, videoID string) error {
@@ -936,6 +1501,10 @@ func (s *VideoService) IncrementVideoViews(ctx context.Context, videoID string)
 }
 
 func (s *VideoService) LikeVideo(ctx context.Context, videoID, userID string) error {
+	if err := s.throttle.check(actionLike, userID); err != nil {
+		return err
+	}
+
 	var exists int
 	err := s.db.QueryRowContext(ctx,
 		"SELECT COUNT(*) FROM video_likes WHERE video_id = $1 AND user_id = $2",
@@ -973,6 +1542,93 @@ func (s *VideoService) UnlikeVideo(ctx context.Context, videoID, userID string)
 	return nil
 }
 
+// ===============================
+// SAVED VIDEOS (private bookmarks)
+// ===============================
+
+func (s *VideoService) SaveVideo(ctx context.Context, videoID, userID string) error {
+	var videoExists bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM videos WHERE id = $1 AND is_active = true)",
+		videoID).Scan(&videoExists)
+	if err != nil {
+		return err
+	}
+	if !videoExists {
+		return errors.New("video_not_found")
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO saved_videos (id, video_id, user_id, created_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (video_id, user_id) DO NOTHING`,
+		uuid.New().String(), videoID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("already_saved")
+	}
+
+	return nil
+}
+
+func (s *VideoService) UnsaveVideo(ctx context.Context, videoID, userID string) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM saved_videos WHERE video_id = $1 AND user_id = $2",
+		videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("not_saved")
+	}
+
+	return nil
+}
+
+// MarkSavedVideos sets IsSaved on the videos the viewer has bookmarked (single query)
+func (s *VideoService) MarkSavedVideos(ctx context.Context, viewerID string, videos []models.VideoResponse) error {
+	if viewerID == "" || len(videos) == 0 {
+		return nil
+	}
+
+	videoIDs := make(models.StringSlice, len(videos))
+	for i, video := range videos {
+		videoIDs[i] = video.ID
+	}
+
+	var savedIDs []string
+	err := s.db.SelectContext(ctx, &savedIDs,
+		"SELECT video_id::text FROM saved_videos WHERE user_id = $1 AND video_id = ANY($2::uuid[])",
+		viewerID, videoIDs)
+	if err != nil {
+		return err
+	}
+
+	saved := make(map[string]bool, len(savedIDs))
+	for _, id := range savedIDs {
+		saved[id] = true
+	}
+
+	for i := range videos {
+		videos[i].IsSaved = saved[videos[i].ID]
+	}
+
+	return nil
+}
+
 func (s *VideoService) GetVideoCountsSummary(ctx context.Context, videoID string) (*models.VideoCountsSummary, error) {
 	query := `
 		SELECT 
@@ -993,41 +1649,48 @@ func (s *VideoService) GetVideoCountsSummary(ctx context.Context, videoID string
 	return &summary, err
 }
 
-func (s *VideoService) BatchUpdateViewCounts(ctx context.Context) error {
-	query := `
-		WITH updated_counts AS (
-			UPDATE videos SET 
-				likes_count = (
-					SELECT COUNT(*) 
-					FROM video_likes 
-					WHERE video_likes.video_id = videos.id
-				),
-				comments_count = (
-					SELECT COUNT(*) 
-					FROM comments 
-					WHERE comments.video_id = videos.id
-				),
-				updated_at = NOW()
-			WHERE is_active = true
-			RETURNING id
-		)
-		SELECT COUNT(*) FROM updated_counts`
+func (s *VideoService) UpdateVideo(ctx context.Context, video *models.Video) error {
+	video.UpdatedAt = time.Now()
 
-	var updatedCount int
-	err := s.db.QueryRowContext(ctx, query).Scan(&updatedCount)
+	caption, err := normalizeCaption(video.Caption)
 	if err != nil {
 		return err
 	}
+	video.Caption = caption
 
-	log.Printf("Updated counts for %d videos", updatedCount)
-	return nil
-}
+	tags, err := normalizeTags(video.Tags)
+	if err != nil {
+		return err
+	}
+	video.Tags = tags
 
-func (s *VideoService) UpdateVideo(ctx context.Context, video *models.Video) error {
-	video.UpdatedAt = time.Now()
+	video.IsFlagged, video.FlagReason, err = s.moderateText(ctx, video.Caption)
+	if err != nil {
+		return err
+	}
 
-	video.VideoURL = s.optimizeVideoURL(video.VideoURL)
-	video.ThumbnailURL = s.optimizeThumbnailURL(video.ThumbnailURL)
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Remember the current media URLs so replaced assets can be purged from the CDN,
+	// and the current price so a free-to-paid change can grandfather existing viewers
+	var previous struct {
+		VideoURL     string  `db:"video_url"`
+		ThumbnailURL string  `db:"thumbnail_url"`
+		Price        float64 `db:"price"`
+	}
+	err = tx.GetContext(ctx, &previous,
+		"SELECT video_url, thumbnail_url, price FROM videos WHERE id = $1 AND user_id = $2 FOR UPDATE",
+		video.ID, video.UserID)
+	if err == sql.ErrNoRows {
+		return errors.New("video_not_found_or_no_access")
+	}
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE videos SET 
@@ -1039,10 +1702,12 @@ func (s *VideoService) UpdateVideo(ctx context.Context, video *models.Video) err
 			is_featured = :is_featured,
 			is_verified = :is_verified,
 			is_active = :is_active,
+			is_flagged = :is_flagged,
+			flag_reason = :flag_reason,
 			updated_at = :updated_at
 		WHERE id = :id AND user_id = :user_id`
 
-	result, err := s.db.NamedExecContext(ctx, query, video)
+	result, err := tx.NamedExecContext(ctx, query, video)
 	if err != nil {
 		return err
 	}
@@ -1056,9 +1721,66 @@ func (s *VideoService) UpdateVideo(ctx context.Context, video *models.Video) err
 		return errors.New("video_not_found_or_no_access")
 	}
 
+	if previous.Price <= 0 && video.Price > 0 {
+		if err := grantExistingViewerAccess(ctx, tx, video.ID, video.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	var stale []string
+	if previous.VideoURL != "" && previous.VideoURL != video.VideoURL {
+		stale = append(stale, previous.VideoURL)
+	}
+	if previous.ThumbnailURL != "" && previous.ThumbnailURL != video.ThumbnailURL {
+		stale = append(stale, previous.ThumbnailURL)
+	}
+	s.purgeCDN(stale)
+
 	return nil
 }
 
+// grantExistingViewerAccess records zero-price entitlements for everyone who already
+// watched, liked or saved a video while it was free, so putting a price on it later does
+// not lock them out. Access is always decided by video_purchases, never the current price.
+func grantExistingViewerAccess(ctx context.Context, tx *sqlx.Tx, videoID, ownerID string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO video_purchases (user_id, video_id, price_paid)
+		SELECT u.uid, $1, 0
+		FROM users u
+		WHERE u.uid != $2
+		  AND u.uid IN (
+			SELECT ws.user_id FROM video_watch_sessions ws WHERE ws.video_id = $1 AND ws.user_id IS NOT NULL
+			UNION
+			SELECT vl.user_id FROM video_likes vl WHERE vl.video_id = $1
+			UNION
+			SELECT sv.user_id FROM saved_videos sv WHERE sv.video_id = $1
+		  )
+		ON CONFLICT (user_id, video_id) DO NOTHING`,
+		videoID, ownerID)
+	return err
+}
+
+// purgeCDN evicts replaced media URLs in the background. Failures are logged only;
+// the cached copy simply expires on its own.
+func (s *VideoService) purgeCDN(urls []string) {
+	if s.cdnPurger == nil || len(urls) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := s.cdnPurger.PurgeURLs(ctx, urls); err != nil {
+			slog.Warn("CDN purge failed", "urls", urls, "error", err)
+		}
+	}()
+}
+
 func (s *VideoService) DeleteVideo(ctx context.Context, videoID, userID string) error {
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -1098,11 +1820,54 @@ func (s *VideoService) CheckVideoLiked(ctx context.Context, videoID, userID stri
 	return count > 0, err
 }
 
+// GetVideoLikers returns a page of active users who liked videoID, most recent like first,
+// and the total number of such users. Users the viewer has blocked or been blocked by are
+// left out, and a private account's video has no visible likers for non-followers. Likers
+// are returned as public profiles without contact fields.
+func (s *VideoService) GetVideoLikers(ctx context.Context, videoID, viewerID string, limit, offset int) ([]models.PublicUser, int, error) {
+	query := `
+		SELECT u.uid, u.name, u.profile_image, u.cover_image, u.bio, u.role,
+		       u.followers_count, u.following_count, u.videos_count, u.likes_count,
+		       u.is_verified, u.is_featured, u.is_private, u.tags, u.created_at, u.last_post_at,
+		       COUNT(*) OVER() as total_count
+		FROM video_likes vl
+		JOIN videos v ON v.id = vl.video_id AND v.is_active = true
+		JOIN users u ON u.uid = vl.user_id AND u.is_active = true
+		WHERE vl.video_id = $1
+		  AND NOT ` + privateAuthorHidden("v.user_id", 2) + `
+		  AND NOT ` + blockedPairExists("u.uid", 2) + `
+		ORDER BY vl.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	var rows []struct {
+		models.PublicUser
+		TotalCount int `db:"total_count"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, query, videoID, viewerID, limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]models.PublicUser, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		users = append(users, row.PublicUser)
+		total = row.TotalCount
+	}
+
+	return users, total, nil
+}
+
 func (s *VideoService) IncrementVideoShares(ctx context.Context, videoID string) error {
 	query := `
-		UPDATE videos 
-		SET shares_count = shares_count + 1, updated_at = $1 
-		WHERE id = $2 AND is_active = true`
+		WITH shared AS (
+			UPDATE videos 
+			SET shares_count = shares_count + 1, updated_at = $1 
+			WHERE id = $2 AND is_active = true
+			RETURNING id
+		)
+		INSERT INTO video_daily_engagement (video_id, day, shares)
+		SELECT id, CURRENT_DATE, 1 FROM shared
+		ON CONFLICT (video_id, day) DO UPDATE SET shares = video_daily_engagement.shares + 1`
 
 	_, err := s.db.ExecContext(ctx, query, time.Now(), videoID)
 	return err
@@ -1116,39 +1881,274 @@ func (s *VideoService) CreateComment(ctx context.Context, comment *models.Commen
 	if errors := comment.ValidateForCreation(); len(errors) > 0 {
 		return "", fmt.Errorf("validation failed: %v", errors)
 	}
+	if err := s.throttle.check(actionComment, comment.AuthorID); err != nil {
+		return "", err
+	}
+
+	// Commenters cannot reach creators they have blocked or been blocked by
+	var blocked bool
+	err := s.db.GetContext(ctx, &blocked,
+		`SELECT EXISTS (SELECT 1 FROM videos v WHERE v.id = $1 AND `+blockedPairExists("v.user_id", 2)+`)`,
+		comment.VideoID, comment.AuthorID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", errors.New("user_blocked")
+	}
+
+	comment.IsFlagged, comment.FlagReason, err = s.moderateText(ctx, comment.Content)
+	if err != nil {
+		return "", err
+	}
+
+	mentions, err := s.resolveMentions(ctx, comment.Content, comment.AuthorID)
+	if err != nil {
+		// Mentions are best effort; the comment is still posted
+		logging.FromContext(ctx).Warn("failed to resolve comment mentions", "author_id", comment.AuthorID, "error", err)
+		mentions = models.StringSlice{}
+	}
 
 	comment.ID = uuid.New().String()
 	comment.CreatedAt = time.Now()
 	comment.UpdatedAt = time.Now()
 	comment.LikesCount = 0
+	comment.Mentions = mentions
 
 	query := `
 		INSERT INTO comments (
 			id, video_id, author_id, author_name, author_image, content,
 			likes_count, is_reply, replied_to_comment_id, replied_to_author_name,
-			created_at, updated_at
+			mentions, is_flagged, flag_reason, created_at, updated_at
 		) VALUES (
 			:id, :video_id, :author_id, :author_name, :author_image, :content,
 			:likes_count, :is_reply, :replied_to_comment_id, :replied_to_author_name,
-			:created_at, :updated_at
+			:mentions, :is_flagged, :flag_reason, :created_at, :updated_at
 		)`
 
-	_, err := s.db.NamedExecContext(ctx, query, comment)
+	_, err = s.db.NamedExecContext(ctx, query, comment)
 	return comment.ID, err
 }
 
-func (s *VideoService) GetVideoComments(ctx context.Context, videoID string, limit, offset int) ([]models.Comment, error) {
+// resolveMentions maps @handles in content to active user UIDs. Unknown or inactive
+// handles, the author, and users blocked with the author are silently skipped.
+func (s *VideoService) resolveMentions(ctx context.Context, content, authorID string) (models.StringSlice, error) {
+	handles := models.ExtractMentions(content, models.MaxCommentMentions)
+	if len(handles) == 0 {
+		return models.StringSlice{}, nil
+	}
+
+	query := `
+		SELECT DISTINCT ON (LOWER(REPLACE(u.name, ' ', '_'))) u.uid
+		FROM users u
+		WHERE u.is_active = true
+		  AND LOWER(REPLACE(u.name, ' ', '_')) = ANY($1::text[])
+		  AND u.uid <> $2
+		  AND NOT ` + blockedPairExists("u.uid", 2) + `
+		ORDER BY LOWER(REPLACE(u.name, ' ', '_')), u.is_verified DESC, u.followers_count DESC`
+
+	uids := models.StringSlice{}
+	if err := s.db.SelectContext(ctx, &uids, query, models.StringSlice(handles), authorID); err != nil {
+		return nil, err
+	}
+
+	return uids, nil
+}
+
+// commentIsLikedSQL selects whether the viewer (the given arg) liked each comment
+func commentIsLikedSQL(viewerArg int) string {
+	return fmt.Sprintf(`EXISTS(
+			SELECT 1 FROM comment_likes cl
+			WHERE cl.comment_id = comments.id AND cl.user_id = $%d
+		) AS is_liked`, viewerArg)
+}
+
+// GetVideoComments returns a page of a video's comments. sortBy is "newest" (default),
+// "oldest" or "top" (most liked first, newest breaking ties); the pinned comment always
+// comes first. When viewerID is set, comments from users blocked by or blocking the
+// viewer are left out.
+func (s *VideoService) GetVideoComments(ctx context.Context, videoID, viewerID, sortBy string, limit, offset int) ([]models.Comment, error) {
+	orderBy := "created_at DESC"
+	switch sortBy {
+	case "oldest":
+		orderBy = "created_at ASC"
+	case "top":
+		orderBy = "likes_count DESC, created_at DESC"
+	}
+
 	query := `
-		SELECT * FROM comments 
-		WHERE video_id = $1 
-		ORDER BY created_at DESC 
+		SELECT comments.*, ` + commentIsLikedSQL(4) + `
+		FROM comments 
+		WHERE video_id = $1`
+
+	args := []interface{}{videoID, limit, offset, viewerID}
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("comments.author_id", 4)
+	}
+
+	query += `
+		ORDER BY is_pinned DESC, ` + orderBy + `
 		LIMIT $2 OFFSET $3`
 
 	var comments []models.Comment
-	err := s.db.SelectContext(ctx, &comments, query, videoID, limit, offset)
+	err := s.db.SelectContext(ctx, &comments, query, args...)
 	return comments, err
 }
 
+// GetCommentReplies returns a page of replies to a comment, oldest first, with the
+// viewer's like state and the total reply count. A malformed ID returns "invalid_id".
+func (s *VideoService) GetCommentReplies(ctx context.Context, commentID, viewerID string, limit, offset int) ([]models.Comment, int, error) {
+	if !validUUIDs(commentID) {
+		return nil, 0, errors.New("invalid_id")
+	}
+
+	var exists bool
+	err := s.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM comments WHERE id = $1)", commentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, errors.New("comment_not_found")
+	}
+
+	query := `
+		SELECT comments.*, ` + commentIsLikedSQL(4) + `,
+		       COUNT(*) OVER() AS total_count
+		FROM comments
+		WHERE replied_to_comment_id = $1`
+	if viewerID != "" {
+		query += " AND NOT " + blockedPairExists("comments.author_id", 4)
+	}
+	query += `
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := s.db.QueryxContext(ctx, query, commentID, limit, offset, viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	replies := []models.Comment{}
+	total := 0
+	for rows.Next() {
+		var row struct {
+			models.Comment
+			TotalCount int `db:"total_count"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, 0, err
+		}
+		replies = append(replies, row.Comment)
+		total = row.TotalCount
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return replies, total, nil
+}
+
+// PinComment pins a top-level comment on videoID, replacing any comment already pinned.
+// Only the video's owner may pin. Malformed IDs return "invalid_id".
+func (s *VideoService) PinComment(ctx context.Context, videoID, commentID, userID string) error {
+	if !validUUIDs(videoID, commentID) {
+		return errors.New("invalid_id")
+	}
+	if err := s.checkVideoOwner(ctx, videoID, userID); err != nil {
+		return err
+	}
+
+	var isReply bool
+	err := s.db.GetContext(ctx, &isReply,
+		"SELECT is_reply FROM comments WHERE id = $1 AND video_id = $2", commentID, videoID)
+	if err == sql.ErrNoRows {
+		return errors.New("comment_not_found")
+	}
+	if err != nil {
+		return err
+	}
+	if isReply {
+		return errors.New("cannot_pin_reply")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Unpin first so the one-pin-per-video index never sees two
+	_, err = tx.ExecContext(ctx,
+		"UPDATE comments SET is_pinned = false WHERE video_id = $1 AND is_pinned = true AND id <> $2",
+		videoID, commentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE comments SET is_pinned = true WHERE id = $1", commentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UnpinComment removes the pin from a comment on videoID. Only the video's owner may unpin.
+// Malformed IDs return "invalid_id".
+func (s *VideoService) UnpinComment(ctx context.Context, videoID, commentID, userID string) error {
+	if !validUUIDs(videoID, commentID) {
+		return errors.New("invalid_id")
+	}
+	if err := s.checkVideoOwner(ctx, videoID, userID); err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE comments SET is_pinned = false WHERE id = $1 AND video_id = $2 AND is_pinned = true",
+		commentID, videoID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("comment_not_pinned")
+	}
+	return nil
+}
+
+// validUUIDs reports whether every id parses as a UUID, so malformed input is rejected
+// before it reaches a uuid column comparison
+func validUUIDs(ids ...string) bool {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// checkVideoOwner returns "video_not_found" or "access_denied" unless userID owns videoID
+func (s *VideoService) checkVideoOwner(ctx context.Context, videoID, userID string) error {
+	var ownerID string
+	err := s.db.GetContext(ctx, &ownerID, "SELECT user_id FROM videos WHERE id = $1 AND is_active = true", videoID)
+	if err == sql.ErrNoRows {
+		return errors.New("video_not_found")
+	}
+	if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return errors.New("access_denied")
+	}
+	return nil
+}
+
 func (s *VideoService) DeleteComment(ctx context.Context, commentID, userID string) error {
 	var authorID string
 	err := s.db.QueryRowContext(ctx, "SELECT author_id FROM comments WHERE id = $1", commentID).Scan(&authorID)
@@ -1227,25 +2227,53 @@ func (s *VideoService) UnlikeComment(ctx context.Context, commentID, userID stri
 // SOCIAL OPERATIONS
 // ===============================
 
-func (s *VideoService) FollowUser(ctx context.Context, followerID, followingID string) error {
+// FollowUser follows followingID, or for a private account sends a follow request instead.
+// It reports true when a request was sent rather than a follow created.
+func (s *VideoService) FollowUser(ctx context.Context, followerID, followingID string) (bool, error) {
 	if followerID == followingID {
-		return errors.New("cannot_follow_self")
+		return false, errors.New("cannot_follow_self")
+	}
+	if err := s.throttle.check(actionFollow, followerID); err != nil {
+		return false, err
 	}
 
 	var exists int
 	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_follows WHERE follower_id = $1 AND following_id = $2", followerID, followingID).Scan(&exists)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if exists > 0 {
-		return errors.New("already_following")
+		return false, errors.New("already_following")
+	}
+
+	var target struct {
+		IsPrivate bool `db:"is_private"`
+		Blocked   bool `db:"blocked"`
+	}
+	err = s.db.GetContext(ctx, &target,
+		`SELECT u.is_private, `+blockedPairExists("u.uid", 1)+` AS blocked
+		 FROM users u WHERE u.uid = $2 AND u.is_active = true`,
+		followerID, followingID)
+	if err == sql.ErrNoRows {
+		return false, errors.New("user_not_found")
+	}
+	if err != nil {
+		return false, err
+	}
+	if target.Blocked {
+		return false, errors.New("user_blocked")
+	}
+
+	if target.IsPrivate {
+		return true, s.requestFollow(ctx, followerID, followingID)
 	}
 
 	_, err = s.db.ExecContext(ctx, "INSERT INTO user_follows (id, follower_id, following_id, created_at) VALUES ($1, $2, $3, $4)",
 		uuid.New().String(), followerID, followingID, time.Now())
-	return err
+	return false, err
 }
 
+// UnfollowUser removes a follow, or withdraws a pending follow request to a private account
 func (s *VideoService) UnfollowUser(ctx context.Context, followerID, followingID string) error {
 	result, err := s.db.ExecContext(ctx, "DELETE FROM user_follows WHERE follower_id = $1 AND following_id = $2", followerID, followingID)
 	if err != nil {
@@ -1258,7 +2286,13 @@ func (s *VideoService) UnfollowUser(ctx context.Context, followerID, followingID
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("not_following")
+		cancelled, err := s.cancelFollowRequest(ctx, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		if !cancelled {
+			return errors.New("not_following")
+		}
 	}
 
 	return nil
@@ -1270,59 +2304,163 @@ func (s *VideoService) CheckUserFollowing(ctx context.Context, followerID, follo
 	return count > 0, err
 }
 
-func (s *VideoService) GetUserFollowers(ctx context.Context, userID string, limit, offset int) ([]models.User, error) {
+// GetFollowStatusBulk reports, for each of the given user IDs, whether followerID follows
+// them. IDs that are not followed map to false.
+func (s *VideoService) GetFollowStatusBulk(ctx context.Context, followerID string, userIDs []string) (map[string]bool, error) {
+	status := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		status[id] = false
+	}
+	if len(userIDs) == 0 {
+		return status, nil
+	}
+
+	var followingIDs []string
+	err := s.db.SelectContext(ctx, &followingIDs,
+		"SELECT following_id FROM user_follows WHERE follower_id = $1 AND following_id = ANY($2)",
+		followerID, models.StringSlice(userIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range followingIDs {
+		status[id] = true
+	}
+
+	return status, nil
+}
+
+// GetUserFollowers returns a page of userID's followers and the total follower count.
+// IsMutual marks followers userID follows back.
+func (s *VideoService) GetUserFollowers(ctx context.Context, userID string, limit, offset int) ([]models.User, int, error) {
 	query := `
 		SELECT u.uid, u.name, u.phone_number, u.whatsapp_number, u.profile_image, u.cover_image, u.bio,
 		       u.user_type, u.role, u.followers_count, u.following_count, u.videos_count, u.likes_count,
 		       u.is_verified, u.is_active, u.is_featured, u.tags,
-		       u.created_at, u.updated_at, u.last_seen, u.last_post_at
+		       u.created_at, u.updated_at, u.last_seen, u.last_post_at,
+		       EXISTS (
+		           SELECT 1 FROM user_follows back
+		           WHERE back.follower_id = $1 AND back.following_id = u.uid
+		       ) as is_mutual,
+		       COUNT(*) OVER() as total_count
 		FROM users u
 		JOIN user_follows uf ON u.uid = uf.follower_id
 		WHERE uf.following_id = $1 AND u.is_active = true
 		ORDER BY uf.created_at DESC
 		LIMIT $2 OFFSET $3`
 
-	var users []models.User
-	err := s.db.SelectContext(ctx, &users, query, userID, limit, offset)
-	return users, err
+	return s.selectUsersWithTotal(ctx, query, userID, limit, offset)
 }
 
-func (s *VideoService) GetUserFollowing(ctx context.Context, userID string, limit, offset int) ([]models.User, error) {
+// GetMutualFollowers returns followers of userID whom the viewer also follows, along with the
+// total number of such users, for "followed by X, Y and N others you follow"
+func (s *VideoService) GetMutualFollowers(ctx context.Context, viewerID, userID string, limit, offset int) ([]models.User, int, error) {
 	query := `
 		SELECT u.uid, u.name, u.phone_number, u.whatsapp_number, u.profile_image, u.cover_image, u.bio,
 		       u.user_type, u.role, u.followers_count, u.following_count, u.videos_count, u.likes_count,
 		       u.is_verified, u.is_active, u.is_featured, u.tags,
-		       u.created_at, u.updated_at, u.last_seen, u.last_post_at
+		       u.created_at, u.updated_at, u.last_seen, u.last_post_at,
+		       COUNT(*) OVER() as total_count
+		FROM user_follows target_followers
+		JOIN user_follows viewer_following 
+		  ON viewer_following.following_id = target_followers.follower_id
+		 AND viewer_following.follower_id = $2
+		JOIN users u ON u.uid = target_followers.follower_id
+		WHERE target_followers.following_id = $1 AND u.is_active = true
+		ORDER BY u.followers_count DESC, target_followers.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	return s.selectUsersWithTotal(ctx, query, userID, viewerID, limit, offset)
+}
+
+// GetUserFollowing returns a page of users userID follows and the total count. IsMutual
+// marks users who follow userID back.
+func (s *VideoService) GetUserFollowing(ctx context.Context, userID string, limit, offset int) ([]models.User, int, error) {
+	query := `
+		SELECT u.uid, u.name, u.phone_number, u.whatsapp_number, u.profile_image, u.cover_image, u.bio,
+		       u.user_type, u.role, u.followers_count, u.following_count, u.videos_count, u.likes_count,
+		       u.is_verified, u.is_active, u.is_featured, u.tags,
+		       u.created_at, u.updated_at, u.last_seen, u.last_post_at,
+		       EXISTS (
+		           SELECT 1 FROM user_follows back
+		           WHERE back.follower_id = u.uid AND back.following_id = $1
+		       ) as is_mutual,
+		       COUNT(*) OVER() as total_count
 		FROM users u
 		JOIN user_follows uf ON u.uid = uf.following_id
 		WHERE uf.follower_id = $1 AND u.is_active = true
 		ORDER BY uf.created_at DESC
 		LIMIT $2 OFFSET $3`
 
-	var users []models.User
-	err := s.db.SelectContext(ctx, &users, query, userID, limit, offset)
-	return users, err
+	return s.selectUsersWithTotal(ctx, query, userID, limit, offset)
+}
+
+// GetCommonFollowing returns users that both the viewer and userID follow, most recently
+// followed by the viewer first, along with the total number of such users
+func (s *VideoService) GetCommonFollowing(ctx context.Context, viewerID, userID string, limit, offset int) ([]models.User, int, error) {
+	query := `
+		SELECT u.uid, u.name, u.phone_number, u.whatsapp_number, u.profile_image, u.cover_image, u.bio,
+		       u.user_type, u.role, u.followers_count, u.following_count, u.videos_count, u.likes_count,
+		       u.is_verified, u.is_active, u.is_featured, u.tags,
+		       u.created_at, u.updated_at, u.last_seen, u.last_post_at,
+		       COUNT(*) OVER() as total_count
+		FROM user_follows viewer_following
+		JOIN user_follows target_following
+		  ON target_following.following_id = viewer_following.following_id
+		 AND target_following.follower_id = $2
+		JOIN users u ON u.uid = viewer_following.following_id
+		WHERE viewer_following.follower_id = $1 AND u.is_active = true
+		ORDER BY viewer_following.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	return s.selectUsersWithTotal(ctx, query, viewerID, userID, limit, offset)
+}
+
+// selectUsersWithTotal runs a user list query that selects COUNT(*) OVER() as total_count
+func (s *VideoService) selectUsersWithTotal(ctx context.Context, query string, args ...interface{}) ([]models.User, int, error) {
+	var rows []struct {
+		models.User
+		TotalCount int `db:"total_count"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]models.User, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		users = append(users, row.User)
+		total = row.TotalCount
+	}
+
+	return users, total, nil
 }
 
-func (s *VideoService) GetFollowingVideoFeed(ctx context.Context, userID string, limit, offset int) ([]models.VideoResponse, error) {
+// GetFollowingVideoFeed returns a page of videos from accounts userID follows and the
+// total number of such videos
+func (s *VideoService) GetFollowingVideoFeed(ctx context.Context, userID string, limit, offset int) ([]models.VideoResponse, int, error) {
 	query := `
 		SELECT v.id, v.user_id, v.user_name, v.user_image, v.video_url, v.thumbnail_url,
 		       v.caption, v.price, v.likes_count, v.comments_count, v.views_count, v.shares_count,
 		       v.tags, v.is_active, v.is_featured, v.is_verified, v.is_multiple_images, v.image_urls,
-		       v.created_at, v.updated_at
+		       v.created_at, v.updated_at,
+		       COUNT(*) OVER() as total_count
 		FROM videos v
 		JOIN user_follows uf ON v.user_id = uf.following_id
+		JOIN users owner ON owner.uid = v.user_id AND owner.is_active = true
 		WHERE uf.follower_id = $1 AND v.is_active = true
+		  AND NOT ` + blockedPairExists("v.user_id", 1) + `
 		ORDER BY v.created_at DESC
 		LIMIT $2 OFFSET $3`
 
 	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var videos []models.VideoResponse
+	total := 0
 	for rows.Next() {
 		var video models.VideoResponse
 
@@ -1332,19 +2470,54 @@ func (s *VideoService) GetFollowingVideoFeed(ctx context.Context, userID string,
 			&video.LikesCount, &video.CommentsCount, &video.ViewsCount, &video.SharesCount,
 			&video.Tags, &video.IsActive, &video.IsFeatured, &video.IsVerified,
 			&video.IsMultipleImages, &video.ImageUrls, &video.CreatedAt, &video.UpdatedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		s.applyURLOptimizations(&video)
+		s.applyURLOptimizations(ctx, &video)
 		video.UserProfileImage = video.UserImage
 		video.IsFollowing = true
 
 		videos = append(videos, video)
 	}
 
-	return videos, nil
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return videos, total, nil
+}
+
+// MaxFollowingNewCount caps the "new posts" badge count; the badge shows "99+" beyond it
+const MaxFollowingNewCount = 99
+
+// GetFollowingNewCount counts active videos posted by followed creators since the user
+// last loaded the following feed, capped at MaxFollowingNewCount+1 so the count stays cheap
+func (s *VideoService) GetFollowingNewCount(ctx context.Context, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM (
+			SELECT 1
+			FROM videos v
+			JOIN user_follows uf ON v.user_id = uf.following_id
+			JOIN users owner ON owner.uid = v.user_id AND owner.is_active = true
+			WHERE uf.follower_id = $1 AND v.is_active = true
+			  AND v.created_at > COALESCE(
+			      (SELECT last_feed_seen_at FROM users WHERE uid = $1), '-infinity'::timestamptz)
+			  AND NOT ` + blockedPairExists("v.user_id", 1) + `
+			LIMIT $2
+		) new_videos`
+
+	var count int
+	err := s.db.GetContext(ctx, &count, query, userID, MaxFollowingNewCount+1)
+	return count, err
+}
+
+// MarkFollowingFeedSeen records that the user has just loaded the following feed
+func (s *VideoService) MarkFollowingFeedSeen(ctx context.Context, userID string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET last_feed_seen_at = NOW() WHERE uid = $1`, userID)
+	return err
 }
 
 // ===============================
@@ -1374,58 +2547,115 @@ func (s *VideoService) ToggleFeatured(ctx context.Context, videoID string, isFea
 	return nil
 }
 
-func (s *VideoService) ToggleActive(ctx context.Context, videoID string, isActive bool) error {
-	query := `
-		UPDATE videos 
-		SET is_active = $1, updated_at = $2 
-		WHERE id = $3`
+// publicStatsCacheTTL bounds how stale the landing page counters may be
+const publicStatsCacheTTL = 10 * time.Minute
 
-	result, err := s.db.ExecContext(ctx, query, isActive, time.Now(), videoID)
-	if err != nil {
-		return err
+// GetPublicStats returns platform totals for the public landing page: creators with at
+// least one active post, active posts and their combined views. Only aggregates over
+// active content are exposed, and they are recomputed at most every publicStatsCacheTTL.
+func (s *VideoService) GetPublicStats(ctx context.Context) (*models.PublicStats, error) {
+	s.publicStatsMu.Lock()
+	defer s.publicStatsMu.Unlock()
+
+	if s.publicStats != nil && time.Since(s.publicStats.UpdatedAt) < publicStatsCacheTTL {
+		stats := *s.publicStats
+		return &stats, nil
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	var stats models.PublicStats
+	err := s.db.GetContext(ctx, &stats, `
+		SELECT COUNT(DISTINCT v.user_id) AS total_creators,
+		       COUNT(*) AS total_videos,
+		       COALESCE(SUM(v.views_count), 0) AS total_views
+		FROM videos v
+		JOIN users u ON u.uid = v.user_id AND u.is_active = true
+		WHERE v.is_active = true`)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	stats.UpdatedAt = time.Now()
 
-	if rowsAffected == 0 {
-		return errors.New("video_not_found")
+	s.publicStats = &stats
+	result := stats
+	return &result, nil
+}
+
+// GetMediaBreakdown counts active video posts and image posts, for one creator when
+// userID is set. The platform-wide count is served by idx_videos_media_type_search.
+func (s *VideoService) GetMediaBreakdown(ctx context.Context, userID string) (*models.MediaBreakdown, error) {
+	query := `
+		SELECT is_multiple_images, COUNT(*)
+		FROM videos
+		WHERE is_active = true`
+
+	var args []interface{}
+	if userID != "" {
+		query += " AND user_id = $1"
+		args = append(args, userID)
 	}
+	query += " GROUP BY is_multiple_images"
 
-	return nil
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := &models.MediaBreakdown{UserID: userID}
+	for rows.Next() {
+		var isImages bool
+		var count int
+		if err := rows.Scan(&isImages, &count); err != nil {
+			return nil, err
+		}
+		if isImages {
+			breakdown.ImagePosts = count
+		} else {
+			breakdown.VideoPosts = count
+		}
+		breakdown.Total += count
+	}
+	return breakdown, rows.Err()
 }
 
-func (s *VideoService) GetVideoStats(ctx context.Context, userID string) ([]models.VideoPerformance, error) {
+// GetVideoStats returns a page of the user's per-video performance and their total
+// active video count
+func (s *VideoService) GetVideoStats(ctx context.Context, userID string, limit, offset int) ([]models.VideoPerformance, int, error) {
 	query := `
 		SELECT id as video_id, caption as title, likes_count, comments_count, 
-		       views_count, shares_count, created_at
+		       views_count, shares_count, created_at,
+		       COUNT(*) OVER() as total_count
 		FROM videos 
 		WHERE user_id = $1 AND is_active = true 
-		ORDER BY created_at DESC`
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
 
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var stats []models.VideoPerformance
+	total := 0
 	for rows.Next() {
 		var stat models.VideoPerformance
 		err := rows.Scan(
 			&stat.VideoID, &stat.Title, &stat.LikesCount,
 			&stat.CommentsCount, &stat.ViewsCount, &stat.SharesCount,
-			&stat.CreatedAt,
+			&stat.CreatedAt, &total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		stat.CalculateEngagementRate()
 		stats = append(stats, stat)
 	}
 
-	return stats, nil
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return stats, total, nil
 }
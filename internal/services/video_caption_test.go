@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCaption(t *testing.T) {
+	tests := []struct {
+		name    string
+		caption string
+		want    string
+		wantErr string
+	}{
+		{name: "empty", caption: "", wantErr: "caption_required"},
+		{name: "whitespace only", caption: " \t\n ", wantErr: "caption_required"},
+		{name: "trimmed", caption: "  hello world \n", want: "hello world"},
+		{name: "at max length", caption: strings.Repeat("a", maxCaptionLength), want: strings.Repeat("a", maxCaptionLength)},
+		{name: "over max length", caption: strings.Repeat("a", maxCaptionLength+1), wantErr: "caption_too_long"},
+		{name: "surrounding whitespace does not count", caption: " " + strings.Repeat("a", maxCaptionLength) + " ",
+			want: strings.Repeat("a", maxCaptionLength)},
+		{name: "multibyte at max length", caption: strings.Repeat("视", maxCaptionLength), want: strings.Repeat("视", maxCaptionLength)},
+		{name: "emoji at max length", caption: strings.Repeat("🔥", maxCaptionLength), want: strings.Repeat("🔥", maxCaptionLength)},
+		{name: "multibyte over max length", caption: strings.Repeat("é", maxCaptionLength+1), wantErr: "caption_too_long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeCaption(tt.caption)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("normalizeCaption() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeCaption() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeCaption() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
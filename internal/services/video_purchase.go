@@ -0,0 +1,124 @@
+// ===============================
+// internal/services/video_purchase.go - Paid Video Purchase Service
+// ===============================
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"weibaobe/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type VideoPurchaseService struct {
+	db             *sqlx.DB
+	walletService  *WalletService
+	webhookService *WebhookService
+}
+
+func NewVideoPurchaseService(db *sqlx.DB, walletService *WalletService, webhookService *WebhookService) *VideoPurchaseService {
+	return &VideoPurchaseService{
+		db:             db,
+		walletService:  walletService,
+		webhookService: webhookService,
+	}
+}
+
+// PurchaseVideo charges the buyer the video's price in coins, credits the creator
+// (minus platform commission) and records the entitlement, all in one transaction
+func (s *VideoPurchaseService) PurchaseVideo(ctx context.Context, buyerID, videoID string) (*models.VideoPurchase, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var video struct {
+		UserID   string  `db:"user_id"`
+		Caption  string  `db:"caption"`
+		Price    float64 `db:"price"`
+		IsActive bool    `db:"is_active"`
+	}
+	err = tx.GetContext(ctx, &video, `
+		SELECT user_id, caption, price, is_active FROM videos WHERE id = $1 FOR SHARE`, videoID)
+	if err == sql.ErrNoRows || (err == nil && !video.IsActive) {
+		return nil, errors.New("video_not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if video.UserID == buyerID {
+		return nil, errors.New("cannot_purchase_own_video")
+	}
+	if video.Price <= 0 {
+		return nil, errors.New("video_not_for_sale")
+	}
+
+	var purchase models.VideoPurchase
+	err = tx.GetContext(ctx, &purchase, `
+		INSERT INTO video_purchases (user_id, video_id, price_paid)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, video_id) DO NOTHING
+		RETURNING id, user_id, video_id, price_paid, created_at`,
+		buyerID, videoID, video.Price)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("already_purchased")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	coins := models.VideoPriceInCoins(video.Price)
+	creatorAmount, _ := models.CalculateCommission(coins, models.DefaultCommissionRate)
+
+	_, err = s.walletService.Debit(ctx, tx, buyerID, coins, "video_purchase", models.WalletLedgerMeta{
+		Description: "Purchased video",
+		ReferenceID: &purchase.ID,
+		Metadata: models.MetadataMap{
+			"video_id":   videoID,
+			"creator_id": video.UserID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if creatorAmount > 0 {
+		_, err = s.walletService.Credit(ctx, tx, video.UserID, creatorAmount, "video_sale", models.WalletLedgerMeta{
+			Description: "Video sale",
+			ReferenceID: &purchase.ID,
+			Metadata: models.MetadataMap{
+				"video_id": videoID,
+				"buyer_id": buyerID,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to credit creator: %w", err)
+		}
+	}
+
+	err = s.webhookService.Enqueue(ctx, tx, models.WebhookEventVideoPurchased, models.VideoPurchasedWebhookData{
+		PurchaseID:    purchase.ID,
+		BuyerID:       buyerID,
+		CreatorID:     video.UserID,
+		VideoID:       videoID,
+		CoinsPaid:     coins,
+		CreatorAmount: creatorAmount,
+		PurchasedAt:   purchase.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &purchase, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"weibaobe/internal/config"
+	"weibaobe/internal/models"
+)
+
+func TestRepricingKeepsEarlierBuyersAccess(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	videos := NewVideoService(db, nil, nil, nil, config.DuplicateUploadConfig{}, nil, nil)
+
+	creator := createTestUser(t, db, "Creator")
+	buyer := createTestUser(t, db, "Buyer")
+	freeViewer := createTestUser(t, db, "Free Viewer")
+	stranger := createTestUser(t, db, "Stranger")
+
+	paidID := createTestVideo(t, db, creator, 10)
+	freeID := createTestVideo(t, db, creator, 0)
+
+	if _, err := db.Exec(`
+		INSERT INTO video_purchases (user_id, video_id, price_paid) VALUES ($1, $2, 10)`,
+		buyer, paidID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO video_likes (video_id, user_id) VALUES ($1, $2)`,
+		freeID, freeViewer); err != nil {
+		t.Fatal(err)
+	}
+
+	reprice := func(videoID string, price float64) {
+		t.Helper()
+		err := videos.UpdateVideo(ctx, &models.Video{
+			ID:       videoID,
+			UserID:   creator,
+			VideoURL: "https://example.com/video.mp4",
+			Price:    price,
+			IsActive: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectAccess := func(step, videoID, viewerID string, want bool) {
+		t.Helper()
+		access, err := videos.GetVideoAccess(ctx, videoID, viewerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if access.HasAccess != want {
+			t.Errorf("%s: access of %s = %v, want %v", step, viewerID, access.HasAccess, want)
+		}
+	}
+
+	expectAccess("bought", paidID, buyer, true)
+	expectAccess("bought", paidID, stranger, false)
+
+	reprice(paidID, 50)
+	expectAccess("price raised", paidID, buyer, true)
+	expectAccess("price raised", paidID, stranger, false)
+	expectAccess("price raised", paidID, creator, true)
+
+	reprice(paidID, 5)
+	expectAccess("price lowered", paidID, buyer, true)
+	expectAccess("price lowered", paidID, stranger, false)
+
+	reprice(paidID, 0)
+	expectAccess("made free", paidID, stranger, true)
+
+	// Putting a price back on after a free spell keeps the original buyer's entitlement
+	reprice(paidID, 20)
+	expectAccess("priced again", paidID, buyer, true)
+	expectAccess("priced again", paidID, stranger, false)
+
+	// Viewers who engaged while the video was free keep it when a price is first added
+	reprice(freeID, 15)
+	expectAccess("free video priced", freeID, freeViewer, true)
+	expectAccess("free video priced", freeID, stranger, false)
+}
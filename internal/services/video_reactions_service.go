@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"weibaobe/internal/models"
@@ -20,17 +21,20 @@ type VideoReactionsService struct {
 	repo         *repositories.VideoReactionsRepository
 	userService  *UserService
 	videoService *VideoService
+	blockService *BlockService
 }
 
 func NewVideoReactionsService(
 	repo *repositories.VideoReactionsRepository,
 	userService *UserService,
 	videoService *VideoService,
+	blockService *BlockService,
 ) *VideoReactionsService {
 	return &VideoReactionsService{
 		repo:         repo,
 		userService:  userService,
 		videoService: videoService,
+		blockService: blockService,
 	}
 }
 
@@ -50,8 +54,16 @@ func (s *VideoReactionsService) CreateVideoReactionChat(
 		return nil, errors.New("cannot create chat with yourself")
 	}
 
+	blocked, err := s.blockService.AreUsersBlocked(ctx, currentUserID, videoOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("users are blocked")
+	}
+
 	// Check if video exists
-	video, err := s.videoService.GetVideoOptimized(ctx, videoReaction.VideoID)
+	video, err := s.videoService.GetVideoOptimized(ctx, videoReaction.VideoID, currentUserID)
 	if err != nil {
 		return nil, fmt.Errorf("video not found: %w", err)
 	}
@@ -249,6 +261,19 @@ func (s *VideoReactionsService) SendMessage(
 		return nil, errors.New("access denied")
 	}
 
+	for _, participantID := range chat.Participants {
+		if participantID == senderID {
+			continue
+		}
+		blocked, err := s.blockService.AreUsersBlocked(ctx, senderID, participantID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, errors.New("users are blocked")
+		}
+	}
+
 	// Create message
 	message := &models.VideoReactionMessage{
 		MessageID:        uuid.New().String(),
@@ -335,6 +360,55 @@ func (s *VideoReactionsService) GetChatMessages(
 	}, nil
 }
 
+// Upper bound on messages included in a single chat export
+const maxChatExportMessages = 10000
+
+// ExportChat returns the chat transcript for a participant, with sender names resolved
+func (s *VideoReactionsService) ExportChat(ctx context.Context, chatID, userID string) (*models.ChatExport, error) {
+	chat, err := s.repo.GetChatByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, errors.New("chat not found")
+	}
+	if !s.isParticipant(chat, userID) {
+		return nil, errors.New("access denied")
+	}
+
+	messages, err := s.repo.GetChatMessagesForExport(ctx, chatID, maxChatExportMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chats only have two participants, so resolve names once
+	senderNames := make(map[string]string, len(chat.Participants))
+	for _, participantID := range chat.Participants {
+		name, _, _, err := s.userService.GetUserBasicInfo(ctx, participantID)
+		if err != nil {
+			name = "Unknown user"
+		}
+		senderNames[participantID] = name
+	}
+
+	for i := range messages {
+		if name, ok := senderNames[messages[i].SenderID]; ok {
+			messages[i].SenderName = name
+		} else {
+			messages[i].SenderName = "Unknown user"
+		}
+	}
+
+	return &models.ChatExport{
+		ChatID:       chat.ChatID,
+		Participants: chat.Participants,
+		Messages:     messages,
+		Total:        len(messages),
+		Truncated:    len(messages) == maxChatExportMessages,
+		ExportedAt:   time.Now(),
+	}, nil
+}
+
 // EditMessage edits a message
 func (s *VideoReactionsService) EditMessage(ctx context.Context, messageID, userID, newContent string) error {
 	// Get message
@@ -412,6 +486,58 @@ func (s *VideoReactionsService) ToggleMessagePin(ctx context.Context, messageID,
 	return s.repo.ToggleMessagePin(ctx, messageID)
 }
 
+// messageReportFlagThreshold is how many different users must report a sender's messages
+// before the sender is flagged for review
+const messageReportFlagThreshold = 3
+
+// ReportMessage lets a chat participant report a message in that chat for moderator
+// review. Senders reported by messageReportFlagThreshold different users are flagged.
+func (s *VideoReactionsService) ReportMessage(ctx context.Context, chatID, messageID, reporterID, reason, description string) (*models.MessageReportResult, error) {
+	if !models.IsValidReportReason(reason) {
+		return nil, errors.New("invalid_reason")
+	}
+
+	message, err := s.repo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || message.ChatID != chatID {
+		return nil, errors.New("message_not_found")
+	}
+
+	chat, err := s.repo.GetChatByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil || !s.isParticipant(chat, reporterID) {
+		return nil, errors.New("access_denied")
+	}
+	if message.SenderID == reporterID {
+		return nil, errors.New("cannot_report_own_message")
+	}
+
+	reportID, err := s.repo.CreateMessageReport(ctx, message, reporterID, reason, description)
+	if err != nil {
+		return nil, err
+	}
+	if reportID == "" {
+		return nil, errors.New("already_reported")
+	}
+
+	flagged, err := s.repo.FlagRepeatedlyReportedSender(ctx, message.SenderID, messageReportFlagThreshold)
+	if err != nil {
+		// The report is stored; the flag is re-evaluated on the next report
+		log.Printf("⚠️ Failed to evaluate message sender flag for %s: %v", message.SenderID, err)
+	}
+
+	return &models.MessageReportResult{ReportID: reportID, SenderFlagged: flagged}, nil
+}
+
+// GetReportedMessages returns chat messages with open reports for the moderation queue
+func (s *VideoReactionsService) GetReportedMessages(ctx context.Context, limit, offset int) ([]models.ReportedMessage, int, error) {
+	return s.repo.GetReportedMessages(ctx, limit, offset)
+}
+
 // AddMessageReaction adds a reaction to a message
 func (s *VideoReactionsService) AddMessageReaction(ctx context.Context, messageID, userID, reaction string) error {
 	// Get message
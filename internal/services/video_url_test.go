@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithQueryParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		keyValues []string
+		want      string
+	}{
+		{name: "no query", url: "https://cdn.example.com/v.mp4", keyValues: []string{"stream", "true"},
+			want: "https://cdn.example.com/v.mp4?stream=true"},
+		{name: "existing query", url: "https://cdn.example.com/v.mp4?sig=abc", keyValues: []string{"stream", "true"},
+			want: "https://cdn.example.com/v.mp4?sig=abc&stream=true"},
+		{name: "param already present", url: "https://cdn.example.com/v.mp4?stream=false", keyValues: []string{"stream", "true"},
+			want: "https://cdn.example.com/v.mp4?stream=false"},
+		{name: "trailing separator", url: "https://cdn.example.com/v.mp4?", keyValues: []string{"stream", "true"},
+			want: "https://cdn.example.com/v.mp4?stream=true"},
+		{name: "fragment kept last", url: "https://cdn.example.com/v.mp4#t=10", keyValues: []string{"stream", "true"},
+			want: "https://cdn.example.com/v.mp4?stream=true#t=10"},
+		{name: "only missing keys added", url: "https://cdn.example.com/i.jpg?format=webp",
+			keyValues: []string{"format", "webp", "quality", "85"},
+			want:      "https://cdn.example.com/i.jpg?format=webp&quality=85"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withQueryParams(tt.url, tt.keyValues...); got != tt.want {
+				t.Errorf("withQueryParams(%q, %v) = %q, want %q", tt.url, tt.keyValues, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetQueryParam(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "adds when missing", url: "https://cdn.example.com/i.jpg",
+			want: "https://cdn.example.com/i.jpg?width=320"},
+		{name: "replaces in place", url: "https://cdn.example.com/i.jpg?width=640&format=webp",
+			want: "https://cdn.example.com/i.jpg?width=320&format=webp"},
+		{name: "keeps fragment", url: "https://cdn.example.com/i.jpg?width=640#x",
+			want: "https://cdn.example.com/i.jpg?width=320#x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setQueryParam(tt.url, "width", "320"); got != tt.want {
+				t.Errorf("setQueryParam(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+// A URL served to a client can come back in a write (e.g. a profile update echoing the
+// image it was given) and be served again; params must not pile up across the trip.
+func TestURLOptimizationRoundTrip(t *testing.T) {
+	s := &VideoService{}
+
+	videoURLs := []string{
+		"https://pub.r2.cloudflarestorage.com/videos/a.mp4",
+		"https://media.example.com/videos/a.mp4",
+		"https://media.example.com/videos/a.mp4?token=abc",
+	}
+	for _, stored := range videoURLs {
+		served := s.optimizeVideoURL(stored)
+		again := s.optimizeVideoURL(served)
+		if again != served {
+			t.Errorf("optimizeVideoURL round trip of %q: %q then %q", stored, served, again)
+		}
+	}
+
+	ctx320 := WithImageWidth(context.Background(), 320)
+	ctx1080 := WithImageWidth(context.Background(), 1080)
+
+	stored := "https://imagedelivery.cloudflare.com/abc/thumb.jpg?width=640"
+	served := s.optimizeThumbnailURL(ctx320, stored)
+	want := "https://imagedelivery.cloudflare.com/abc/thumb.jpg?width=320&format=webp&quality=85"
+	if served != want {
+		t.Fatalf("optimizeThumbnailURL(%q) = %q, want %q", stored, served, want)
+	}
+
+	// Written back as-is and served to a client asking for another width
+	reserved := s.optimizeThumbnailURL(ctx1080, served)
+	want = "https://imagedelivery.cloudflare.com/abc/thumb.jpg?width=1080&format=webp&quality=85"
+	if reserved != want {
+		t.Errorf("optimizeThumbnailURL round trip = %q, want %q", reserved, want)
+	}
+
+	if got := s.optimizeThumbnailURL(ctx1080, reserved); got != reserved {
+		t.Errorf("optimizeThumbnailURL is not idempotent: %q then %q", reserved, got)
+	}
+
+	// Non-CDN images are served untouched
+	plain := "https://media.example.com/thumb.jpg"
+	if got := s.optimizeThumbnailURL(ctx320, plain); got != plain {
+		t.Errorf("optimizeThumbnailURL(%q) = %q, want it unchanged", plain, got)
+	}
+}
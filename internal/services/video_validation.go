@@ -0,0 +1,199 @@
+// ===============================
+// internal/services/video_validation.go - Pre-publish Draft Validation
+// ===============================
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"weibaobe/internal/models"
+	"weibaobe/internal/storage"
+)
+
+// probeTimeout bounds a single ffprobe run; it only reads the container header over HTTP
+const probeTimeout = 15 * time.Second
+
+// ValidateVideoDraft runs the checks CreateVideo would, without creating anything, and
+// additionally HEADs every media URL in R2 and probes the video for duration and
+// dimensions when ffprobe is installed. Returns storage.ErrStorageUnavailable when R2
+// cannot be reached, since reachability can't be judged then.
+func (s *VideoService) ValidateVideoDraft(ctx context.Context, req models.ValidateVideoRequest) (*models.VideoValidationResult, error) {
+	result := &models.VideoValidationResult{
+		Errors:   []string{},
+		Warnings: []string{},
+		Tags:     []string{},
+		Media:    []models.MediaCheck{},
+	}
+
+	caption, err := normalizeCaption(req.Caption)
+	switch {
+	case err == nil:
+		result.Caption = caption
+		flagged, _, err := s.moderateText(ctx, caption)
+		if err != nil {
+			if err.Error() != "content_rejected" {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, "caption contains disallowed content")
+		} else if flagged {
+			result.Warnings = append(result.Warnings, "caption will be held for moderator review")
+		}
+	case err.Error() == "caption_required":
+		result.Errors = append(result.Errors, "caption is required")
+	case err.Error() == "caption_too_long":
+		result.Errors = append(result.Errors, fmt.Sprintf("caption must be %d characters or less", maxCaptionLength))
+	}
+
+	tags, err := normalizeTags(models.StringSlice(req.Tags))
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("a video can have at most %d tags", maxVideoTags))
+	} else {
+		result.Tags = tags
+		if len(tags) < len(req.Tags) {
+			result.Warnings = append(result.Warnings, "duplicate or empty tags were removed")
+		}
+	}
+
+	if req.Price != nil {
+		if *req.Price < 0 {
+			result.Warnings = append(result.Warnings, "negative price will be stored as 0")
+		} else {
+			result.Price = *req.Price
+		}
+	}
+
+	if req.IsMultipleImages {
+		if len(req.ImageUrls) == 0 {
+			result.Errors = append(result.Errors, "at least one image URL is required for image posts")
+		}
+		if len(req.ImageUrls) > models.GetMaxImagesPerPost() {
+			result.Errors = append(result.Errors, fmt.Sprintf("a post can have at most %d images", models.GetMaxImagesPerPost()))
+		}
+		for _, imageURL := range req.ImageUrls {
+			if err := s.checkMedia(ctx, result, imageURL, "image", "image/"); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if req.VideoURL == "" {
+			result.Errors = append(result.Errors, "video URL is required for video posts")
+		} else if err := s.checkMedia(ctx, result, req.VideoURL, "video", "video/"); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ThumbnailURL != "" {
+		if err := s.checkMedia(ctx, result, req.ThumbnailURL, "thumbnail", "image/"); err != nil {
+			return nil, err
+		}
+	} else if !req.IsMultipleImages {
+		result.Warnings = append(result.Warnings, "no thumbnail provided; the generated one will be used if it exists")
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// checkMedia HEADs one media URL and records the outcome. A missing object is an error;
+// URLs outside our bucket and unexpected content types are only warnings.
+func (s *VideoService) checkMedia(ctx context.Context, result *models.VideoValidationResult, url, kind, typePrefix string) error {
+	check := models.MediaCheck{URL: url, Kind: kind}
+
+	key, ok := s.r2Client.KeyFromURL(url)
+	if !ok {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s URL is not served from our storage and was not checked", kind))
+		result.Media = append(result.Media, check)
+		return nil
+	}
+
+	info, err := s.r2Client.StatObject(ctx, key)
+	switch {
+	case errors.Is(err, storage.ErrObjectNotFound):
+		result.Errors = append(result.Errors, fmt.Sprintf("%s not found in storage: %s", kind, url))
+		result.Media = append(result.Media, check)
+		return nil
+	case errors.Is(err, storage.ErrStorageUnavailable):
+		return err
+	case err != nil:
+		return fmt.Errorf("failed to check %s: %w", kind, err)
+	}
+
+	check.Reachable = true
+	check.ContentType = info.ContentType
+	check.SizeBytes = info.ContentLength
+
+	if info.ContentLength == 0 {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s is empty: %s", kind, url))
+	}
+	if info.ContentType != "" && !strings.HasPrefix(info.ContentType, typePrefix) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s has unexpected content type %s", kind, info.ContentType))
+	}
+
+	if kind == "video" {
+		if err := probeVideo(ctx, s.r2Client.GetPublicURL(key), &check); err != nil {
+			result.Warnings = append(result.Warnings, "duration and dimensions unavailable: "+err.Error())
+		}
+	}
+
+	result.Media = append(result.Media, check)
+	return nil
+}
+
+// probeVideo reads duration and dimensions of the first video stream with ffprobe
+func probeVideo(ctx context.Context, url string, check *models.MediaCheck) error {
+	if !strings.HasPrefix(url, "https://") {
+		return errors.New("only https URLs can be probed")
+	}
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		return errors.New("ffprobe is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	// https runs over tls and tcp, which must be whitelisted for it to open; the scheme
+	// check above keeps those from being used directly. The URL follows "--" so it can
+	// never be read as an option.
+	output, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-protocol_whitelist", "https,tls,tcp",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		"--", url,
+	).Output()
+	if err != nil {
+		return fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return fmt.Errorf("unreadable ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return errors.New("no video stream found")
+	}
+
+	width, height := probe.Streams[0].Width, probe.Streams[0].Height
+	check.Width, check.Height = &width, &height
+	if duration, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil {
+		check.DurationSeconds = &duration
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"weibaobe/internal/models"
@@ -14,12 +16,25 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// InsufficientBalanceError is returned when a wallet cannot cover a debit. Its message is
+// "insufficient_balance" so callers switching on err.Error() keep working, while handlers
+// can unwrap it to tell the client how many coins are needed.
+type InsufficientBalanceError struct {
+	Required int
+	Current  int
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return "insufficient_balance"
+}
+
 type WalletService struct {
-	db *sqlx.DB
+	db             *sqlx.DB
+	webhookService *WebhookService
 }
 
-func NewWalletService(db *sqlx.DB) *WalletService {
-	return &WalletService{db: db}
+func NewWalletService(db *sqlx.DB, webhookService *WebhookService) *WalletService {
+	return &WalletService{db: db, webhookService: webhookService}
 }
 
 func (s *WalletService) GetWallet(ctx context.Context, userID string) (*models.Wallet, error) {
@@ -38,6 +53,31 @@ func (s *WalletService) GetWallet(ctx context.Context, userID string) (*models.W
 	return &wallet, nil
 }
 
+// GetBalance returns the user's coin balance, or 0 when they have no wallet yet
+func (s *WalletService) GetBalance(ctx context.Context, userID string) (int, error) {
+	var balance int
+	err := s.db.GetContext(ctx, &balance, `SELECT coins_balance FROM wallets WHERE user_id = $1`, userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return balance, err
+}
+
+// GetBalanceSummary reads only the balance and its last change, without creating a
+// wallet for users who don't have one yet
+func (s *WalletService) GetBalanceSummary(ctx context.Context, userID string) (*models.WalletBalance, error) {
+	var balance models.WalletBalance
+	err := s.db.GetContext(ctx, &balance,
+		`SELECT coins_balance, updated_at FROM wallets WHERE user_id = $1`, userID)
+	if err == sql.ErrNoRows {
+		return &models.WalletBalance{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
 func (s *WalletService) createWallet(ctx context.Context, userID string) (models.Wallet, error) {
 	// Get user info
 	var user models.User
@@ -64,13 +104,28 @@ func (s *WalletService) createWallet(ctx context.Context, userID string) (models
 	return wallet, err
 }
 
-func (s *WalletService) GetTransactions(ctx context.Context, userID string, limit int) ([]models.WalletTransaction, error) {
+// GetTransactions returns the user's latest transactions. With includeArchived the
+// archived ledger is merged in, for full history beyond the retention window.
+func (s *WalletService) GetTransactions(ctx context.Context, userID string, limit int, includeArchived bool) ([]models.WalletTransaction, error) {
 	query := `
-		SELECT * FROM wallet_transactions 
+		SELECT ` + walletTransactionColumns + ` FROM wallet_transactions 
 		WHERE user_id = $1 
 		ORDER BY created_at DESC 
 		LIMIT $2`
 
+	if includeArchived {
+		query = `
+			SELECT ` + walletTransactionColumns + `, false AS is_archived
+			FROM wallet_transactions
+			WHERE user_id = $1
+			UNION ALL
+			SELECT ` + walletTransactionColumns + `, true AS is_archived
+			FROM wallet_transactions_archive
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2`
+	}
+
 	var transactions []models.WalletTransaction
 	err := s.db.SelectContext(ctx, &transactions, query, userID, limit)
 	return transactions, err
@@ -100,69 +155,141 @@ func (s *WalletService) AddCoins(ctx context.Context, userID string, coinAmount
 	}
 	defer tx.Rollback()
 
-	// UPDATED: Get current balance from wallets table only (not users table)
-	var currentBalance int
-	err = tx.QueryRowContext(ctx, "SELECT coins_balance FROM wallets WHERE user_id = $1", userID).Scan(&currentBalance)
+	if description == "" {
+		description = "Admin added coins"
+	}
+
+	transaction, err := s.Credit(ctx, tx, userID, coinAmount, "admin_credit", models.WalletLedgerMeta{
+		Description: description,
+		AdminNote:   &adminNote,
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	newBalance := currentBalance + coinAmount
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return transaction.BalanceAfter, nil
+}
+
+// ===============================
+// TRANSACTIONAL BALANCE CHANGES
+// ===============================
+
+// walletLedgerOwner holds the wallet columns copied onto every ledger row
+type walletLedgerOwner struct {
+	WalletID        string `db:"wallet_id"`
+	UserPhoneNumber string `db:"user_phone_number"`
+	UserName        string `db:"user_name"`
+	CoinsBalance    int    `db:"coins_balance"`
+}
+
+// Debit atomically removes coins from a wallet inside the caller's transaction and
+// records the ledger row. The balance is never allowed to go negative: the UPDATE
+// only matches when coins_balance >= amount, so concurrent spends cannot overdraw.
+func (s *WalletService) Debit(ctx context.Context, tx *sqlx.Tx, userID string, amount int, txType string, meta models.WalletLedgerMeta) (*models.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
+
+	var owner walletLedgerOwner
+	err := tx.GetContext(ctx, &owner, `
+		UPDATE wallets 
+		SET coins_balance = coins_balance - $2, updated_at = CURRENT_TIMESTAMP 
+		WHERE user_id = $1 AND coins_balance >= $2
+		RETURNING wallet_id, user_phone_number, user_name, coins_balance`,
+		userID, amount)
+	if err == sql.ErrNoRows {
+		var current int
+		err := tx.GetContext(ctx, &current, "SELECT coins_balance FROM wallets WHERE user_id = $1", userID)
+		if err == sql.ErrNoRows {
+			return nil, errors.New("wallet_not_found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, &InsufficientBalanceError{Required: amount, Current: current}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.writeLedgerEntry(ctx, tx, userID, owner, -amount, txType, meta)
+}
 
-	// REMOVED: Update user balance
-	// _, err = tx.ExecContext(ctx,
-	// 	"UPDATE users SET coins_balance = $1, updated_at = $2 WHERE uid = $3",
-	// 	newBalance, time.Now(), userID)
-	// if err != nil {
-	// 	return 0, err
-	// }
+// Credit atomically adds coins to a wallet inside the caller's transaction and
+// records the ledger row.
+func (s *WalletService) Credit(ctx context.Context, tx *sqlx.Tx, userID string, amount int, txType string, meta models.WalletLedgerMeta) (*models.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("invalid_amount")
+	}
 
-	// Update wallet only (single source of truth)
-	_, err = tx.ExecContext(ctx,
-		"UPDATE wallets SET coins_balance = $1, updated_at = $2 WHERE user_id = $3",
-		newBalance, time.Now(), userID)
+	var owner walletLedgerOwner
+	err := tx.GetContext(ctx, &owner, `
+		UPDATE wallets 
+		SET coins_balance = coins_balance + $2, updated_at = CURRENT_TIMESTAMP 
+		WHERE user_id = $1
+		RETURNING wallet_id, user_phone_number, user_name, coins_balance`,
+		userID, amount)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("wallet_not_found")
+	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	// Create transaction record
-	transactionID := uuid.New().String()
-	if description == "" {
-		description = "Admin added coins"
+	return s.writeLedgerEntry(ctx, tx, userID, owner, amount, txType, meta)
+}
+
+// writeLedgerEntry inserts the wallet_transactions row for a balance change of
+// signedAmount (negative for debits). owner.CoinsBalance is the balance after the change.
+func (s *WalletService) writeLedgerEntry(ctx context.Context, tx *sqlx.Tx, userID string, owner walletLedgerOwner, signedAmount int, txType string, meta models.WalletLedgerMeta) (*models.WalletTransaction, error) {
+	metadata := meta.Metadata
+	if metadata == nil {
+		metadata = models.MetadataMap{}
 	}
 
-	transaction := models.WalletTransaction{
-		TransactionID: transactionID,
-		WalletID:      userID,
-		UserID:        userID,
-		Type:          "admin_credit",
-		CoinAmount:    coinAmount,
-		BalanceBefore: currentBalance,
-		BalanceAfter:  newBalance,
-		Description:   description,
-		AdminNote:     &adminNote,
-		CreatedAt:     time.Now(),
+	transaction := &models.WalletTransaction{
+		TransactionID:    uuid.New().String(),
+		WalletID:         owner.WalletID,
+		UserID:           userID,
+		UserPhoneNumber:  owner.UserPhoneNumber,
+		UserName:         owner.UserName,
+		Type:             txType,
+		CoinAmount:       signedAmount,
+		BalanceBefore:    owner.CoinsBalance - signedAmount,
+		BalanceAfter:     owner.CoinsBalance,
+		Description:      meta.Description,
+		ReferenceID:      meta.ReferenceID,
+		AdminNote:        meta.AdminNote,
+		PaymentMethod:    meta.PaymentMethod,
+		PaymentReference: meta.PaymentReference,
+		PackageID:        meta.PackageID,
+		PaidAmount:       meta.PaidAmount,
+		Metadata:         metadata,
+		CreatedAt:        time.Now(),
 	}
 
 	query := `
 		INSERT INTO wallet_transactions (
-			transaction_id, wallet_id, user_id, type, coin_amount,
-			balance_before, balance_after, description, admin_note, created_at
+			transaction_id, wallet_id, user_id, user_phone_number, user_name,
+			type, coin_amount, balance_before, balance_after, description,
+			reference_id, admin_note, payment_method, payment_reference,
+			package_id, paid_amount, metadata, created_at
 		) VALUES (
-			:transaction_id, :wallet_id, :user_id, :type, :coin_amount,
-			:balance_before, :balance_after, :description, :admin_note, :created_at
+			:transaction_id, :wallet_id, :user_id, :user_phone_number, :user_name,
+			:type, :coin_amount, :balance_before, :balance_after, :description,
+			:reference_id, :admin_note, :payment_method, :payment_reference,
+			:package_id, :paid_amount, :metadata, :created_at
 		)`
 
-	_, err = tx.NamedExecContext(ctx, query, transaction)
-	if err != nil {
-		return 0, err
-	}
-
-	if err = tx.Commit(); err != nil {
-		return 0, err
+	if _, err := tx.NamedExecContext(ctx, query, transaction); err != nil {
+		return nil, err
 	}
 
-	return newBalance, nil
+	return transaction, nil
 }
 
 func (s *WalletService) GetPendingPurchases(ctx context.Context, limit int) ([]models.CoinPurchaseRequest, error) {
@@ -192,16 +319,26 @@ func (s *WalletService) approvePurchaseRequest(ctx context.Context, requestID, a
 	}
 	defer tx.Rollback()
 
-	// Get purchase request
+	// Lock the purchase request so concurrent approvals can't credit twice
 	var request models.CoinPurchaseRequest
-	err = tx.GetContext(ctx, &request, "SELECT * FROM coin_purchase_requests WHERE id = $1", requestID)
+	err = tx.GetContext(ctx, &request, "SELECT * FROM coin_purchase_requests WHERE id = $1 FOR UPDATE", requestID)
 	if err != nil {
 		return err
 	}
+	if request.Status != "pending_admin_verification" {
+		return errors.New("request_already_processed")
+	}
 
 	// Add coins to user account
-	_, err = s.AddCoins(ctx, request.UserID, request.CoinAmount,
-		"Coin purchase approved", adminNote)
+	_, err = s.Credit(ctx, tx, request.UserID, request.CoinAmount, "admin_credit", models.WalletLedgerMeta{
+		Description:      "Coin purchase approved",
+		ReferenceID:      &request.ID,
+		AdminNote:        &adminNote,
+		PaymentMethod:    &request.PaymentMethod,
+		PaymentReference: &request.PaymentReference,
+		PackageID:        &request.PackageID,
+		PaidAmount:       &request.PaidAmount,
+	})
 	if err != nil {
 		return err
 	}
@@ -216,6 +353,20 @@ func (s *WalletService) approvePurchaseRequest(ctx context.Context, requestID, a
 		return err
 	}
 
+	err = s.webhookService.Enqueue(ctx, tx, models.WebhookEventPurchaseApproved, models.PurchaseApprovedWebhookData{
+		RequestID:        request.ID,
+		UserID:           request.UserID,
+		PackageID:        request.PackageID,
+		CoinAmount:       request.CoinAmount,
+		PaidAmount:       request.PaidAmount,
+		PaymentMethod:    request.PaymentMethod,
+		PaymentReference: request.PaymentReference,
+		ApprovedAt:       now,
+	})
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -0,0 +1,104 @@
+// ===============================
+// internal/services/wallet_archive.go - Wallet Ledger Archival
+// ===============================
+
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// walletTransactionColumns is the column list shared by wallet_transactions and
+// wallet_transactions_archive
+const walletTransactionColumns = `transaction_id, wallet_id, user_id, user_phone_number, user_name,
+	type, coin_amount, balance_before, balance_after, description, reference_id, admin_note,
+	payment_method, payment_reference, package_id, paid_amount, metadata, created_at`
+
+// ArchiveTransactions moves transactions created before cutoff into
+// wallet_transactions_archive in batches and returns how many rows were moved.
+// Each batch is a single statement, so a row is never lost or duplicated.
+func (s *WalletService) ArchiveTransactions(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	query := `
+		WITH moved AS (
+			DELETE FROM wallet_transactions
+			WHERE transaction_id IN (
+				SELECT transaction_id FROM wallet_transactions
+				WHERE created_at < $1
+				ORDER BY created_at
+				LIMIT $2
+			)
+			RETURNING ` + walletTransactionColumns + `
+		)
+		INSERT INTO wallet_transactions_archive (` + walletTransactionColumns + `)
+		SELECT ` + walletTransactionColumns + ` FROM moved
+		ON CONFLICT (transaction_id) DO NOTHING`
+
+	var total int64
+	for {
+		result, err := s.db.ExecContext(ctx, query, cutoff, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		moved, _ := result.RowsAffected()
+		total += moved
+		if moved < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// StartTransactionArchiver archives transactions older than afterMonths every interval.
+// afterMonths <= 0 disables archival. The returned function stops the job.
+func (s *WalletService) StartTransactionArchiver(afterMonths int, interval time.Duration, batchSize int) func() {
+	if afterMonths <= 0 {
+		log.Println("🗄️ Wallet transaction archival disabled")
+		return func() {}
+	}
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func() {
+		cutoff := time.Now().AddDate(0, -afterMonths, 0)
+		moved, err := s.ArchiveTransactions(ctx, cutoff, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("⚠️ Wallet transaction archival failed after moving %d rows: %v", moved, err)
+			}
+			return
+		}
+		if moved > 0 {
+			log.Printf("🗄️ Archived %d wallet transactions older than %s", moved, cutoff.Format("2006-01-02"))
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("🗄️ Wallet transaction archival started (older than %d months, every %s)", afterMonths, interval)
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
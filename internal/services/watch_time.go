@@ -0,0 +1,134 @@
+// ===============================
+// internal/services/watch_time.go - Watch Time and Completion Rate
+// ===============================
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// A viewer's repeat sessions on the same video within this window are not counted again
+const watchSessionWindow = 30 * time.Minute
+
+// RecordWatchSession stores one playback session and folds its completion rate into the
+// video's running average. The duration comes from the stored video, never the client,
+// and each viewer counts once per video per watchSessionWindow ("duplicate_watch_session").
+// Watched time beyond the duration (replays) counts as a full watch. Videos whose
+// duration hasn't been probed yet return "duration_unknown" and start a probe. Returns
+// the updated average.
+func (s *VideoService) RecordWatchSession(ctx context.Context, videoID, userID string, watchedMs int64) (float64, error) {
+	if watchedMs < 0 {
+		return 0, errors.New("invalid_watch_time")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// The row lock also serialises a viewer's concurrent sessions for the duplicate check
+	var video struct {
+		VideoURL   string        `db:"video_url"`
+		DurationMs sql.NullInt64 `db:"duration_ms"`
+	}
+	err = tx.GetContext(ctx, &video,
+		"SELECT video_url, duration_ms FROM videos WHERE id = $1 AND is_active = true FOR UPDATE", videoID)
+	if err == sql.ErrNoRows {
+		return 0, errors.New("video_not_found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !video.DurationMs.Valid || video.DurationMs.Int64 <= 0 {
+		go s.probeVideoDuration(videoID, video.VideoURL)
+		return 0, errors.New("duration_unknown")
+	}
+
+	var duplicate bool
+	err = tx.GetContext(ctx, &duplicate, `
+		SELECT EXISTS(
+			SELECT 1 FROM video_watch_sessions
+			WHERE video_id = $1 AND user_id = $2 AND created_at > $3
+		)`, videoID, userID, time.Now().Add(-watchSessionWindow))
+	if err != nil {
+		return 0, err
+	}
+	if duplicate {
+		return 0, errors.New("duplicate_watch_session")
+	}
+
+	durationMs := video.DurationMs.Int64
+	completionRate := math.Min(float64(watchedMs)/float64(durationMs), 1)
+
+	var avgCompletionRate float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE videos
+		SET avg_completion_rate = (avg_completion_rate * watch_sessions_count + $2) / (watch_sessions_count + 1),
+		    watch_sessions_count = watch_sessions_count + 1
+		WHERE id = $1
+		RETURNING avg_completion_rate`,
+		videoID, completionRate).Scan(&avgCompletionRate)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO video_watch_sessions (id, video_id, user_id, watched_ms, duration_ms, completion_rate)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New().String(), videoID, userID, watchedMs, durationMs, completionRate)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return avgCompletionRate, nil
+}
+
+// probeVideoDuration reads a video's duration with ffprobe and stores it. Only one probe
+// per video runs at a time; a failed probe is retried by the next watch session. The stored
+// URL is creator input, so only objects in our bucket are probed, by their rebuilt public URL.
+func (s *VideoService) probeVideoDuration(videoID, videoURL string) {
+	if s.r2Client == nil {
+		return
+	}
+	key, ok := s.r2Client.KeyFromURL(videoURL)
+	if !ok {
+		return
+	}
+	if _, running := s.durationProbes.LoadOrStore(videoID, struct{}{}); running {
+		return
+	}
+	defer s.durationProbes.Delete(videoID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout+5*time.Second)
+	defer cancel()
+
+	var check models.MediaCheck
+	if err := probeVideo(ctx, s.r2Client.GetPublicURL(key), &check); err != nil || check.DurationSeconds == nil {
+		slog.Warn("failed to probe video duration", "video_id", videoID, "error", err)
+		return
+	}
+
+	durationMs := int64(*check.DurationSeconds * 1000)
+	if durationMs <= 0 {
+		return
+	}
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE videos SET duration_ms = $2 WHERE id = $1", videoID, durationMs); err != nil {
+		slog.Warn("failed to store video duration", "video_id", videoID, "error", err)
+	}
+}
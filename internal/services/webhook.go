@@ -0,0 +1,463 @@
+// ===============================
+// internal/services/webhook.go - Outbound Webhooks
+// ===============================
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"weibaobe/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+
+	// Extra time on top of the worst-case send time of a claimed batch before its
+	// deliveries can be claimed again by another worker
+	webhookClaimLeaseMargin = time.Minute
+
+	// Bytes of a failed response body kept in the delivery log
+	webhookErrorBodyLimit = 512
+)
+
+// WebhookService manages webhook subscriptions and delivers queued events. Events are
+// queued with Enqueue inside the caller's transaction and sent by the delivery worker.
+// A nil *WebhookService queues nothing.
+type WebhookService struct {
+	db          *sqlx.DB
+	client      *http.Client
+	maxAttempts int
+}
+
+func NewWebhookService(db *sqlx.DB, timeout time.Duration, maxAttempts int) *WebhookService {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	dialer := &net.Dialer{Timeout: timeout, Control: rejectInternalAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &WebhookService{
+		db: db,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// A redirect could point the delivery back at an internal host
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// claimLease is how long a claimed batch stays reserved: long enough for every delivery
+// in it to time out one after another, so a slow batch isn't picked up and sent twice
+func (s *WebhookService) claimLease(batchSize int) time.Duration {
+	return time.Duration(batchSize)*s.client.Timeout + webhookClaimLeaseMargin
+}
+
+// ===============================
+// SUBSCRIPTIONS
+// ===============================
+
+// CreateWebhook subscribes a URL to events and generates its signing secret
+func (s *WebhookService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest, adminID string) (*models.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook models.Webhook
+	err = s.db.GetContext(ctx, &webhook, `
+		INSERT INTO webhooks (url, secret, events, description, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *`,
+		req.URL, secret, models.StringSlice(req.Events), req.Description, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks returns every subscription, newest first
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks := []models.Webhook{}
+	err := s.db.SelectContext(ctx, &webhooks, `SELECT * FROM webhooks ORDER BY created_at DESC`)
+	return webhooks, err
+}
+
+// UpdateWebhook applies the fields set in req
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID string, req models.UpdateWebhookRequest) (*models.Webhook, error) {
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	var events interface{}
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+		events = models.StringSlice(req.Events)
+	}
+
+	var webhook models.Webhook
+	err := s.db.GetContext(ctx, &webhook, `
+		UPDATE webhooks SET
+			url = COALESCE($2, url),
+			events = COALESCE($3::text[], events),
+			description = COALESCE($4, description),
+			is_active = COALESCE($5, is_active),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING *`,
+		webhookID, req.URL, events, req.Description, req.IsActive)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("webhook_not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook removes a subscription and its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, webhookID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("webhook_not_found")
+	}
+	return nil
+}
+
+// GetDeliveries returns a webhook's delivery log, newest first, optionally filtered by status
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID, status string, limit, offset int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := s.db.SelectContext(ctx, &deliveries, `
+		SELECT * FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`, webhookID, status, limit, offset)
+	return deliveries, err
+}
+
+// validateWebhookURL accepts absolute https URLs whose host doesn't resolve to a
+// loopback, private or otherwise internal address
+func validateWebhookURL(raw string) error {
+	u, err := neturl.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return errors.New("invalid_url")
+	}
+
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return errors.New("invalid_url")
+	}
+	for _, ip := range ips {
+		if isInternalIP(ip) {
+			return errors.New("invalid_url")
+		}
+	}
+	return nil
+}
+
+// rejectInternalAddress is the delivery dialer's Control hook. It checks the resolved
+// address being connected to, so a hostname re-pointed at an internal address after
+// the webhook was saved is still refused.
+func rejectInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+	return nil
+}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return errors.New("invalid_event")
+	}
+	for _, event := range events {
+		if !models.IsValidWebhookEvent(event) {
+			return errors.New("invalid_event")
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// ===============================
+// QUEUEING
+// ===============================
+
+// Enqueue records event for every active webhook subscribed to it. Call it with the
+// transaction that makes the event happen so the event is queued if and only if it
+// commits.
+func (s *WebhookService) Enqueue(ctx context.Context, tx *sqlx.Tx, event string, data interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $1, $2 FROM webhooks
+		WHERE is_active = true AND $1 = ANY(events)`,
+		event, payload)
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook: %w", err)
+	}
+	return nil
+}
+
+// ===============================
+// DELIVERY
+// ===============================
+
+type dueWebhookDelivery struct {
+	ID       string `db:"id"`
+	Event    string `db:"event"`
+	Payload  []byte `db:"payload"`
+	Attempts int    `db:"attempts"`
+	URL      string `db:"url"`
+	Secret   string `db:"secret"`
+}
+
+// DeliverDue sends up to batchSize deliveries whose next attempt is due and returns how
+// many succeeded. Claimed rows are leased for the batch's worst-case send time so
+// concurrent workers don't send them twice.
+func (s *WebhookService) DeliverDue(ctx context.Context, batchSize int) (int, error) {
+	var due []dueWebhookDelivery
+	err := s.db.SelectContext(ctx, &due, `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.event, d.payload, d.attempts, w.url, w.secret`,
+		batchSize, s.claimLease(batchSize).Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.deliver(ctx, delivery) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver POSTs one payload and records the outcome, scheduling a retry with exponential
+// backoff until maxAttempts is reached
+func (s *WebhookService) deliver(ctx context.Context, delivery dueWebhookDelivery) bool {
+	statusCode, sendErr := s.send(ctx, delivery)
+	attempts := delivery.Attempts + 1
+
+	var statusCodeArg *int
+	if statusCode > 0 {
+		statusCodeArg = &statusCode
+	}
+
+	var err error
+	if sendErr == nil {
+		_, err = s.db.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET status = 'delivered', attempts = $2, last_status_code = $3, last_error = NULL,
+			    delivered_at = NOW(), next_attempt_at = NULL
+			WHERE id = $1`, delivery.ID, attempts, statusCodeArg)
+	} else if attempts >= s.maxAttempts {
+		log.Printf("⚠️ Webhook delivery %s (%s) failed permanently after %d attempts: %v",
+			delivery.ID, delivery.Event, attempts, sendErr)
+		_, err = s.db.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET status = 'failed', attempts = $2, last_status_code = $3, last_error = $4,
+			    next_attempt_at = NULL
+			WHERE id = $1`, delivery.ID, attempts, statusCodeArg, sendErr.Error())
+	} else {
+		_, err = s.db.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET attempts = $2, last_status_code = $3, last_error = $4,
+			    next_attempt_at = NOW() + make_interval(secs => $5)
+			WHERE id = $1`, delivery.ID, attempts, statusCodeArg, sendErr.Error(),
+			webhookBackoff(attempts).Seconds())
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("⚠️ Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+
+	return sendErr == nil
+}
+
+// send POSTs the signed payload and returns the response status. Any non-2xx response
+// is an error.
+func (s *WebhookService) send(ctx context.Context, delivery dueWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Weibao-Webhooks/1.0")
+	req.Header.Set(models.WebhookEventHeader, delivery.Event)
+	req.Header.Set(models.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(models.WebhookTimestampHeader, timestamp)
+	req.Header.Set(models.WebhookSignatureHeader, SignWebhookPayload(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+		return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value for body sent at timestamp.
+// Receivers recompute it with their copy of the secret and compare in constant time.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff doubles from webhookBaseBackoff after each failed attempt, capped at
+// webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
+
+// StartDeliveryWorker sends due webhook deliveries every interval. interval <= 0
+// disables delivery (events are still queued). The returned function stops the worker.
+func (s *WebhookService) StartDeliveryWorker(interval time.Duration, batchSize int) func() {
+	if interval <= 0 {
+		log.Println("🪝 Webhook delivery disabled")
+		return func() {}
+	}
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func() {
+		// Keep draining while full batches come back so a backlog clears quickly
+		for ctx.Err() == nil {
+			delivered, err := s.DeliverDue(ctx, batchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("⚠️ Webhook delivery run failed: %v", err)
+				}
+				return
+			}
+			if delivered < batchSize {
+				return
+			}
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("🪝 Webhook delivery worker started (every %s, %d attempts max)", interval, s.maxAttempts)
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
@@ -0,0 +1,137 @@
+// ===============================
+// internal/storage/breaker.go - Circuit Breaker for R2 Calls
+// ===============================
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrStorageUnavailable is returned without contacting R2 while the breaker is open
+var ErrStorageUnavailable = errors.New("storage_unavailable")
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"    // calls go through
+	BreakerOpen     = "open"      // calls fail fast until the cooldown passes
+	BreakerHalfOpen = "half_open" // one trial call decides whether to close or re-open
+)
+
+// BreakerState is a snapshot of the breaker for health reporting
+type BreakerState struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	RetryAt             *time.Time `json:"retryAt,omitempty"`
+}
+
+// CircuitBreaker opens after threshold consecutive failures. While open every call fails
+// with ErrStorageUnavailable; after cooldown a single trial call is let through and its
+// outcome closes or re-opens the breaker.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	state    string
+	failures int
+	openedAt time.Time
+	trialOut bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, state: BreakerClosed}
+}
+
+// Allow reports whether a call may proceed
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrStorageUnavailable
+		}
+		b.state = BreakerHalfOpen
+		b.trialOut = true
+		return nil
+	case BreakerHalfOpen:
+		if b.trialOut {
+			return ErrStorageUnavailable
+		}
+		b.trialOut = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Check reports ErrStorageUnavailable while the breaker is open and still cooling down,
+// without taking the half-open trial slot. It is for work that never reaches R2 and so
+// has no outcome to Record.
+func (b *CircuitBreaker) Check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) < b.cooldown {
+		return ErrStorageUnavailable
+	}
+	return nil
+}
+
+// Record feeds a call's outcome back into the breaker. Only outages count as failures:
+// client errors such as a missing object mean R2 answered.
+func (b *CircuitBreaker) Record(err error) {
+	failed := isOutage(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialOut = false
+	if !failed {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns a snapshot for health reporting
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BreakerState{State: b.state, ConsecutiveFailures: b.failures}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.cooldown)
+		state.OpenedAt = &openedAt
+		state.RetryAt = &retryAt
+	}
+	return state
+}
+
+// isOutage reports whether err means R2 could not serve the request: network errors and
+// 5xx responses. A cancelled caller or a 4xx response is not an outage.
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return reqErr.StatusCode() >= 500
+	}
+	return true
+}
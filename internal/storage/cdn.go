@@ -0,0 +1,115 @@
+// ===============================
+// internal/storage/cdn.go - CDN Cache Purging
+// ===============================
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"weibaobe/internal/config"
+)
+
+// CDNPurger evicts cached copies of public asset URLs so edits propagate immediately
+type CDNPurger interface {
+	PurgeURLs(ctx context.Context, urls []string) error
+}
+
+// NewCDNPurger builds the purger for the configured provider. An empty provider or
+// "none" returns a no-op purger.
+func NewCDNPurger(cfg config.CDNConfig) (CDNPurger, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "", "none":
+		return NoopCDNPurger{}, nil
+	case "cloudflare":
+		if cfg.CloudflareZoneID == "" || cfg.CloudflareAPIToken == "" {
+			return nil, fmt.Errorf("cloudflare CDN purging requires CLOUDFLARE_ZONE_ID and CLOUDFLARE_API_TOKEN")
+		}
+		return NewCloudflarePurger(cfg.CloudflareZoneID, cfg.CloudflareAPIToken), nil
+	default:
+		return nil, fmt.Errorf("unknown CDN provider %q", cfg.Provider)
+	}
+}
+
+// NoopCDNPurger is used when no CDN sits in front of the bucket
+type NoopCDNPurger struct{}
+
+func (NoopCDNPurger) PurgeURLs(ctx context.Context, urls []string) error {
+	return nil
+}
+
+// Cloudflare accepts at most this many files per purge request
+const cloudflarePurgeBatchSize = 30
+
+// CloudflarePurger purges files through the Cloudflare zone purge_cache API
+type CloudflarePurger struct {
+	zoneID     string
+	apiToken   string
+	httpClient *http.Client
+}
+
+func NewCloudflarePurger(zoneID, apiToken string) *CloudflarePurger {
+	return &CloudflarePurger{
+		zoneID:     zoneID,
+		apiToken:   apiToken,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *CloudflarePurger) PurgeURLs(ctx context.Context, urls []string) error {
+	for start := 0; start < len(urls); start += cloudflarePurgeBatchSize {
+		end := start + cloudflarePurgeBatchSize
+		if end > len(urls) {
+			end = len(urls)
+		}
+		if err := p.purgeBatch(ctx, urls[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *CloudflarePurger) purgeBatch(ctx context.Context, urls []string) error {
+	body, err := json.Marshal(map[string][]string{"files": urls})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/purge_cache", p.zoneID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare purge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(respBody, &result); err != nil || resp.StatusCode != http.StatusOK || !result.Success {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("cloudflare purge failed (%d): %s", result.Errors[0].Code, result.Errors[0].Message)
+		}
+		return fmt.Errorf("cloudflare purge failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
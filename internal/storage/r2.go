@@ -6,22 +6,39 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"weibaobe/internal/config"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+// R2 calls are retried by the SDK with exponential backoff and jitter on throttling, 5xx
+// and network errors. After r2BreakerThreshold consecutive outages the breaker fails calls
+// fast for r2BreakerCooldown so requests don't pile up behind a Cloudflare incident.
+const (
+	r2MaxRetries       = 3
+	r2MinRetryDelay    = 100 * time.Millisecond
+	r2MaxRetryDelay    = 2 * time.Second
+	r2BreakerThreshold = 5
+	r2BreakerCooldown  = 30 * time.Second
+)
+
 type R2Client struct {
 	client     *s3.S3
 	bucketName string
 	publicURL  string
+	breaker    *CircuitBreaker
 }
 
 func (r *R2Client) UploadFileWithProgress(ctx context.Context, uniqueFilename string, progressReader io.Reader, contentType string, totalSize int64) any {
@@ -30,32 +47,55 @@ func (r *R2Client) UploadFileWithProgress(ctx context.Context, uniqueFilename st
 
 func NewR2Client(cfg config.R2Config) (*R2Client, error) {
 	// Create AWS session configured for R2
-	sess, err := session.NewSession(&aws.Config{
+	sess, err := session.NewSession(request.WithRetryer(&aws.Config{
 		Region:           aws.String("auto"),
 		Endpoint:         aws.String(fmt.Sprintf("https://%s.r2.cloudflarestorage.com", cfg.AccountID)),
 		Credentials:      credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
 		S3ForcePathStyle: aws.Bool(true),
-	})
+	}, client.DefaultRetryer{
+		NumMaxRetries: r2MaxRetries,
+		MinRetryDelay: r2MinRetryDelay,
+		MaxRetryDelay: r2MaxRetryDelay,
+	}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create R2 session: %w", err)
 	}
 
-	client := s3.New(sess)
+	s3Client := s3.New(sess)
 
 	return &R2Client{
-		client:     client,
+		client:     s3Client,
 		bucketName: cfg.BucketName,
 		publicURL:  cfg.PublicURL,
+		breaker:    NewCircuitBreaker(r2BreakerThreshold, r2BreakerCooldown),
 	}, nil
 }
 
+// call runs one R2 operation through the circuit breaker
+func (r *R2Client) call(fn func() error) error {
+	if err := r.breaker.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	r.breaker.Record(err)
+	return err
+}
+
+// BreakerState reports the R2 circuit breaker for health checks
+func (r *R2Client) BreakerState() BreakerState {
+	return r.breaker.State()
+}
+
 func (r *R2Client) UploadFile(ctx context.Context, key string, file io.Reader, contentType string) error {
-	_, err := r.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(r.bucketName),
-		Key:         aws.String(key),
-		Body:        aws.ReadSeekCloser(file),
-		ContentType: aws.String(contentType),
-		ACL:         aws.String("public-read"), // Make files publicly readable
+	err := r.call(func() error {
+		_, err := r.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(r.bucketName),
+			Key:         aws.String(key),
+			Body:        aws.ReadSeekCloser(file),
+			ContentType: aws.String(contentType),
+			ACL:         aws.String("public-read"), // Make files publicly readable
+		})
+		return err
 	})
 
 	if err != nil {
@@ -65,10 +105,39 @@ func (r *R2Client) UploadFile(ctx context.Context, key string, file io.Reader, c
 	return nil
 }
 
+// PresignPutURL returns a time-limited URL that lets a client PUT an object directly
+// to the bucket. The Content-Type header is part of the signature, so the client must
+// send the same value.
+func (r *R2Client) PresignPutURL(key, contentType string, contentLength int64, expires time.Duration) (string, error) {
+	// Signing is local, but a URL for an unreachable bucket only moves the failure to the
+	// client. Check rather than Allow: there is no R2 call whose outcome could release a
+	// half-open trial slot.
+	if err := r.breaker.Check(); err != nil {
+		return "", fmt.Errorf("failed to presign R2 upload: %w", err)
+	}
+
+	req, _ := r.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(r.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(contentLength),
+	})
+
+	url, err := req.Presign(expires)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign R2 upload: %w", err)
+	}
+
+	return url, nil
+}
+
 func (r *R2Client) DeleteFile(ctx context.Context, key string) error {
-	_, err := r.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(r.bucketName),
-		Key:    aws.String(key),
+	err := r.call(func() error {
+		_, err := r.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+		return err
 	})
 
 	if err != nil {
@@ -82,10 +151,126 @@ func (r *R2Client) GetPublicURL(key string) string {
 	return fmt.Sprintf("%s/%s", r.publicURL, key)
 }
 
-func (r *R2Client) FileExists(ctx context.Context, key string) (bool, error) {
-	_, err := r.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+// KeyFromURL returns the object key for a public URL served from this bucket
+func (r *R2Client) KeyFromURL(url string) (string, bool) {
+	prefix := r.publicURL + "/"
+	if r.publicURL == "" || !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(url, prefix)
+	if i := strings.IndexAny(key, "?#"); i >= 0 {
+		key = key[:i]
+	}
+	return key, key != ""
+}
+
+// ObjectStream is an open (possibly partial) object body read from the bucket
+type ObjectStream struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+	ContentRange  string // set when a byte range was served
+	ETag          string
+	LastModified  time.Time
+}
+
+// ErrInvalidRange is returned when the requested byte range cannot be satisfied
+var ErrInvalidRange = errors.New("invalid_range")
+
+// GetObjectStream opens an object for reading, forwarding an HTTP Range header
+// (e.g. "bytes=0-1023") so R2 only sends the requested bytes
+func (r *R2Client) GetObjectStream(ctx context.Context, key, byteRange string) (*ObjectStream, error) {
+	input := &s3.GetObjectInput{
 		Bucket: aws.String(r.bucketName),
 		Key:    aws.String(key),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+
+	var output *s3.GetObjectOutput
+	err := r.call(func() error {
+		var err error
+		output, err = r.client.GetObjectWithContext(ctx, input)
+		return err
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidRange" {
+			return nil, ErrInvalidRange
+		}
+		return nil, fmt.Errorf("failed to read file from R2: %w", err)
+	}
+
+	stream := &ObjectStream{
+		Body:          output.Body,
+		ContentType:   aws.StringValue(output.ContentType),
+		ContentLength: aws.Int64Value(output.ContentLength),
+		ContentRange:  aws.StringValue(output.ContentRange),
+		ETag:          aws.StringValue(output.ETag),
+	}
+	if output.LastModified != nil {
+		stream.LastModified = *output.LastModified
+	}
+
+	return stream, nil
+}
+
+// Ping issues a HeadBucket to confirm the bucket is reachable with our credentials. It
+// bypasses the breaker so health checks always report R2's real state.
+func (r *R2Client) Ping(ctx context.Context) error {
+	_, err := r.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucketName),
+	})
+	return err
+}
+
+// ObjectInfo is the metadata R2 returns for an object without reading its body
+type ObjectInfo struct {
+	ContentType   string
+	ContentLength int64
+	LastModified  time.Time
+	ETag          string // unquoted; the content MD5 for single-part uploads
+}
+
+// ErrObjectNotFound is returned by StatObject when the key does not exist
+var ErrObjectNotFound = errors.New("object_not_found")
+
+// StatObject issues a HEAD for key and returns its size, content type and ETag
+func (r *R2Client) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	var output *s3.HeadObjectOutput
+	err := r.call(func() error {
+		var err error
+		output, err = r.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+		return err
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		ContentType:   aws.StringValue(output.ContentType),
+		ContentLength: aws.Int64Value(output.ContentLength),
+		ETag:          strings.Trim(aws.StringValue(output.ETag), `"`),
+	}
+	if output.LastModified != nil {
+		info.LastModified = *output.LastModified
+	}
+	return info, nil
+}
+
+func (r *R2Client) FileExists(ctx context.Context, key string) (bool, error) {
+	err := r.call(func() error {
+		_, err := r.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+		return err
 	})
 
 	if err != nil {
@@ -5,14 +5,27 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"weibaobe/internal/config"
 	"weibaobe/internal/database"
 	"weibaobe/internal/handlers"
+	"weibaobe/internal/logging"
 	"weibaobe/internal/middleware"
+	"weibaobe/internal/models"
+	"weibaobe/internal/repositories"
 	"weibaobe/internal/services"
 	"weibaobe/internal/storage"
 
@@ -28,17 +41,29 @@ import (
 
 type RateLimiter struct {
 	visitors map[string]*Visitor
+	admins   map[string]adminStatus
 	mutex    sync.RWMutex
 }
 
+// adminStatus caches whether a user is an admin so authenticated requests don't hit the
+// database just to decide whether they are rate limited
+type adminStatus struct {
+	isAdmin   bool
+	checkedAt time.Time
+}
+
+const adminStatusTTL = 5 * time.Minute
+
 type Visitor struct {
 	requests int
 	lastSeen time.Time
+	window   time.Duration // the limiting rule's window; the entry is kept at least this long
 }
 
 func NewRateLimiter() *RateLimiter {
 	rl := &RateLimiter{
 		visitors: make(map[string]*Visitor),
+		admins:   make(map[string]adminStatus),
 	}
 
 	// Cleanup routine every 5 minutes
@@ -57,6 +82,7 @@ func (rl *RateLimiter) Allow(ip string, limit int, window time.Duration) bool {
 		rl.visitors[ip] = &Visitor{
 			requests: 1,
 			lastSeen: now,
+			window:   window,
 		}
 		return true
 	}
@@ -84,58 +110,150 @@ func (rl *RateLimiter) cleanup() {
 	rl.mutex.Lock()
 	defer rl.mutex.Unlock()
 
-	cutoff := time.Now().Add(-10 * time.Minute)
+	// An entry only matters until its own window has passed, which for some routes is
+	// hours; evicting sooner would reset the client's count early
+	now := time.Now()
 	for ip, visitor := range rl.visitors {
-		if visitor.lastSeen.Before(cutoff) {
+		if now.Sub(visitor.lastSeen) > visitor.window {
 			delete(rl.visitors, ip)
 		}
 	}
+
+	adminCutoff := time.Now().Add(-adminStatusTTL)
+	for userID, status := range rl.admins {
+		if status.checkedAt.Before(adminCutoff) {
+			delete(rl.admins, userID)
+		}
+	}
+}
+
+// IsAdmin reports whether the user is an admin, caching the answer for adminStatusTTL.
+// Lookup failures count as non-admin so the user is still rate limited.
+func (rl *RateLimiter) IsAdmin(ctx context.Context, userID string) bool {
+	rl.mutex.RLock()
+	status, ok := rl.admins[userID]
+	rl.mutex.RUnlock()
+	if ok && time.Since(status.checkedAt) < adminStatusTTL {
+		return status.isAdmin
+	}
+
+	var user models.User
+	err := database.GetDB().GetContext(ctx, &user, "SELECT role, user_type FROM users WHERE uid = $1", userID)
+	if err != nil && err != sql.ErrNoRows {
+		return false
+	}
+
+	rl.mutex.Lock()
+	rl.admins[userID] = adminStatus{isAdmin: err == nil && user.IsAdmin(), checkedAt: time.Now()}
+	rl.mutex.Unlock()
+	return err == nil && user.IsAdmin()
 }
 
 // ===============================
 // RATE LIMITING MIDDLEWARE
 // ===============================
 
-func createRateLimitMiddleware(rateLimiter *RateLimiter) gin.HandlerFunc {
+// rateLimitPolicy is a configured route pattern split into path segments
+type rateLimitPolicy struct {
+	pattern  string
+	segments []string
+	rule     config.RateLimitRule
+}
+
+// newRateLimitPolicies orders the configured patterns most specific first: exact paths,
+// then longer patterns, then patterns with fewer wildcards
+func newRateLimitPolicies(routes map[string]config.RateLimitRule) []rateLimitPolicy {
+	policies := make([]rateLimitPolicy, 0, len(routes))
+	for pattern, rule := range routes {
+		policies = append(policies, rateLimitPolicy{
+			pattern:  pattern,
+			segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+			rule:     rule,
+		})
+	}
+
+	wildcards := func(p rateLimitPolicy) int { return strings.Count(p.pattern, "*") }
+	sort.Slice(policies, func(i, j int) bool {
+		wi, wj := wildcards(policies[i]), wildcards(policies[j])
+		if (wi == 0) != (wj == 0) {
+			return wi == 0
+		}
+		if len(policies[i].segments) != len(policies[j].segments) {
+			return len(policies[i].segments) > len(policies[j].segments)
+		}
+		if wi != wj {
+			return wi < wj
+		}
+		return policies[i].pattern < policies[j].pattern
+	})
+
+	return policies
+}
+
+// matches reports whether the path segments fit the pattern. "*" matches one segment and
+// a trailing "*" matches one or more remaining segments.
+func (p rateLimitPolicy) matches(path []string) bool {
+	for i, segment := range p.segments {
+		if i >= len(path) {
+			return false
+		}
+		if segment == "*" {
+			if i == len(p.segments)-1 {
+				return true
+			}
+			continue
+		}
+		if segment != path[i] {
+			return false
+		}
+	}
+	return len(path) == len(p.segments)
+}
+
+// createRateLimitMiddleware limits signed-in users per user ID, so users sharing a carrier
+// NAT don't throttle each other, and anonymous requests per client IP. Admins are exempt.
+func createRateLimitMiddleware(rateLimiter *RateLimiter, limits config.RateLimitConfig, firebaseService *services.FirebaseService) gin.HandlerFunc {
+	policies := newRateLimitPolicies(limits.Routes)
+
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-
-		var limit int
-		var window time.Duration
-
-		path := c.Request.URL.Path
-		if path == "/api/v1/videos/bulk" {
-			limit = 30
-			window = time.Minute
-		} else if path == "/api/v1/videos/search" {
-			limit = 100
-			window = time.Minute
-		} else if path == "/api/v1/videos" ||
-			path == "/api/v1/videos/featured" ||
-			path == "/api/v1/videos/trending" {
-			limit = 100
-			window = time.Minute
-		} else {
-			limit = 200
-			window = time.Minute
+		client := "ip:" + c.ClientIP()
+		if userID := middleware.BearerUserID(c, firebaseService); userID != "" {
+			if rateLimiter.IsAdmin(c.Request.Context(), userID) {
+				c.Next()
+				return
+			}
+			client = "user:" + userID
 		}
 
-		if !rateLimiter.Allow(ip, limit, window) {
-			c.Header("X-RateLimit-Limit", string(rune(limit)))
+		// Each route policy has its own bucket; everything else shares the default one
+		key := client
+		rule := limits.Default
+		path := strings.Split(strings.Trim(c.Request.URL.Path, "/"), "/")
+		for _, policy := range policies {
+			if policy.matches(path) {
+				key = client + ":" + policy.pattern
+				rule = policy.rule
+				break
+			}
+		}
+
+		limit := strconv.Itoa(rule.Limit)
+		if !rateLimiter.Allow(key, rule.Limit, rule.Window) {
+			c.Header("X-RateLimit-Limit", limit)
 			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("Retry-After", "60")
+			c.Header("Retry-After", strconv.Itoa(int(rule.Window.Seconds())))
 
 			c.JSON(429, gin.H{
 				"error":   "Rate limit exceeded",
 				"message": "Too many requests, please try again later",
-				"limit":   limit,
-				"window":  window.String(),
+				"limit":   rule.Limit,
+				"window":  rule.Window.String(),
 			})
 			c.Abort()
 			return
 		}
 
-		c.Header("X-RateLimit-Limit", string(rune(limit)))
+		c.Header("X-RateLimit-Limit", limit)
 		c.Next()
 	}
 }
@@ -159,12 +277,37 @@ func main() {
 	// Set Gin mode
 	gin.SetMode(cfg.Environment)
 
+	// JSON logs; log.Printf output is routed through the same handler
+	logging.Init(cfg.Environment)
+
+	// Phone numbers without a country code are read in the configured region
+	if err := models.SetDefaultPhoneCountry(cfg.DefaultCountry); err != nil {
+		log.Fatal("Invalid DEFAULT_COUNTRY:", err)
+	}
+
+	if err := models.SetMaxImagesPerPost(cfg.Upload.MaxImagesPerPost); err != nil {
+		log.Fatal("Invalid UPLOAD_MAX_IMAGES_PER_POST:", err)
+	}
+
+	// Widths clients may request for CDN image transforms
+	if err := services.SetImageWidths(cfg.CDN.ImageWidths, cfg.CDN.DefaultImageWidth); err != nil {
+		log.Fatal("Invalid CDN_IMAGE_WIDTHS / CDN_DEFAULT_IMAGE_WIDTH:", err)
+	}
+
+	if err := services.SetMinSearchQueryLength(cfg.MinSearchQueryLength); err != nil {
+		log.Fatal("Invalid SEARCH_MIN_QUERY_LENGTH:", err)
+	}
+
+	// Deactivated accounts drop out of other users' follower/following counts
+	services.SetExcludeInactiveFollows(cfg.ExcludeInactiveFollows)
+
 	// Initialize database connection
 	db, err := database.Connect(cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer database.Close()
+	database.SetAdminStatementTimeout(cfg.Database.AdminStatementTimeout)
 
 	// Apply database optimizations
 	log.Println("📊 Applying database optimizations for video workload:")
@@ -177,6 +320,10 @@ func main() {
 	log.Printf("   • Connection lifetime: 10 minutes")
 	log.Printf("   • Idle timeout: 5 minutes")
 
+	// Watch for connections piling up (leaked rows, slow queries)
+	stopPoolMonitor := database.StartPoolMonitor(cfg.Database.PoolSampleInterval, cfg.Database.PoolInUseAlertRatio)
+	defer stopPoolMonitor()
+
 	// Run migrations
 	log.Println("🔧 Running database migrations...")
 	if err := database.RunMigrations(db); err != nil {
@@ -200,24 +347,71 @@ func main() {
 		log.Fatal("Failed to initialize R2 client:", err)
 	}
 
+	cdnPurger, err := storage.NewCDNPurger(cfg.CDN)
+	if err != nil {
+		log.Fatal("Failed to configure CDN purging:", err)
+	}
+
+	searchSafety, err := services.NewSearchSafetyPolicy(cfg.SearchSafetyRules)
+	if err != nil {
+		log.Fatal("Invalid SEARCH_SAFETY_RULES:", err)
+	}
+
 	// Initialize services
-	videoService := services.NewVideoService(db, r2Client)
-	walletService := services.NewWalletService(db)
-	userService := services.NewUserService(db)
-	uploadService := services.NewUploadService(r2Client)
+	videoService := services.NewVideoService(db, r2Client, cdnPurger, services.NewContentModerator(cfg.Moderation), cfg.DuplicateUploads, searchSafety,
+		services.NewActionThrottle(cfg.ActionThrottles))
+	webhookService := services.NewWebhookService(db, cfg.Webhooks.Timeout, cfg.Webhooks.MaxAttempts)
+	walletService := services.NewWalletService(db, webhookService)
+	profileCache := services.NewProfileCache(cfg.ProfileCacheTTL)
+	userService := services.NewUserService(db, profileCache)
+	uploadService := services.NewUploadService(r2Client, cfg.Upload)
+	adminService := services.NewAdminService(db)
+	auditService := services.NewAuditService(db)
+	giftService := services.NewGiftService(db, walletService, webhookService)
+	rewardService := services.NewRewardService(db, walletService, cfg.Rewards)
+	videoPurchaseService := services.NewVideoPurchaseService(db, walletService, webhookService)
+	notificationService := services.NewNotificationService(db)
+	blockService := services.NewBlockService(db, profileCache)
+	videoReactionsService := services.NewVideoReactionsService(
+		repositories.NewVideoReactionsRepository(db), userService, videoService, blockService)
+
+	// Move old ledger rows out of the hot wallet_transactions table
+	stopTransactionArchiver := walletService.StartTransactionArchiver(
+		cfg.Wallet.ArchiveAfterMonths, cfg.Wallet.ArchiveInterval, cfg.Wallet.ArchiveBatchSize)
+	defer stopTransactionArchiver()
+
+	// Rank trending videos in the background instead of on every request
+	stopTrendingRefresher := videoService.StartTrendingRefresher(cfg.Trending.RefreshInterval, cfg.Trending.Size)
+	defer stopTrendingRefresher()
+
+	// Repair denormalized counts that drifted from their source tables
+	stopCountReconciler := videoService.StartCountReconciler(cfg.CountReconcileInterval)
+	defer stopCountReconciler()
+
+	// Send queued gift and purchase events to subscribed webhooks
+	stopWebhookWorker := webhookService.StartDeliveryWorker(cfg.Webhooks.DeliveryInterval, cfg.Webhooks.BatchSize)
+	defer stopWebhookWorker()
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(firebaseService)
-	userHandler := handlers.NewUserHandler(db)
-	videoHandler := handlers.NewVideoHandler(videoService, userService)
-	walletHandler := handlers.NewWalletHandler(walletService)
+	authHandler := handlers.NewAuthHandler(firebaseService, userService)
+	userHandler := handlers.NewUserHandler(db, userService, auditService)
+	videoHandler := handlers.NewVideoHandler(videoService, userService, rewardService, notificationService, uploadService, videoPurchaseService, auditService, cfg.CacheTTLs)
+	walletHandler := handlers.NewWalletHandler(walletService, rewardService, auditService)
 	uploadHandler := handlers.NewUploadHandler(uploadService)
+	adminHandler := handlers.NewAdminHandler(adminService, auditService)
+	giftHandler := handlers.NewGiftHandler(giftService)
+	blockHandler := handlers.NewBlockHandler(blockService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	videoReactionsHandler := handlers.NewVideoReactionsHandler(videoReactionsService)
+	searchHandler := handlers.NewSearchHandler(videoService, userService)
+	healthHandler := handlers.NewHealthHandler(firebaseService, r2Client, profileCache)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, auditService)
 
 	// Initialize rate limiter
 	rateLimiter := NewRateLimiter()
 
 	// Setup router
-	router := setupOptimizedRouter(cfg, rateLimiter)
+	router := setupOptimizedRouter(cfg, rateLimiter, firebaseService)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -256,11 +450,21 @@ func main() {
 				"max_open":         50,
 				"max_idle":         25,
 			},
+			"pool_monitor": database.LastPoolSample(),
 		})
 	})
 
+	// Connection pool metrics (Prometheus text format), only for scrapers holding METRICS_TOKEN
+	if cfg.MetricsToken != "" {
+		router.GET("/metrics", middleware.StaticBearerToken(cfg.MetricsToken), func(c *gin.Context) {
+			c.Data(200, "text/plain; version=0.0.4; charset=utf-8", []byte(database.PoolMetricsText()))
+		})
+	} else {
+		log.Println("📉 /metrics disabled (METRICS_TOKEN not set)")
+	}
+
 	// Setup routes
-	setupRoutes(router, firebaseService, authHandler, userHandler, videoHandler, walletHandler, uploadHandler)
+	setupRoutes(router, firebaseService, authHandler, userHandler, videoHandler, walletHandler, uploadHandler, giftHandler, blockHandler, notificationHandler, adminHandler, videoReactionsHandler, searchHandler, healthHandler, webhookHandler)
 
 	// Start server
 	port := cfg.Port
@@ -282,27 +486,65 @@ func main() {
 	log.Printf("   • Per-user chat settings")
 	log.Printf("⚡ Performance optimizations:")
 	log.Printf("   • Gzip compression: ~70%% size reduction")
-	log.Printf("   • Rate limiting: 100 req/min")
+	log.Printf("   • Concurrent requests per client: %d", cfg.MaxConcurrentRequestsPerClient)
+	log.Printf("   • Rate limiting: %d route policies, default %d per %s", len(cfg.RateLimits.Routes), cfg.RateLimits.Default.Limit, cfg.RateLimits.Default.Window)
 	log.Printf("   • Connection pooling: optimized")
 	log.Printf("   • Bulk endpoints: 50 videos/request")
 	log.Printf("   • Smart caching: different TTLs")
 	log.Printf("   • Trigram search: 10-100x faster")
 
-	log.Fatal(router.Run(":" + port))
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM, then stop accepting connections and let in-flight requests
+	// (uploads, wallet transactions) finish. The deferred cleanup above - archiver, pool
+	// monitor and finally the database pool - runs once main returns.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	log.Printf("🛑 Received %s, draining in-flight requests (timeout %s)...", sig, cfg.ShutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Drain timed out, closing remaining connections: %v", err)
+		srv.Close()
+	} else {
+		log.Println("✅ In-flight requests drained")
+	}
+	log.Println("👋 Server stopped")
 }
 
 // ===============================
 // OPTIMIZED ROUTER SETUP
 // ===============================
 
-func setupOptimizedRouter(cfg *config.Config, rateLimiter *RateLimiter) *gin.Engine {
-	router := gin.Default()
+func setupOptimizedRouter(cfg *config.Config, rateLimiter *RateLimiter, firebaseService *services.FirebaseService) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Request IDs and structured access logs
+	router.Use(middleware.RequestLogger(cfg.Logging))
+
+	// Client-selected image transform width (?imageWidth=)
+	router.Use(middleware.ImageWidth())
 
 	// GZIP compression
 	router.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedExtensions([]string{".mp4", ".avi", ".mov", ".webm"})))
 
-	// Rate limiting
-	router.Use(createRateLimitMiddleware(rateLimiter))
+	// Per-client in-flight request cap, then windowed rate limiting
+	router.Use(middleware.ConcurrencyLimit(cfg.MaxConcurrentRequestsPerClient, firebaseService))
+	router.Use(createRateLimitMiddleware(rateLimiter, cfg.RateLimits, firebaseService))
 
 	// CORS
 	router.Use(cors.New(cors.Config{
@@ -312,11 +554,13 @@ func setupOptimizedRouter(cfg *config.Config, rateLimiter *RateLimiter) *gin.Eng
 			"Origin", "Content-Type", "Authorization",
 			"Range", "Accept-Ranges",
 			"Cache-Control", "If-None-Match", "If-Modified-Since",
+			"Idempotency-Key", middleware.RequestIDHeader,
 		},
 		ExposeHeaders: []string{
 			"Content-Length", "Content-Range", "Accept-Ranges",
 			"Cache-Control", "Last-Modified", "ETag",
 			"X-RateLimit-Limit", "X-RateLimit-Remaining", "Retry-After",
+			"Idempotent-Replayed", middleware.RequestIDHeader,
 		},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
@@ -347,6 +591,14 @@ func setupRoutes(
 	videoHandler *handlers.VideoHandler,
 	walletHandler *handlers.WalletHandler,
 	uploadHandler *handlers.UploadHandler,
+	giftHandler *handlers.GiftHandler,
+	blockHandler *handlers.BlockHandler,
+	notificationHandler *handlers.NotificationHandler,
+	adminHandler *handlers.AdminHandler,
+	videoReactionsHandler *handlers.VideoReactionsHandler,
+	searchHandler *handlers.SearchHandler,
+	healthHandler *handlers.HealthHandler,
+	webhookHandler *handlers.WebhookHandler,
 ) {
 	api := router.Group("/api/v1")
 
@@ -356,6 +608,7 @@ func setupRoutes(
 	auth := api.Group("/auth")
 	{
 		auth.POST("/sync", authHandler.SyncUser)
+		auth.POST("/check-phone", authHandler.CheckPhone)
 		auth.POST("/verify", authHandler.VerifyToken)
 	}
 
@@ -364,39 +617,56 @@ func setupRoutes(
 	{
 		protectedAuth.GET("/user", authHandler.GetCurrentUser)
 		protectedAuth.POST("/profile-sync", authHandler.SyncUserWithToken)
+		protectedAuth.POST("/recover", authHandler.RecoverAccount)
 	}
 
 	// ===============================
 	// PUBLIC ROUTES
 	// ===============================
 	public := api.Group("")
+	public.Use(middleware.OptionalAuth(firebaseService))
 	{
 		// VIDEO ENDPOINTS
 		public.GET("/videos", videoHandler.GetVideos)
 		public.GET("/videos/featured", videoHandler.GetFeaturedVideos)
 		public.GET("/videos/trending", videoHandler.GetTrendingVideos)
+		public.GET("/videos/trending/:period", videoHandler.GetTrendingInPeriod)
 		public.GET("/videos/popular", videoHandler.GetPopularVideos)
 		public.GET("/videos/:videoId", videoHandler.GetVideo)
 		public.GET("/videos/:videoId/qualities", videoHandler.GetVideoQualities)
+		public.GET("/videos/:videoId/stream", videoHandler.StreamVideo)
 		public.GET("/videos/:videoId/metrics", videoHandler.GetVideoMetrics)
 		public.POST("/videos/:videoId/views", videoHandler.IncrementViews)
 		public.GET("/users/:userId/videos", videoHandler.GetUserVideos)
 		public.GET("/videos/:videoId/comments", videoHandler.GetVideoComments)
+		public.GET("/comments/:commentId/replies", videoHandler.GetCommentReplies)
 
 		// SEARCH ENDPOINTS
 		public.GET("/videos/search", videoHandler.SearchVideos)
 		public.GET("/videos/search/popular", videoHandler.GetPopularSearchTerms)
+		public.GET("/videos/search/suggest", videoHandler.GetSearchSuggestions)
+		public.GET("/search", searchHandler.Search)
 
 		// BULK ENDPOINT
 		public.POST("/videos/bulk", videoHandler.GetVideosBulk)
 
+		// TAG ENDPOINTS
+		public.GET("/tags/trending", videoHandler.GetTrendingTags)
+		public.GET("/tags/:tag/videos", videoHandler.GetVideosByTag)
+
 		// USER ENDPOINTS
 		public.GET("/users/:userId", userHandler.GetUser)
 		public.GET("/users/:userId/stats", userHandler.GetUserStats)
 		public.GET("/users/:userId/followers", videoHandler.GetUserFollowers)
 		public.GET("/users/:userId/following", videoHandler.GetUserFollowing)
+		public.GET("/users/:userId/media-breakdown", videoHandler.GetUserMediaBreakdown)
 		public.GET("/users", userHandler.GetAllUsers)
 		public.GET("/users/search", userHandler.SearchUsers)
+		public.GET("/leaderboard/creators", userHandler.GetCreatorLeaderboard)
+		public.GET("/stats/public", videoHandler.GetPublicStats)
+
+		// GIFT CATALOG
+		public.GET("/gifts/catalog", giftHandler.GetGiftCatalog)
 	}
 
 	// ===============================
@@ -408,17 +678,27 @@ func setupRoutes(
 		// USER MANAGEMENT
 		protected.PUT("/users/:userId", userHandler.UpdateUser)
 		protected.DELETE("/users/:userId", userHandler.DeleteUser)
-		protected.POST("/users/:userId/status", userHandler.UpdateUserStatus)
 
 		// VIDEO FEATURES
 		protected.POST("/videos", videoHandler.CreateVideo)
+		protected.POST("/videos/validate", videoHandler.ValidateVideo)
 		protected.PUT("/videos/:videoId", videoHandler.UpdateVideo)
 		protected.DELETE("/videos/:videoId", videoHandler.DeleteVideo)
 		protected.POST("/videos/:videoId/like", videoHandler.LikeVideo)
+		protected.POST("/videos/:videoId/watch", videoHandler.RecordWatchTime)
 		protected.DELETE("/videos/:videoId/like", videoHandler.UnlikeVideo)
+		protected.GET("/videos/:videoId/like-status", videoHandler.GetLikeStatus)
+		protected.GET("/videos/:videoId/liked-by", videoHandler.GetVideoLikers)
 		protected.POST("/videos/:videoId/share", videoHandler.ShareVideo)
 		protected.GET("/videos/:videoId/counts", videoHandler.GetVideoCountsSummary)
 		protected.GET("/users/:userId/liked-videos", videoHandler.GetUserLikedVideos)
+		protected.POST("/videos/:videoId/save", videoHandler.SaveVideo)
+		protected.DELETE("/videos/:videoId/save", videoHandler.UnsaveVideo)
+		protected.GET("/users/:userId/saved", videoHandler.GetUserSavedVideos)
+		protected.POST("/videos/:videoId/not-interested", videoHandler.HideVideo)
+		protected.DELETE("/videos/:videoId/not-interested", videoHandler.UnhideVideo)
+		protected.GET("/users/me/hidden-videos", videoHandler.GetHiddenVideos)
+		protected.POST("/videos/:videoId/purchase", middleware.Idempotency(), videoHandler.PurchaseVideo)
 		protected.GET("/videos/:videoId/analytics", videoHandler.GetVideoAnalytics)
 
 		// SEARCH HISTORY ENDPOINTS
@@ -433,10 +713,35 @@ func setupRoutes(
 		// SOCIAL FEATURES
 		protected.POST("/users/:userId/follow", videoHandler.FollowUser)
 		protected.DELETE("/users/:userId/follow", videoHandler.UnfollowUser)
+		protected.POST("/users/follow-status", videoHandler.GetFollowStatus)
 		protected.GET("/feed/following", videoHandler.GetFollowingFeed)
+		protected.GET("/feed/following/new-count", videoHandler.GetFollowingNewCount)
+		protected.GET("/users/:userId/followers/mutual", videoHandler.GetMutualFollowers)
+		protected.GET("/users/:userId/mutuals", videoHandler.GetCommonFollowing)
+		protected.GET("/follow-requests", videoHandler.GetFollowRequests)
+		protected.POST("/follow-requests/:requestId/approve", videoHandler.ApproveFollowRequest)
+		protected.POST("/follow-requests/:requestId/reject", videoHandler.RejectFollowRequest)
+
+		// BLOCKING
+		protected.GET("/users/me/blocked", blockHandler.GetBlockedUsers)
+		protected.GET("/users/me/dashboard", userHandler.GetDashboard)
+		protected.GET("/users/me/audience", userHandler.GetMyAudience)
+		protected.GET("/users/me/best-times", userHandler.GetMyBestTimes)
+		protected.POST("/users/me/deactivate", userHandler.DeactivateAccount)
+		protected.POST("/users/me/reactivate", userHandler.ReactivateAccount)
+		protected.GET("/users/blocked", blockHandler.GetBlockedUsers)
+		protected.POST("/users/:userId/block", blockHandler.BlockUser)
+		protected.DELETE("/users/:userId/block", blockHandler.UnblockUser)
+
+		// NOTIFICATIONS
+		protected.GET("/notifications", notificationHandler.GetNotifications)
+		protected.GET("/notifications/unread-count", notificationHandler.GetUnreadCount)
+		protected.POST("/notifications/read", notificationHandler.MarkNotificationsRead)
 
 		// COMMENTS
 		protected.POST("/videos/:videoId/comments", videoHandler.CreateComment)
+		protected.POST("/videos/:videoId/comments/:commentId/pin", videoHandler.PinComment)
+		protected.DELETE("/videos/:videoId/comments/:commentId/pin", videoHandler.UnpinComment)
 		protected.DELETE("/comments/:commentId", videoHandler.DeleteComment)
 		protected.POST("/comments/:commentId/like", videoHandler.LikeComment)
 		protected.DELETE("/comments/:commentId/like", videoHandler.UnlikeComment)
@@ -449,12 +754,23 @@ func setupRoutes(
 
 		// WALLET
 		protected.GET("/wallet/:userId", walletHandler.GetWallet)
+		protected.GET("/wallet/:userId/balance", walletHandler.GetBalance)
 		protected.GET("/wallet/:userId/transactions", walletHandler.GetTransactions)
-		protected.POST("/wallet/:userId/purchase-request", walletHandler.CreatePurchaseRequest)
+		protected.POST("/wallet/:userId/purchase-request", middleware.Idempotency(), walletHandler.CreatePurchaseRequest)
+		protected.GET("/wallet/:userId/claim-daily", walletHandler.GetDailyClaimStatus)
+		protected.POST("/wallet/:userId/claim-daily", walletHandler.ClaimDaily)
+
+		// GIFTS
+		protected.POST("/gifts/send", middleware.Idempotency(), giftHandler.SendGift)
+		protected.GET("/gifts/transactions/:transactionId", giftHandler.GetGiftTransaction)
+		protected.GET("/users/:userId/gifts/history", giftHandler.GetGiftHistory)
+		protected.GET("/users/:userId/gifts/stats", giftHandler.GetGiftStats)
+		protected.GET("/users/me/videos/top-gifted", giftHandler.GetMyTopGiftedVideos)
 
 		// UPLOAD
 		protected.POST("/upload", uploadHandler.UploadFile)
 		protected.POST("/upload/batch", uploadHandler.BatchUploadFiles)
+		protected.POST("/upload/presign", uploadHandler.PresignUpload)
 		protected.GET("/upload/health", uploadHandler.HealthCheck)
 
 		// ===============================
@@ -463,62 +779,34 @@ func setupRoutes(
 		videoReactions := protected.Group("/video-reactions")
 		{
 			// Chat management
-			videoReactions.GET("/chats", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Get user's video reaction chats - TODO: Implement handler"})
-			})
-			videoReactions.POST("/chats", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Create new video reaction chat - TODO: Implement handler"})
-			})
-			videoReactions.GET("/chats/:chatId", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Get specific chat - TODO: Implement handler"})
-			})
-			videoReactions.DELETE("/chats/:chatId", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Delete chat - TODO: Implement handler"})
-			})
+			videoReactions.GET("/chats", videoReactionsHandler.GetUserChats)
+			videoReactions.POST("/chats", videoReactionsHandler.CreateVideoReactionChat)
+			videoReactions.GET("/chats/:chatId", videoReactionsHandler.GetChatByID)
+			videoReactions.DELETE("/chats/:chatId", videoReactionsHandler.DeleteChat)
+			videoReactions.GET("/chats/:chatId/export", videoReactionsHandler.ExportChat)
 
 			// Message management
-			videoReactions.GET("/chats/:chatId/messages", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Get chat messages - TODO: Implement handler"})
-			})
-			videoReactions.POST("/chats/:chatId/messages", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Send message - TODO: Implement handler"})
-			})
-			videoReactions.PUT("/chats/:chatId/messages/:messageId", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Edit message - TODO: Implement handler"})
-			})
-			videoReactions.DELETE("/chats/:chatId/messages/:messageId", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Delete message - TODO: Implement handler"})
-			})
+			videoReactions.GET("/chats/:chatId/messages", videoReactionsHandler.GetChatMessages)
+			videoReactions.POST("/chats/:chatId/messages", videoReactionsHandler.SendMessage)
+			videoReactions.PUT("/chats/:chatId/messages/:messageId", videoReactionsHandler.EditMessage)
+			videoReactions.DELETE("/chats/:chatId/messages/:messageId", videoReactionsHandler.DeleteMessage)
 
 			// Chat actions
-			videoReactions.POST("/chats/:chatId/read", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Mark chat as read - TODO: Implement handler"})
-			})
-			videoReactions.POST("/chats/:chatId/pin", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Toggle chat pin - TODO: Implement handler"})
-			})
-			videoReactions.POST("/chats/:chatId/archive", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Toggle chat archive - TODO: Implement handler"})
-			})
-			videoReactions.POST("/chats/:chatId/mute", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Toggle chat mute - TODO: Implement handler"})
-			})
+			videoReactions.POST("/chats/:chatId/read", videoReactionsHandler.MarkChatAsRead)
+			videoReactions.POST("/chats/:chatId/pin", videoReactionsHandler.ToggleChatPin)
+			videoReactions.POST("/chats/:chatId/archive", videoReactionsHandler.ToggleChatArchive)
+			videoReactions.POST("/chats/:chatId/mute", videoReactionsHandler.ToggleChatMute)
 
 			// Message actions
-			videoReactions.POST("/chats/:chatId/messages/:messageId/pin", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Toggle message pin - TODO: Implement handler"})
-			})
+			videoReactions.POST("/chats/:chatId/messages/:messageId/pin", videoReactionsHandler.ToggleMessagePin)
+			videoReactions.POST("/chats/:chatId/messages/:messageId/report", videoReactionsHandler.ReportMessage)
 			videoReactions.GET("/chats/:chatId/messages/pinned", func(c *gin.Context) {
 				c.JSON(200, gin.H{"message": "Get pinned messages - TODO: Implement handler"})
 			})
-			videoReactions.GET("/chats/:chatId/messages/search", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Search messages - TODO: Implement handler"})
-			})
+			videoReactions.GET("/chats/:chatId/messages/search", videoReactionsHandler.SearchMessages)
 
 			// Chat settings
-			videoReactions.PUT("/chats/:chatId/settings", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Update chat settings - TODO: Implement handler"})
-			})
+			videoReactions.PUT("/chats/:chatId/settings", videoReactionsHandler.UpdateChatSettings)
 		}
 
 		// ===============================
@@ -527,26 +815,60 @@ func setupRoutes(
 		admin := protected.Group("")
 		admin.Use(middleware.AdminOnly())
 		{
+			moderateContent := middleware.RequirePermission(models.PermissionModerateContent)
+			manageUsers := middleware.RequirePermission(models.PermissionManageUsers)
+			manageWallet := middleware.RequirePermission(models.PermissionManageWallet)
+			viewReports := middleware.RequirePermission(models.PermissionViewReports)
+			superAdmin := middleware.RequirePermission(models.PermissionSuperAdmin)
+
 			// VIDEO MODERATION
-			admin.POST("/admin/videos/:videoId/featured", videoHandler.ToggleFeatured)
-			admin.POST("/admin/videos/:videoId/active", videoHandler.ToggleActive)
-			admin.POST("/admin/videos/:videoId/verified", videoHandler.ToggleVerified)
+			admin.POST("/admin/videos/:videoId/featured", moderateContent, videoHandler.ToggleFeatured)
+			admin.POST("/admin/videos/:videoId/active", moderateContent, videoHandler.ToggleActive)
+			admin.POST("/admin/videos/:videoId/verified", moderateContent, videoHandler.ToggleVerified)
+			admin.GET("/admin/videos/pending", moderateContent, videoHandler.GetPendingModeration)
+			admin.POST("/admin/videos/bulk-moderate", moderateContent, videoHandler.BulkModerateVideos)
+			admin.GET("/admin/comments/flagged", moderateContent, videoHandler.GetFlaggedComments)
+			admin.GET("/admin/messages/reported", moderateContent, videoReactionsHandler.GetReportedMessages)
 
 			// PERFORMANCE
-			admin.POST("/admin/videos/batch-update-counts", videoHandler.BatchUpdateCounts)
+			admin.POST("/admin/videos/batch-update-counts", superAdmin, videoHandler.BatchUpdateCounts)
 
 			// USER MANAGEMENT
-			admin.GET("/admin/users", userHandler.GetAllUsers)
-			admin.POST("/admin/users/:userId/status", userHandler.UpdateUserStatus)
+			admin.GET("/admin/users", manageUsers, userHandler.GetAllUsers)
+			admin.GET("/admin/users/demographics", manageUsers, userHandler.GetDemographicsSummary)
+			admin.POST("/admin/users/:userId/status", manageUsers, userHandler.UpdateUserStatus)
+			admin.POST("/admin/users/:userId/role", superAdmin, userHandler.ChangeUserRole)
 
 			// WALLET MANAGEMENT
-			admin.POST("/admin/wallet/:userId/add-coins", walletHandler.AddCoins)
-			admin.GET("/admin/purchase-requests", walletHandler.GetPendingPurchases)
-			admin.POST("/admin/purchase-requests/:requestId/approve", walletHandler.ApprovePurchase)
-			admin.POST("/admin/purchase-requests/:requestId/reject", walletHandler.RejectPurchase)
+			admin.POST("/admin/wallet/:userId/add-coins", manageWallet, walletHandler.AddCoins)
+			admin.GET("/admin/purchase-requests", manageWallet, walletHandler.GetPendingPurchases)
+			admin.POST("/admin/purchase-requests/:requestId/approve", manageWallet, walletHandler.ApprovePurchase)
+			admin.POST("/admin/purchase-requests/:requestId/reject", manageWallet, walletHandler.RejectPurchase)
+
+			// GIFT MANAGEMENT
+			admin.GET("/admin/gifts/commission-summary", viewReports, giftHandler.GetPlatformCommissionSummary)
+			admin.GET("/admin/gifts/top-senders", viewReports, giftHandler.GetTopGiftSenders)
+			admin.GET("/admin/gifts/top-receivers", viewReports, giftHandler.GetTopGiftReceivers)
+
+			// REVENUE
+			admin.GET("/admin/revenue/commissions", viewReports, giftHandler.GetPlatformRevenue)
+
+			// ADMIN PERMISSIONS (super admins only)
+			admin.GET("/admin/admins", superAdmin, adminHandler.ListAdmins)
+			admin.GET("/admin/admins/:userId/permissions", superAdmin, adminHandler.GetPermissions)
+			admin.POST("/admin/admins/:userId/permissions", superAdmin, adminHandler.GrantPermission)
+			admin.DELETE("/admin/admins/:userId/permissions/:permission", superAdmin, adminHandler.RevokePermission)
+			admin.GET("/admin/audit-log", superAdmin, adminHandler.GetAuditLog)
+
+			// WEBHOOKS
+			admin.GET("/admin/webhooks", superAdmin, webhookHandler.ListWebhooks)
+			admin.POST("/admin/webhooks", superAdmin, webhookHandler.CreateWebhook)
+			admin.PUT("/admin/webhooks/:webhookId", superAdmin, webhookHandler.UpdateWebhook)
+			admin.DELETE("/admin/webhooks/:webhookId", superAdmin, webhookHandler.DeleteWebhook)
+			admin.GET("/admin/webhooks/:webhookId/deliveries", superAdmin, webhookHandler.GetDeliveries)
 
 			// PLATFORM STATS
-			admin.GET("/admin/stats", func(c *gin.Context) {
+			admin.GET("/admin/stats", viewReports, func(c *gin.Context) {
 				c.Header("Cache-Control", "public, max-age=300")
 				dbStats := database.Stats()
 
@@ -583,40 +905,10 @@ func setupRoutes(
 				})
 			})
 
-			// SYSTEM HEALTH
-			admin.GET("/admin/health", func(c *gin.Context) {
-				c.Header("Cache-Control", "no-cache")
-				dbStats := database.Stats()
+			admin.GET("/stats/media-breakdown", viewReports, videoHandler.GetMediaBreakdown)
 
-				c.JSON(200, gin.H{
-					"database": gin.H{
-						"status":           "connected",
-						"open_connections": dbStats.OpenConnections,
-						"in_use":           dbStats.InUse,
-						"idle":             dbStats.Idle,
-					},
-					"firebase": gin.H{"status": "initialized"},
-					"storage":  gin.H{"status": "connected", "type": "cloudflare-r2"},
-					"search": gin.H{
-						"status":            "enabled",
-						"type":              "fuzzy",
-						"trigram_extension": true,
-						"history_enabled":   true,
-					},
-					"chat": gin.H{
-						"status":         "enabled",
-						"type":           "websocket",
-						"real_time":      true,
-						"tables_created": true,
-					},
-					"app": gin.H{
-						"name":     "video-social-with-reactions",
-						"version":  "2.1.0",
-						"status":   "healthy",
-						"features": []string{"videos", "wallet", "social", "fuzzy-search", "history", "video-reactions", "websocket-chat"},
-					},
-				})
-			})
+			// SYSTEM HEALTH
+			admin.GET("/admin/health", viewReports, healthHandler.GetSystemHealth)
 		}
 	}
 